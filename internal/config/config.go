@@ -4,12 +4,18 @@ package config
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 )
 
 // Config holds the application configuration
 type Config struct {
+	Version int `json:"version"` // Config file format version, see CurrentVersion
+
 	ServerID           string        `json:"server_id"`            // Plex server ID for building playback URLs
 	PlexServerAddr     string        `json:"plex_server_addr"`     // Plex server address for API calls
 	PlexServerName     string        `json:"plex_server_name"`     // Plex server name for display
@@ -18,6 +24,138 @@ type Config struct {
 	SelectedPlayerName string        `json:"selected_player_name"` // Selected player name for display
 	PlexLibraryName    string        `json:"plex_library_name"`    // Music library name for display
 	PlexLibraries      []PlexLibrary `json:"plex_libraries"`       // List of Plex libraries
+
+	PlexServerURI string `json:"plex_server_uri,omitempty"` // Full URI of the chosen server connection, including scheme
+
+	RequestTimeoutSeconds int `json:"request_timeout_seconds,omitempty"` // Timeout for Plex API requests, defaults to 15s
+
+	LastPanelMode     string `json:"last_panel_mode,omitempty"`     // Panel that was open when the app last quit
+	LastSelectedIndex int    `json:"last_selected_index,omitempty"` // Selected list index in that panel
+
+	ScrobbleEnabled  bool   `json:"scrobble_enabled,omitempty"`   // Send played tracks to last.fm
+	LastFMAPIKey     string `json:"lastfm_api_key,omitempty"`     // last.fm API account key
+	LastFMAPISecret  string `json:"lastfm_api_secret,omitempty"`  // last.fm API account shared secret
+	LastFMSessionKey string `json:"lastfm_session_key,omitempty"` // last.fm session key for the user being scrobbled
+
+	DiscordPresence bool   `json:"discord_presence,omitempty"`  // Show the current track as Discord Rich Presence
+	DiscordClientID string `json:"discord_client_id,omitempty"` // Discord application ID used for the presence
+
+	CrossfadeSeconds int `json:"crossfade_seconds,omitempty"` // Preferred crossfade duration, 0 for gapless
+
+	Theme string `json:"theme,omitempty"` // Built-in color scheme: default, mono or solarized
+
+	LogMaxSizeMB  int `json:"log_max_size_mb,omitempty"` // Debug log size that triggers rotation, defaults to 5MB
+	LogMaxBackups int `json:"log_max_backups,omitempty"` // Rotated debug logs to keep, defaults to 3
+
+	ArtistSort string `json:"artist_sort,omitempty"` // Artist list order: title, added or plays
+	AlbumSort  string `json:"album_sort,omitempty"`  // Album list order: artist, title, year, added or plays
+
+	DefaultShuffle *bool `json:"default_shuffle,omitempty"` // Shuffle state at launch, the last one used; on when unset
+
+	VolumeStep int `json:"volume_step,omitempty"` // Percent the volume keys change the volume by, defaults to 5
+
+	AlwaysResume bool `json:"always_resume,omitempty"` // Resume partly played items without asking
+
+	PlainFavoriteTypes bool `json:"plain_favorite_types,omitempty"` // Show favorite types as text only, for terminals without emoji
+
+	TimelinePollSeconds int `json:"timeline_poll_seconds,omitempty"` // Shortest time in seconds between polls of the player's timeline, defaults to 2
+
+	// Right-hand panels: each is shown unless set to false, Now Playing on top
+	// unless controls_first is set
+	ShowStatusPanel   *bool `json:"show_status_panel,omitempty"`
+	ShowControlsPanel *bool `json:"show_controls_panel,omitempty"`
+	ControlsFirst     bool  `json:"controls_first,omitempty"`
+
+	CompactView bool `json:"compact_view,omitempty"` // Show only a one-line status instead of the panels
+
+	// File or named pipe the playback state is written to for status bars,
+	// and the line written, e.g. "{state} {artist} - {title}"
+	StatusOutputPath   string `json:"status_output_path,omitempty"`
+	StatusOutputFormat string `json:"status_output_format,omitempty"`
+
+	// Local HTTP control API. It listens on localhost unless http_api_bind
+	// says otherwise; it has no authentication, so anyone who can reach it
+	// can control the player.
+	HTTPAPIEnabled bool   `json:"http_api_enabled,omitempty"`
+	HTTPAPIPort    int    `json:"http_api_port,omitempty"` // Defaults to 32580
+	HTTPAPIBind    string `json:"http_api_bind,omitempty"` // Address to listen on, defaults to 127.0.0.1
+
+	ASCIIProgressBar bool `json:"ascii_progress_bar,omitempty"` // Draw progress bars with # and - for terminals without block glyphs
+}
+
+// DefaultVolumeStep is the volume key step used when none is configured
+const DefaultVolumeStep = 5
+
+// DefaultHTTPAPIPort is the port the HTTP control API listens on when none is configured
+const DefaultHTTPAPIPort = 32580
+
+// DefaultTimelinePollSeconds is the timeline poll interval used when none is configured
+const DefaultTimelinePollSeconds = 2
+
+// RequestTimeout returns the configured Plex request timeout, or zero to use the default
+func (c *Config) RequestTimeout() time.Duration {
+	if c.RequestTimeoutSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(c.RequestTimeoutSeconds) * time.Second
+}
+
+// ShuffleOnLaunch returns the shuffle state to start with, defaulting to on
+func (c *Config) ShuffleOnLaunch() bool {
+	if c.DefaultShuffle == nil {
+		return true
+	}
+	return *c.DefaultShuffle
+}
+
+// StatusPanelShown reports whether the Now Playing panel is shown, defaulting to yes
+func (c *Config) StatusPanelShown() bool {
+	return c.ShowStatusPanel == nil || *c.ShowStatusPanel
+}
+
+// ControlsPanelShown reports whether the Controls panel is shown, defaulting to yes
+func (c *Config) ControlsPanelShown() bool {
+	return c.ShowControlsPanel == nil || *c.ShowControlsPanel
+}
+
+// VolumeStepPercent returns how far the volume keys move the volume
+func (c *Config) VolumeStepPercent() int {
+	if c.VolumeStep <= 0 {
+		return DefaultVolumeStep
+	}
+	if c.VolumeStep > 100 {
+		return 100
+	}
+	return c.VolumeStep
+}
+
+// TimelinePollInterval returns how often the player's timeline is polled
+func (c *Config) TimelinePollInterval() time.Duration {
+	if c.TimelinePollSeconds <= 0 {
+		return DefaultTimelinePollSeconds * time.Second
+	}
+	return time.Duration(c.TimelinePollSeconds) * time.Second
+}
+
+// HTTPAPIAddress returns the address the HTTP control API listens on
+func (c *Config) HTTPAPIAddress() string {
+	bind, port := c.HTTPAPIBind, c.HTTPAPIPort
+	if bind == "" {
+		bind = "127.0.0.1"
+	}
+	if port <= 0 {
+		port = DefaultHTTPAPIPort
+	}
+	return net.JoinHostPort(bind, strconv.Itoa(port))
+}
+
+// ServerURL returns the server to send library requests to: the stored
+// connection URI when there is one, otherwise the plain address:port
+func (c *Config) ServerURL() string {
+	if c.PlexServerURI != "" {
+		return c.PlexServerURI
+	}
+	return c.PlexServerAddr
 }
 
 // PlexLibrary represents a Plex media library
@@ -25,6 +163,17 @@ type PlexLibrary struct {
 	Key   string `json:"key"`
 	Title string `json:"title"`
 	Type  string `json:"type"`
+
+	// Kind is what a music library holds: "music", "audiobooks" or
+	// "podcasts". It is detected when the libraries are fetched; empty
+	// means music.
+	Kind string `json:"kind,omitempty"`
+
+	// ArtistType and AlbumType are the Plex metadata types listed as the
+	// library's artists and albums, for libraries whose agent files them
+	// under other types than music. 0 keeps the default of its kind.
+	ArtistType int `json:"artist_type,omitempty"`
+	AlbumType  int `json:"album_type,omitempty"`
 }
 
 // Manager handles configuration loading and saving
@@ -32,6 +181,15 @@ type Manager struct {
 	configPath   string
 	config       *Config
 	UsingDefault bool
+
+	// Migrations describes the upgrades applied to the config file by Load,
+	// so they can be logged once the logger is running
+	Migrations []string
+
+	// CorruptBackup is where Load moved a config file it could not parse
+	// before starting over with the defaults, empty if it parsed fine
+	CorruptBackup string
+	CorruptErr    error // Why the moved config file could not be parsed
 }
 
 // NewManager creates a new configuration manager
@@ -65,7 +223,24 @@ func (m *Manager) Load() (*Config, error) {
 
 	var cfg Config
 	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, err
+		// Keep the broken file for the user to fix and start with the defaults
+		// rather than refusing to launch
+		backup, backupErr := BackupCorruptFile(m.configPath)
+		if backupErr != nil {
+			return nil, fmt.Errorf("config is not valid JSON (%v) and could not be backed up: %w", err, backupErr)
+		}
+		m.CorruptBackup = backup
+		m.CorruptErr = err
+		m.UsingDefault = true
+		return m.createDefaultConfig()
+	}
+
+	// Upgrade files written by older versions and save the result so the
+	// migration only runs once
+	if m.Migrations = migrate(&cfg); len(m.Migrations) > 0 {
+		if err := m.Save(&cfg); err != nil {
+			return nil, err
+		}
 	}
 
 	m.config = &cfg
@@ -88,20 +263,34 @@ func (m *Manager) GetConfig() *Config {
 	return m.config
 }
 
+// Placeholders written to a new config until a server, player and library are selected
+const (
+	PlaceholderServer  = "SELECT_SERVER"
+	PlaceholderPlayer  = "SELECT_PLAYER"
+	PlaceholderLibrary = "SELECT_LIBRARY"
+)
+
+// IsPlaceholder reports whether a config value is still one of the defaults
+// written before anything was selected
+func IsPlaceholder(value string) bool {
+	return value == PlaceholderServer || value == PlaceholderPlayer || value == PlaceholderLibrary
+}
+
 // createDefaultConfig creates a new default configuration
 func (m *Manager) createDefaultConfig() (*Config, error) {
 	defaultCfg := &Config{
-		ServerID:           "SELECT_SERVER",
+		Version:            CurrentVersion,
+		ServerID:           PlaceholderServer,
 		PlexServerAddr:     "127.0.0.1:32400",
-		PlexServerName:     "SELECT_SERVER",
+		PlexServerName:     PlaceholderServer,
 		PlexLibraryID:      "15",
 		SelectedPlayer:     "127.0.0.1",
-		SelectedPlayerName: "SELECT_PLAYER",
-		PlexLibraryName:    "SELECT_LIBRARY",
+		SelectedPlayerName: PlaceholderPlayer,
+		PlexLibraryName:    PlaceholderLibrary,
 		PlexLibraries: []PlexLibrary{
 			{
 				Key:   "15",
-				Title: "SELECT_LIBRARY",
+				Title: PlaceholderLibrary,
 				Type:  "artist",
 			},
 		},
@@ -114,6 +303,16 @@ func (m *Manager) createDefaultConfig() (*Config, error) {
 	return defaultCfg, nil
 }
 
+// BackupCorruptFile moves a file that could not be parsed to <path>.bak,
+// replacing any earlier backup, and returns the backup's path
+func BackupCorruptFile(path string) (string, error) {
+	backup := path + ".bak"
+	if err := os.Rename(path, backup); err != nil {
+		return "", err
+	}
+	return backup, nil
+}
+
 // GetConfigPath returns the path to the configuration file
 func (m *Manager) GetConfigPath() string {
 	return m.configPath
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadBrokenConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	broken := []byte(`{"server_id": "abc", "plex_library_id": `)
+	if err := os.WriteFile(path, broken, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager, err := NewManager(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := manager.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if !manager.UsingDefault || cfg.ServerID != PlaceholderServer {
+		t.Errorf("got server %q, want the default config", cfg.ServerID)
+	}
+	if manager.CorruptBackup != path+".bak" || manager.CorruptErr == nil {
+		t.Errorf("backup %q, error %v; want %s.bak and the parse error", manager.CorruptBackup, manager.CorruptErr, path)
+	}
+	backup, err := os.ReadFile(path + ".bak")
+	if err != nil {
+		t.Fatalf("reading the backup: %v", err)
+	}
+	if string(backup) != string(broken) {
+		t.Errorf("backup holds %q, want the broken file", backup)
+	}
+
+	// The defaults replaced the broken file, so the next launch loads cleanly
+	manager, err = NewManager(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.Load(); err != nil {
+		t.Fatalf("Load after recovering: %v", err)
+	}
+	if manager.UsingDefault || manager.CorruptBackup != "" {
+		t.Errorf("second load used the defaults again")
+	}
+}
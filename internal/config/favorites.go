@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"os"
+	"strings"
 	"time"
 
 	"plexamp-tui/internal/database"
@@ -17,6 +18,7 @@ type FavoriteItem struct {
 	Type        string    `json:"type"`
 	MetadataKey string    `json:"key"`
 	CreatedAt   time.Time `json:"created_at"`
+	Tags        []string  `json:"tags,omitempty"` // Free-form labels such as "workout" or "focus"
 }
 
 // Favorites holds the list of favorite items
@@ -39,16 +41,86 @@ func NewFavoritesManager(db *database.Database) (*FavoritesManager, error) {
 	return FavsManager, nil
 }
 
-// Add adds a new favorite item
+// RecoveredFrom returns where a damaged database was moved when an empty one
+// replaced it at startup, or "" when it opened normally
+func (fm *FavoritesManager) RecoveredFrom() string {
+	return fm.db.CorruptBackup
+}
+
+// Add adds a new favorite item. Adding an existing favorite renames it, and
+// replaces its tags when the item has any.
 func (fm *FavoritesManager) Add(item FavoriteItem) error {
 	_, err := fm.db.DB.Exec(`
-		INSERT INTO favorites (name, type, metadata_key)
-		VALUES (?, ?, ?)
-		ON CONFLICT(type, metadata_key) DO UPDATE SET name = excluded.name
-	`, item.Name, item.Type, item.MetadataKey)
+		INSERT INTO favorites (name, type, metadata_key, sort_order, tags)
+		VALUES (?, ?, ?, (SELECT COALESCE(MAX(sort_order), -1) + 1 FROM favorites), ?)
+		ON CONFLICT(type, metadata_key) DO UPDATE SET
+			name = excluded.name,
+			tags = CASE WHEN excluded.tags = '' THEN favorites.tags ELSE excluded.tags END
+	`, item.Name, item.Type, item.MetadataKey, joinTags(item.Tags))
+	return err
+}
+
+// SetTags replaces the tags of the favorite with the given id
+func (fm *FavoritesManager) SetTags(id int, tags []string) error {
+	_, err := fm.db.DB.Exec(`UPDATE favorites SET tags = ? WHERE id = ?`, joinTags(tags), id)
 	return err
 }
 
+// ListByTag returns the favorites carrying a tag, in list order. Tags are
+// matched regardless of case.
+func (fm *FavoritesManager) ListByTag(tag string) ([]FavoriteItem, error) {
+	items, err := fm.List()
+	if err != nil {
+		return nil, err
+	}
+	var tagged []FavoriteItem
+	for _, item := range items {
+		if item.HasTag(tag) {
+			tagged = append(tagged, item)
+		}
+	}
+	return tagged, nil
+}
+
+// HasTag reports whether the favorite carries a tag, regardless of case
+func (item FavoriteItem) HasTag(tag string) bool {
+	for _, t := range item.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTags splits a comma separated list of tags, dropping empty and
+// repeated ones
+func ParseTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || (FavoriteItem{Tags: tags}).HasTag(tag) {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// joinTags stores tags as a comma separated list, the form ParseTags reads
+func joinTags(tags []string) string {
+	return strings.Join(ParseTags(strings.Join(tags, ",")), ",")
+}
+
+// Has reports whether a favorite with the given type and metadata key exists
+func (fm *FavoritesManager) Has(itemType, metadataKey string) (bool, error) {
+	var count int
+	err := fm.db.DB.QueryRow(`
+		SELECT COUNT(*) FROM favorites
+		WHERE type = ? AND metadata_key = ?
+	`, itemType, metadataKey).Scan(&count)
+	return count > 0, err
+}
+
 // Remove removes a favorite item by type and metadata key
 func (fm *FavoritesManager) Remove(itemType, metadataKey string) error {
 	_, err := fm.db.DB.Exec(`
@@ -61,9 +133,9 @@ func (fm *FavoritesManager) Remove(itemType, metadataKey string) error {
 // List returns all favorite items
 func (fm *FavoritesManager) List() ([]FavoriteItem, error) {
 	rows, err := fm.db.DB.Query(`
-		SELECT id, name, type, metadata_key, created_at 
+		SELECT id, name, type, metadata_key, created_at, tags
 		FROM favorites 
-		ORDER BY created_at DESC
+		ORDER BY sort_order, created_at DESC
 	`)
 	if err != nil {
 		return nil, err
@@ -73,15 +145,68 @@ func (fm *FavoritesManager) List() ([]FavoriteItem, error) {
 	var items []FavoriteItem
 	for rows.Next() {
 		var item FavoriteItem
-		if err := rows.Scan(&item.ID, &item.Name, &item.Type, &item.MetadataKey, &item.CreatedAt); err != nil {
+		var tags string
+		if err := rows.Scan(&item.ID, &item.Name, &item.Type, &item.MetadataKey, &item.CreatedAt, &tags); err != nil {
 			return nil, err
 		}
+		item.Tags = ParseTags(tags)
 		items = append(items, item)
 	}
 
 	return items, rows.Err()
 }
 
+// Reorder moves the favorite with the given id to newPos (zero based) in the list
+func (fm *FavoritesManager) Reorder(id int, newPos int) error {
+	tx, err := fm.db.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT id FROM favorites ORDER BY sort_order, created_at DESC`)
+	if err != nil {
+		return err
+	}
+	var ids []int
+	for rows.Next() {
+		var rowID int
+		if err := rows.Scan(&rowID); err != nil {
+			rows.Close()
+			return err
+		}
+		if rowID != id {
+			ids = append(ids, rowID)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if newPos < 0 {
+		newPos = 0
+	}
+	if newPos > len(ids) {
+		newPos = len(ids)
+	}
+	ids = append(ids[:newPos], append([]int{id}, ids[newPos:]...)...)
+
+	stmt, err := tx.Prepare(`UPDATE favorites SET sort_order = ? WHERE id = ?`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for pos, rowID := range ids {
+		if _, err := stmt.Exec(pos, rowID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
 // Save is kept for backward compatibility but now uses the database
 func (fm *FavoritesManager) Save(favorites *Favorites) error {
 	// This is a no-op now since we're using the database directly
@@ -134,16 +259,16 @@ func (fm *FavoritesManager) MigrateFromJSON(jsonPath string) error {
 	defer tx.Rollback()
 
 	stmt, err := tx.Prepare(`
-		INSERT INTO favorites (name, type, metadata_key, created_at)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO favorites (name, type, metadata_key, created_at, sort_order)
+		VALUES (?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	for _, item := range favs.Items {
-		if _, err := stmt.Exec(item.Name, item.Type, item.MetadataKey, time.Now()); err != nil {
+	for i, item := range favs.Items {
+		if _, err := stmt.Exec(item.Name, item.Type, item.MetadataKey, time.Now(), i); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,126 @@
+// internal/config/favorites_io.go
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// m3uTagPrefix marks the comment line carrying the favorite type and metadata key
+const m3uTagPrefix = "#PLEXAMP-TUI:"
+
+// Export writes all favorites to path. Paths ending in .m3u or .m3u8 are
+// written as extended M3U, anything else as JSON in the Favorites shape.
+func (fm *FavoritesManager) Export(path string) (int, error) {
+	items, err := fm.List()
+	if err != nil {
+		return 0, err
+	}
+
+	var data []byte
+	if isM3U(path) {
+		data = []byte(encodeM3U(items))
+	} else {
+		data, err = json.MarshalIndent(Favorites{Items: items}, "", "  ")
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return 0, err
+	}
+	return len(items), nil
+}
+
+// Import reads favorites from a file written by Export and adds each entry.
+// Entries that already exist are updated in place rather than duplicated.
+func (fm *FavoritesManager) Import(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var items []FavoriteItem
+	if isM3U(path) {
+		items, err = decodeM3U(string(data))
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		var favs Favorites
+		if err := json.Unmarshal(data, &favs); err != nil {
+			return 0, err
+		}
+		items = favs.Items
+	}
+
+	for _, item := range items {
+		if item.Name == "" || item.Type == "" || item.MetadataKey == "" {
+			continue
+		}
+		if err := fm.Add(item); err != nil {
+			return 0, err
+		}
+	}
+	return len(items), nil
+}
+
+func isM3U(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".m3u" || ext == ".m3u8"
+}
+
+// encodeM3U renders favorites as an extended M3U playlist
+func encodeM3U(items []FavoriteItem) string {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	for _, item := range items {
+		fmt.Fprintf(&b, "#EXTINF:-1,%s\n", item.Name)
+		fmt.Fprintf(&b, "%stype=%s;key=%s\n", m3uTagPrefix, item.Type, item.MetadataKey)
+		fmt.Fprintf(&b, "/library/metadata/%s\n", item.MetadataKey)
+	}
+	return b.String()
+}
+
+// decodeM3U parses favorites from an extended M3U playlist written by encodeM3U
+func decodeM3U(data string) ([]FavoriteItem, error) {
+	var items []FavoriteItem
+	var current FavoriteItem
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "#EXTINF:"):
+			current = FavoriteItem{}
+			if idx := strings.Index(line, ","); idx >= 0 {
+				current.Name = line[idx+1:]
+			}
+		case strings.HasPrefix(line, m3uTagPrefix):
+			for _, field := range strings.Split(strings.TrimPrefix(line, m3uTagPrefix), ";") {
+				k, v, _ := strings.Cut(field, "=")
+				switch k {
+				case "type":
+					current.Type = v
+				case "key":
+					current.MetadataKey = v
+				}
+			}
+		case line == "" || strings.HasPrefix(line, "#"):
+			// Skip blank lines and comments we don't understand
+		default:
+			// The location line ends an entry
+			if current.Name != "" && current.Type != "" && current.MetadataKey != "" {
+				items = append(items, current)
+			}
+			current = FavoriteItem{}
+		}
+	}
+
+	return items, scanner.Err()
+}
@@ -0,0 +1,87 @@
+// internal/config/history.go
+package config
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"plexamp-tui/internal/database"
+)
+
+// HistoryItem represents a single entry in the play history
+type HistoryItem struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Type        string    `json:"type"`
+	MetadataKey string    `json:"key"`
+	PlayedAt    time.Time `json:"played_at"`
+}
+
+// HistoryManager handles the play history
+type HistoryManager struct {
+	db *database.Database
+}
+
+// NewHistoryManager creates a new HistoryManager
+func NewHistoryManager(db *database.Database) (*HistoryManager, error) {
+	return &HistoryManager{
+		db: db,
+	}, nil
+}
+
+// Record adds a play to the history. Playing the same item twice in a row
+// only refreshes the most recent entry instead of adding a new one.
+func (hm *HistoryManager) Record(item HistoryItem) error {
+	var lastID int
+	var lastType, lastKey string
+	err := hm.db.DB.QueryRow(`
+		SELECT id, type, metadata_key
+		FROM play_history
+		ORDER BY played_at DESC, id DESC
+		LIMIT 1
+	`).Scan(&lastID, &lastType, &lastKey)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if err == nil && lastType == item.Type && lastKey == item.MetadataKey {
+		_, err = hm.db.DB.Exec(`
+			UPDATE play_history
+			SET name = ?, played_at = ?
+			WHERE id = ?
+		`, item.Name, time.Now(), lastID)
+		return err
+	}
+
+	_, err = hm.db.DB.Exec(`
+		INSERT INTO play_history (name, type, metadata_key, played_at)
+		VALUES (?, ?, ?, ?)
+	`, item.Name, item.Type, item.MetadataKey, time.Now())
+	return err
+}
+
+// Recent returns the most recently played items, newest first
+func (hm *HistoryManager) Recent(limit int) ([]HistoryItem, error) {
+	rows, err := hm.db.DB.Query(`
+		SELECT id, name, type, metadata_key, played_at
+		FROM play_history
+		ORDER BY played_at DESC, id DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []HistoryItem
+	for rows.Next() {
+		var item HistoryItem
+		if err := rows.Scan(&item.ID, &item.Name, &item.Type, &item.MetadataKey, &item.PlayedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Actions that can be bound in the keymap file
+const (
+	ActionPlayPause    = "play_pause"
+	ActionStop         = "stop"
+	ActionNext         = "next"
+	ActionPrevious     = "previous"
+	ActionVolumeUp     = "volume_up"
+	ActionVolumeDown   = "volume_down"
+	ActionSetVolume    = "set_volume"
+	ActionMute         = "mute"
+	ActionSeekBack     = "seek_back"
+	ActionSeekForward  = "seek_forward"
+	ActionShuffle      = "shuffle"
+	ActionRepeat       = "repeat"
+	ActionCrossfade    = "crossfade"
+	ActionSleepTimer   = "sleep_timer"
+	ActionCycleLibrary = "cycle_library"
+	ActionCycleTheme   = "cycle_theme"
+	ActionRefresh      = "refresh"
+	ActionArtists      = "artists"
+	ActionAlbums       = "albums"
+	ActionPlaylists    = "playlists"
+	ActionHistory      = "history"
+	ActionProfiles     = "profiles"
+	ActionServers      = "servers"
+	ActionPlayers      = "players"
+	ActionGenres       = "genres"
+	ActionLibraries    = "libraries"
+	ActionQueue        = "queue"
+	ActionGoToAlbum    = "go_to_album"
+	ActionGoToArtist   = "go_to_artist"
+	ActionQuit         = "quit"
+	ActionHelp         = "help"
+	ActionSpeedUp      = "speed_up"
+	ActionSpeedDown    = "speed_down"
+	ActionSpeedReset   = "speed_reset"
+	ActionLyrics       = "lyrics"
+	ActionPollInterval = "poll_interval"
+	ActionPanelLayout  = "panel_layout"
+	ActionCompact      = "compact"
+	ActionCopyKey      = "copy_key"
+	ActionRandomAlbum  = "random_album"
+
+	// Volume jumps that ignore the configured volume step
+	ActionVolumeUpBig   = "volume_up_big"
+	ActionVolumeDownBig = "volume_down_big"
+)
+
+// KeyMap maps each global action to the keys that trigger it. Keys use
+// bubbletea's names, e.g. "p", " ", "shift+tab" or "ctrl+n".
+type KeyMap map[string][]string
+
+// DefaultKeyMap returns the built-in key bindings
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		ActionPlayPause:    {" ", "p"},
+		ActionStop:         {"S"},
+		ActionNext:         {"n"},
+		ActionPrevious:     {"b"},
+		ActionVolumeUp:     {"+", "]"},
+		ActionVolumeDown:   {"-", "["},
+		ActionSetVolume:    {"v"},
+		ActionMute:         {"m"},
+		ActionSeekBack:     {"<"},
+		ActionSeekForward:  {">"},
+		ActionShuffle:      {"h"},
+		ActionRepeat:       {"l"},
+		ActionCrossfade:    {"x"},
+		ActionSleepTimer:   {"T"},
+		ActionCycleLibrary: {"shift+tab"},
+		ActionCycleTheme:   {"ctrl+t"},
+		ActionRefresh:      {"r"},
+		ActionArtists:      {"1"},
+		ActionAlbums:       {"2"},
+		ActionPlaylists:    {"3"},
+		ActionHistory:      {"4"},
+		ActionProfiles:     {"5"},
+		ActionServers:      {"6"},
+		ActionPlayers:      {"7"},
+		ActionGenres:       {"8"},
+		ActionLibraries:    {"9"},
+		ActionQueue:        {"0"},
+		ActionGoToAlbum:    {"o"},
+		ActionGoToArtist:   {"O"},
+		ActionQuit:         {"Q"},
+		ActionHelp:         {"?"},
+		ActionSpeedUp:      {")"},
+		ActionSpeedDown:    {"("},
+		ActionSpeedReset:   {"="},
+		ActionLyrics:       {"y"},
+		ActionPollInterval: {"ctrl+p"},
+		ActionPanelLayout:  {"ctrl+l"},
+		ActionCompact:      {"z"},
+		ActionCopyKey:      {"Y"},
+		ActionRandomAlbum:  {"!"},
+
+		ActionVolumeUpBig:   {"}"},
+		ActionVolumeDownBig: {"{", "_"},
+	}
+}
+
+// GetKeyMapPath returns the path to the keymap file
+func (m *Manager) GetKeyMapPath() string {
+	return filepath.Join(m.GetConfigDir(), "keymap.json")
+}
+
+// LoadKeyMap returns the default key bindings with the overrides from the
+// keymap file applied. Actions missing from the file keep their defaults.
+// A missing file isn't an error; an unreadable one returns the defaults
+// along with the error.
+func (m *Manager) LoadKeyMap() (KeyMap, error) {
+	keys := DefaultKeyMap()
+
+	data, err := os.ReadFile(m.GetKeyMapPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return keys, nil
+	}
+	if err != nil {
+		return keys, err
+	}
+
+	var overrides KeyMap
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return keys, fmt.Errorf("failed to parse keymap: %w", err)
+	}
+
+	for action, bound := range overrides {
+		if _, ok := keys[action]; !ok {
+			return DefaultKeyMap(), fmt.Errorf("unknown action %q in keymap", action)
+		}
+		keys[action] = bound
+	}
+	return keys, nil
+}
+
+// Conflicts returns a description of every key bound to more than one action
+func (k KeyMap) Conflicts() []string {
+	actionsByKey := make(map[string][]string)
+	for action, bound := range k {
+		for _, key := range bound {
+			actionsByKey[key] = append(actionsByKey[key], action)
+		}
+	}
+
+	var conflicts []string
+	for key, actions := range actionsByKey {
+		if len(actions) > 1 {
+			sort.Strings(actions)
+			conflicts = append(conflicts, fmt.Sprintf("%q is bound to %v", key, actions))
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// Lookup returns a map from key to the action it triggers. When a key is
+// bound to several actions, the alphabetically first action wins.
+func (k KeyMap) Lookup() map[string]string {
+	actions := make([]string, 0, len(k))
+	for action := range k {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	lookup := make(map[string]string)
+	for _, action := range actions {
+		for _, key := range k[action] {
+			if _, taken := lookup[key]; !taken {
+				lookup[key] = action
+			}
+		}
+	}
+	return lookup
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+
+	"plexamp-tui/internal/logger"
+)
+
+// CurrentVersion is the config file format written by this build. Files
+// with an older version are upgraded by Load.
+const CurrentVersion = 1
+
+// migrations upgrade a config one version at a time: migrations[i] takes a
+// version i config to version i+1
+var migrations = []struct {
+	description string
+	apply       func(cfg *Config)
+}{
+	{
+		// Files from before versioning leave out settings added since; write
+		// the defaults they were getting so they show up in the file
+		description: "fill in defaults for settings added before the config was versioned",
+		apply: func(cfg *Config) {
+			if cfg.Theme == "" {
+				cfg.Theme = "default"
+			}
+			if cfg.LogMaxSizeMB <= 0 {
+				cfg.LogMaxSizeMB = logger.DefaultMaxSizeMB
+			}
+			if cfg.LogMaxBackups <= 0 {
+				cfg.LogMaxBackups = logger.DefaultMaxBackups
+			}
+			if cfg.DefaultShuffle == nil {
+				shuffle := true
+				cfg.DefaultShuffle = &shuffle
+			}
+		},
+	},
+}
+
+// migrate upgrades cfg to CurrentVersion and returns a description of each
+// migration that ran. Files written by a newer build are left as they are.
+func migrate(cfg *Config) []string {
+	if cfg.Version < 0 {
+		cfg.Version = 0
+	}
+	var applied []string
+	for cfg.Version < CurrentVersion {
+		step := migrations[cfg.Version]
+		step.apply(cfg)
+		cfg.Version++
+		applied = append(applied, fmt.Sprintf("version %d: %s", cfg.Version, step.description))
+	}
+	return applied
+}
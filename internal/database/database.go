@@ -3,49 +3,171 @@ package database
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
+// ErrCorrupt is returned when the integrity check finds a damaged database
+var ErrCorrupt = errors.New("database failed its integrity check")
+
 type Database struct {
-	DB *sql.DB
+	DB   *sql.DB
+	path string
+
+	// CorruptBackup is where a damaged database file was moved before an
+	// empty one was created in its place, and CorruptErr why it was
+	// considered damaged. Both are empty when the file opened normally.
+	CorruptBackup string
+	CorruptErr    error
 }
 
+// New opens the database at path, creating it and its tables when needed. A
+// damaged file, e.g. one cut short by a power loss, is moved to <path>.bak
+// and replaced by an empty database, so the app still starts.
 func New(path string) (*Database, error) {
 	// Create directory if it doesn't exist
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return nil, fmt.Errorf("failed to create database directory: %w", err)
 	}
 
+	d, err := open(path)
+	if err == nil || !isCorrupt(err) {
+		return d, err
+	}
+
+	backup, backupErr := backupCorruptFile(path)
+	if backupErr != nil {
+		return nil, fmt.Errorf("%w (moving it aside failed: %v)", err, backupErr)
+	}
+	d, openErr := open(path)
+	if openErr != nil {
+		return nil, openErr
+	}
+	d.CorruptBackup = backup
+	d.CorruptErr = err
+	return d, nil
+}
+
+// isCorrupt reports whether err means the database file is damaged, as
+// opposed to e.g. unreadable
+func isCorrupt(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrCorrupt || sqliteErr.Code == sqlite3.ErrNotADB
+	}
+	return errors.Is(err, ErrCorrupt)
+}
+
+// backupCorruptFile moves a damaged database, along with its write-ahead log
+// and shared memory files, to <path>.bak, replacing any earlier backup
+func backupCorruptFile(path string) (string, error) {
+	backup := path + ".bak"
+	if err := os.Rename(path, backup); err != nil {
+		return "", err
+	}
+	for _, suffix := range []string{"-wal", "-shm"} {
+		os.Remove(backup + suffix)
+		if err := os.Rename(path+suffix, backup+suffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+	return backup, nil
+}
+
+// open opens the database at path and checks it, creating missing tables
+func open(path string) (*Database, error) {
 	db, err := sql.Open("sqlite3", path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
+	// Close the handle on any failure, so a damaged file can be moved aside
+	fail := func(format string, err error) (*Database, error) {
+		db.Close()
+		return nil, fmt.Errorf(format, err)
+	}
+
 	if err := db.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+		return fail("failed to ping database: %w", err)
+	}
+
+	// Write-ahead logging lets readers carry on while the history is written
+	var journalMode string
+	if err := db.QueryRow("PRAGMA journal_mode = WAL;").Scan(&journalMode); err != nil {
+		return fail("failed to enable write-ahead logging: %w", err)
+	}
+
+	if err := checkIntegrity(db); err != nil {
+		return fail("failed to check database: %w", err)
 	}
 
 	// Enable foreign keys
 	if _, err := db.Exec("PRAGMA foreign_keys = ON;"); err != nil {
-		return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
+		return fail("failed to enable foreign keys: %w", err)
 	}
 
 	// Create tables
 	if err := createTables(db); err != nil {
-		return nil, fmt.Errorf("failed to create tables: %w", err)
+		return fail("failed to create tables: %w", err)
 	}
 
-	return &Database{DB: db}, nil
+	return &Database{DB: db, path: path}, nil
+}
+
+// checkIntegrity runs SQLite's integrity check, which answers "ok" or lists
+// the problems it found
+func checkIntegrity(db *sql.DB) error {
+	var result string
+	if err := db.QueryRow("PRAGMA integrity_check;").Scan(&result); err != nil {
+		return err
+	}
+	if result != "ok" {
+		return fmt.Errorf("%w: %s", ErrCorrupt, result)
+	}
+	return nil
 }
 
 func (d *Database) Close() error {
 	return d.DB.Close()
 }
 
+// Maintain rebuilds the database file without the space left behind by
+// deleted rows and refreshes the query planner statistics. It returns the
+// number of bytes reclaimed, which can be negative when the rebuilt file
+// ends up larger.
+func (d *Database) Maintain() (int64, error) {
+	before := d.size()
+
+	if _, err := d.DB.Exec("VACUUM;"); err != nil {
+		return 0, fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	if _, err := d.DB.Exec("PRAGMA optimize;"); err != nil {
+		return 0, fmt.Errorf("failed to optimize database: %w", err)
+	}
+	// The vacuumed pages sit in the write-ahead log until checkpointed
+	if _, err := d.DB.Exec("PRAGMA wal_checkpoint(TRUNCATE);"); err != nil {
+		return 0, fmt.Errorf("failed to checkpoint database: %w", err)
+	}
+
+	return before - d.size(), nil
+}
+
+// size returns the bytes the database takes on disk, including its
+// write-ahead log
+func (d *Database) size() int64 {
+	var total int64
+	for _, path := range []string{d.path, d.path + "-wal"} {
+		if info, err := os.Stat(path); err == nil {
+			total += info.Size()
+		}
+	}
+	return total
+}
+
 func createTables(db *sql.DB) error {
 	_, err := db.Exec(`
 		CREATE TABLE IF NOT EXISTS favorites (
@@ -54,8 +176,72 @@ func createTables(db *sql.DB) error {
 			type TEXT NOT NULL,
 			metadata_key TEXT NOT NULL,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			sort_order INTEGER NOT NULL DEFAULT 0,
+			tags TEXT NOT NULL DEFAULT '',
 			UNIQUE(type, metadata_key)
 		)
 	`)
+	if err != nil {
+		return err
+	}
+
+	if err := addFavoritesSortOrder(db); err != nil {
+		return err
+	}
+
+	if err := addFavoritesTags(db); err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS play_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			type TEXT NOT NULL,
+			metadata_key TEXT NOT NULL,
+			played_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
 	return err
 }
+
+// addFavoritesSortOrder adds the sort_order column to favorites tables created
+// before favorites could be reordered, keeping the previous newest-first order
+func addFavoritesSortOrder(db *sql.DB) error {
+	exists, err := hasColumn(db, "favorites", "sort_order")
+	if err != nil || exists {
+		return err
+	}
+
+	if _, err := db.Exec(`ALTER TABLE favorites ADD COLUMN sort_order INTEGER NOT NULL DEFAULT 0`); err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		UPDATE favorites SET sort_order = (
+			SELECT COUNT(*) FROM favorites AS newer
+			WHERE newer.created_at > favorites.created_at
+				OR (newer.created_at = favorites.created_at AND newer.id > favorites.id)
+		)
+	`)
+	return err
+}
+
+// addFavoritesTags adds the tags column to favorites tables created before
+// favorites could be tagged, leaving every favorite untagged
+func addFavoritesTags(db *sql.DB) error {
+	exists, err := hasColumn(db, "favorites", "tags")
+	if err != nil || exists {
+		return err
+	}
+	_, err = db.Exec(`ALTER TABLE favorites ADD COLUMN tags TEXT NOT NULL DEFAULT ''`)
+	return err
+}
+
+// hasColumn reports whether a table has a column, for adding columns to
+// databases created by earlier versions
+func hasColumn(db *sql.DB, table, column string) (bool, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column).Scan(&count)
+	return count > 0, err
+}
@@ -0,0 +1,119 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// countFavorites returns the number of rows in the favorites table
+func countFavorites(t *testing.T, d *Database) int {
+	t.Helper()
+	var count int
+	if err := d.DB.QueryRow("SELECT COUNT(*) FROM favorites").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+// addFavorites inserts n favorites with long names, so deleting them leaves
+// pages for a vacuum to reclaim
+func addFavorites(t *testing.T, d *Database, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("%0500d", i)
+		if _, err := d.DB.Exec("INSERT INTO favorites (name, type, metadata_key) VALUES (?, 'artist', ?)", name, fmt.Sprint(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReopenAfterMaintain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plexamp-tui.db")
+	d, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addFavorites(t, d, 200)
+	if _, err := d.DB.Exec("DELETE FROM favorites WHERE id > 10"); err != nil {
+		t.Fatal(err)
+	}
+
+	reclaimed, err := d.Maintain()
+	if err != nil {
+		t.Fatalf("Maintain: %v", err)
+	}
+	if reclaimed <= 0 {
+		t.Errorf("reclaimed %d bytes after deleting most rows, want some", reclaimed)
+	}
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err = New(path)
+	if err != nil {
+		t.Fatalf("reopening after Maintain: %v", err)
+	}
+	defer d.Close()
+	if d.CorruptBackup != "" {
+		t.Errorf("maintained database was treated as damaged: %v", d.CorruptErr)
+	}
+	if got := countFavorites(t, d); got != 10 {
+		t.Errorf("got %d favorites after reopening, want 10", got)
+	}
+}
+
+func TestRecoverTruncatedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plexamp-tui.db")
+	d, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addFavorites(t, d, 200)
+	// Fold the write-ahead log into the file, then cut it short, as a power
+	// loss during a sync might
+	if _, err := d.DB.Exec("PRAGMA wal_checkpoint(TRUNCATE);"); err != nil {
+		t.Fatal(err)
+	}
+	d.Close()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(path, info.Size()/2); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err = New(path)
+	if err != nil {
+		t.Fatalf("New on a truncated file: %v", err)
+	}
+	defer d.Close()
+
+	if d.CorruptBackup != path+".bak" || d.CorruptErr == nil {
+		t.Errorf("backup %q, error %v; want %s.bak and why it was damaged", d.CorruptBackup, d.CorruptErr, path)
+	}
+	if _, err := os.Stat(path + ".bak"); err != nil {
+		t.Errorf("damaged file wasn't kept: %v", err)
+	}
+	if got := countFavorites(t, d); got != 0 {
+		t.Errorf("got %d favorites in the new database, want an empty one", got)
+	}
+}
+
+func TestRecoverNonDatabaseFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plexamp-tui.db")
+	if err := os.WriteFile(path, []byte("not a database, just some text that is long enough"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := New(path)
+	if err != nil {
+		t.Fatalf("New on a non-database file: %v", err)
+	}
+	defer d.Close()
+	if d.CorruptBackup == "" {
+		t.Error("non-database file wasn't moved aside")
+	}
+}
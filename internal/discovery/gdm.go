@@ -0,0 +1,129 @@
+// Package discovery finds Plexamp players on the local network with GDM
+// ("G'Day Mate"), the UDP discovery protocol Plex apps answer to. It finds
+// players that aren't linked to a plex.tv account.
+package discovery
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+const (
+	// searchPort is the port players listen on for GDM searches
+	searchPort = 32412
+
+	// searchMessage asks every player that hears it to describe itself
+	searchMessage = "M-SEARCH * HTTP/1.1\r\n\r\n"
+
+	// playerContentType is sent by players, as opposed to servers
+	playerContentType = "plex/media-player"
+
+	// defaultPlayerPort is assumed when a player leaves its port out
+	defaultPlayerPort = "32500"
+)
+
+// searchTargets are the addresses a search is sent to: the local broadcast
+// address and the GDM multicast group
+var searchTargets = []string{"255.255.255.255", "239.0.0.250"}
+
+// Player is a player that answered a GDM search
+type Player struct {
+	Name             string
+	ClientIdentifier string
+	Product          string
+	Address          string
+	Port             string
+}
+
+// Search sends a GDM search and returns a channel receiving each player as
+// it answers. The channel is closed once timeout has passed.
+func Search(timeout time.Duration) (<-chan Player, error) {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// A search only fails when it can't be sent anywhere; networks without
+	// multicast still get the broadcast
+	var sent bool
+	var sendErr error
+	for _, target := range searchTargets {
+		addr := &net.UDPAddr{IP: net.ParseIP(target), Port: searchPort}
+		if _, err := conn.WriteTo([]byte(searchMessage), addr); err != nil {
+			sendErr = errors.Join(sendErr, err)
+			continue
+		}
+		sent = true
+	}
+	if !sent {
+		conn.Close()
+		return nil, sendErr
+	}
+
+	players := make(chan Player)
+	go func() {
+		defer close(players)
+		defer conn.Close()
+
+		seen := make(map[string]bool)
+		buf := make([]byte, 4096)
+		for {
+			n, from, err := conn.ReadFrom(buf)
+			if err != nil {
+				// The deadline passing ends the search
+				return
+			}
+			udpAddr, ok := from.(*net.UDPAddr)
+			if !ok {
+				continue
+			}
+			player, ok := parseResponse(buf[:n], udpAddr.IP.String())
+			if !ok || seen[player.ClientIdentifier+player.Address] {
+				continue
+			}
+			seen[player.ClientIdentifier+player.Address] = true
+			players <- player
+		}
+	}()
+	return players, nil
+}
+
+// parseResponse reads a GDM answer, an HTTP-style status line followed by
+// headers, and reports whether it came from a player
+func parseResponse(data []byte, address string) (Player, bool) {
+	reader := textproto.NewReader(bufio.NewReader(strings.NewReader(string(data))))
+	status, err := reader.ReadLine()
+	if err != nil || !strings.Contains(status, "200") {
+		return Player{}, false
+	}
+	header, err := reader.ReadMIMEHeader()
+	if err != nil && len(header) == 0 {
+		return Player{}, false
+	}
+	if header.Get("Content-Type") != playerContentType {
+		return Player{}, false
+	}
+
+	player := Player{
+		Name:             header.Get("Name"),
+		ClientIdentifier: header.Get("Resource-Identifier"),
+		Product:          header.Get("Product"),
+		Address:          address,
+		Port:             header.Get("Port"),
+	}
+	if player.Port == "" {
+		player.Port = defaultPlayerPort
+	}
+	if player.Name == "" {
+		player.Name = address
+	}
+	return player, true
+}
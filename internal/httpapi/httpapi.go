@@ -0,0 +1,176 @@
+// Package httpapi serves a small HTTP API for controlling the player from
+// scripts and home automation. Commands are handed to the UI, which carries
+// them out like the matching keys.
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// CommandHeader must be sent with every command, set to "1". A web page can't
+// add it to a cross-origin request without a CORS preflight, which the API
+// never approves, so pages open in a browser can't send commands.
+const CommandHeader = "X-Plexamp-TUI"
+
+// commandQueue is how many commands can wait for the UI before more are refused
+const commandQueue = 16
+
+// Action is a playback command received over the API
+type Action string
+
+const (
+	ActionPlay      Action = "play"
+	ActionPause     Action = "pause"
+	ActionPlayPause Action = "playpause"
+	ActionStop      Action = "stop"
+	ActionNext      Action = "next"
+	ActionPrevious  Action = "prev"
+	ActionVolume    Action = "volume"
+)
+
+// Command is a request to the UI. Volume is only set for ActionVolume.
+type Command struct {
+	Action Action
+	Volume int
+}
+
+// State is the playback state returned by GET /status
+type State struct {
+	State      string `json:"state"` // "playing", "paused" or "stopped"
+	Title      string `json:"title,omitempty"`
+	Artist     string `json:"artist,omitempty"`
+	Album      string `json:"album,omitempty"`
+	PositionMs int    `json:"position_ms"`
+	DurationMs int    `json:"duration_ms"`
+	Volume     int    `json:"volume"`
+	Player     string `json:"player,omitempty"`
+}
+
+// Server is the running API
+type Server struct {
+	http     *http.Server
+	commands chan Command
+
+	mu    sync.Mutex
+	state State
+}
+
+// Start listens on addr and serves the API in the background
+func Start(addr string) (*Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		commands: make(chan Command, commandQueue),
+		state:    State{State: "stopped"},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", s.handleStatus)
+	for _, action := range []Action{ActionPlay, ActionPause, ActionPlayPause, ActionStop, ActionNext, ActionPrevious, ActionVolume} {
+		mux.HandleFunc("/"+string(action), s.handleCommand(action))
+	}
+	s.http = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go s.http.Serve(listener)
+	return s, nil
+}
+
+// Commands returns the channel the received commands are delivered on
+func (s *Server) Commands() <-chan Command {
+	return s.commands
+}
+
+// SetState replaces the state returned by GET /status
+func (s *Server) SetState(state State) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = state
+}
+
+// Close stops the server
+func (s *Server) Close() error {
+	return s.http.Close()
+}
+
+// handleStatus returns the playback state as JSON
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "use GET")
+		return
+	}
+	s.mu.Lock()
+	state := s.state
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, state)
+}
+
+// handleCommand queues a command for the UI. Commands change the player, so
+// only POST is accepted, which also keeps a stray link from triggering one.
+// Requests from web pages are refused: browsers send an Origin header with
+// them, and scripts have no reason to.
+func (s *Server) handleCommand(action Action) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "use POST")
+			return
+		}
+		if r.Header.Get("Origin") != "" {
+			writeError(w, http.StatusForbidden, "requests from web pages are not allowed")
+			return
+		}
+		if r.Header.Get(CommandHeader) != "1" {
+			writeError(w, http.StatusForbidden, "missing header "+CommandHeader+": 1")
+			return
+		}
+
+		cmd := Command{Action: action}
+		if action == ActionVolume {
+			volume, err := parseVolume(r.URL.Query().Get("v"))
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			cmd.Volume = volume
+		}
+
+		select {
+		case s.commands <- cmd:
+			writeJSON(w, http.StatusAccepted, map[string]string{"queued": string(action)})
+		default:
+			writeError(w, http.StatusServiceUnavailable, "too many commands waiting")
+		}
+	}
+}
+
+// parseVolume reads a volume from 0 to 100
+func parseVolume(value string) (int, error) {
+	if value == "" {
+		return 0, errors.New("missing volume: use ?v=0-100")
+	}
+	volume, err := strconv.Atoi(value)
+	if err != nil || volume < 0 || volume > 100 {
+		return 0, fmt.Errorf("invalid volume %q: use a number from 0 to 100", value)
+	}
+	return volume, nil
+}
+
+// writeJSON writes v as the JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error as a JSON response
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,46 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleCommandRequiresHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		origin string
+		status int
+	}{
+		{name: "script", header: "1", status: http.StatusAccepted},
+		{name: "missing header", status: http.StatusForbidden},
+		{name: "wrong header", header: "yes", status: http.StatusForbidden},
+		{name: "web page", origin: "https://example.com", status: http.StatusForbidden},
+		{name: "web page with header", header: "1", origin: "https://example.com", status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{commands: make(chan Command, commandQueue)}
+			req := httptest.NewRequest(http.MethodPost, "/playpause", nil)
+			if tt.header != "" {
+				req.Header.Set(CommandHeader, tt.header)
+			}
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rec := httptest.NewRecorder()
+
+			s.handleCommand(ActionPlayPause)(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			queued := len(s.commands) > 0
+			if queued != (tt.status == http.StatusAccepted) {
+				t.Errorf("command queued: %v, want %v", queued, !queued)
+			}
+		})
+	}
+}
@@ -21,10 +21,21 @@ const (
 type Logger struct {
 	debugMode bool
 	logFile   *os.File
+	logPath   string
 	logger    *log.Logger
 	mu        sync.Mutex
+
+	maxSize    int64 // Rotate once the log file grows past this many bytes
+	maxBackups int   // Number of rotated files kept as <log>.1 ... <log>.N
 }
 
+const (
+	// DefaultMaxSizeMB is the log size that triggers rotation unless configured
+	DefaultMaxSizeMB = 5
+	// DefaultMaxBackups is how many rotated logs are kept unless configured
+	DefaultMaxBackups = 3
+)
+
 var (
 	instance *Logger
 	once     sync.Once
@@ -57,9 +68,12 @@ func NewLogger(debug bool, logFilePath string) (*Logger, error) {
 	}
 
 	return &Logger{
-		debugMode: debug,
-		logFile:   logFile,
-		logger:    logger,
+		debugMode:  debug,
+		logFile:    logFile,
+		logPath:    logFilePath,
+		logger:     logger,
+		maxSize:    DefaultMaxSizeMB * 1024 * 1024,
+		maxBackups: DefaultMaxBackups,
 	}, nil
 }
 
@@ -81,6 +95,19 @@ func (l *Logger) SetDebug(debug bool) {
 	l.debugMode = debug
 }
 
+// SetRotation sets the log size in megabytes that triggers rotation and how
+// many rotated files are kept. Values of zero or less keep the defaults.
+func (l *Logger) SetRotation(maxSizeMB, maxBackups int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if maxSizeMB > 0 {
+		l.maxSize = int64(maxSizeMB) * 1024 * 1024
+	}
+	if maxBackups > 0 {
+		l.maxBackups = maxBackups
+	}
+}
+
 // Debug logs a debug message
 func (l *Logger) Debug(format string, v ...interface{}) {
 	if !l.debugMode {
@@ -129,10 +156,44 @@ func (l *Logger) log(level LogLevel, format string, v ...interface{}) {
 
 	l.logger.SetPrefix(prefix)
 	l.logger.Printf(format, v...)
+	l.rotateIfNeeded()
+}
+
+// rotateIfNeeded starts a new log file once the current one has grown past
+// maxSize, shifting older files up to <log>.N. The caller must hold l.mu.
+func (l *Logger) rotateIfNeeded() {
+	if l.logFile == nil || l.maxSize <= 0 {
+		return
+	}
+	info, err := l.logFile.Stat()
+	if err != nil || info.Size() < l.maxSize {
+		return
+	}
+
+	l.logFile.Close()
+	for i := l.maxBackups - 1; i > 0; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", l.logPath, i), fmt.Sprintf("%s.%d", l.logPath, i+1))
+	}
+	if l.maxBackups > 0 {
+		os.Rename(l.logPath, l.logPath+".1")
+	}
+
+	// Truncate in case there are no backups and the file couldn't be renamed
+	logFile, err := os.OpenFile(l.logPath, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		// Nothing left to write to; drop further output rather than fail
+		l.logFile = nil
+		l.logger.SetOutput(io.Discard)
+		return
+	}
+	l.logFile = logFile
+	l.logger.SetOutput(logFile)
 }
 
 // Close closes the log file if it's open
 func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	if l.logFile != nil {
 		return l.logFile.Close()
 	}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plexamp-tui.log")
+	l, err := NewLogger(true, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	l.maxSize = 100
+	l.maxBackups = 2
+
+	line := strings.Repeat("x", 60)
+	for i := 0; i < 10; i++ {
+		l.Info("%d %s", i, line)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() >= l.maxSize {
+		t.Errorf("current log is %d bytes, want under %d", info.Size(), l.maxSize)
+	}
+	for _, backup := range []string{path + ".1", path + ".2"} {
+		if _, err := os.Stat(backup); err != nil {
+			t.Errorf("backup %s: %v", filepath.Base(backup), err)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("kept a third backup with maxBackups 2")
+	}
+
+	// Nothing recent is lost: the last line is in the current log, or in the
+	// newest backup if writing it triggered the rotation
+	current, _ := os.ReadFile(path)
+	newest, _ := os.ReadFile(path + ".1")
+	if !strings.Contains(string(current)+string(newest), "9 "+line) {
+		t.Errorf("the last line is in neither the log nor its newest backup")
+	}
+}
+
+func TestSetRotationKeepsDefaults(t *testing.T) {
+	l, err := NewLogger(false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.SetRotation(0, -1)
+	if l.maxSize != DefaultMaxSizeMB*1024*1024 || l.maxBackups != DefaultMaxBackups {
+		t.Errorf("got max size %d and %d backups, want the defaults", l.maxSize, l.maxBackups)
+	}
+	l.SetRotation(1, 5)
+	if l.maxSize != 1024*1024 || l.maxBackups != 5 {
+		t.Errorf("got max size %d and %d backups, want 1MB and 5", l.maxSize, l.maxBackups)
+	}
+}
@@ -0,0 +1,40 @@
+// Package mpris exposes the player over the MPRIS D-Bus interface so desktop
+// media keys and widgets can control it. It is only functional on Linux;
+// elsewhere Start returns ErrUnsupported.
+package mpris
+
+import (
+	"errors"
+	"time"
+)
+
+// BusName is the well-known D-Bus name the player is registered under
+const BusName = "org.mpris.MediaPlayer2.plexamptui"
+
+// ErrUnsupported is returned by Start on platforms without D-Bus
+var ErrUnsupported = errors.New("mpris is only supported on linux")
+
+// Command is a playback command received from the desktop
+type Command string
+
+const (
+	CommandPlay      Command = "play"
+	CommandPause     Command = "pause"
+	CommandPlayPause Command = "playpause"
+	CommandStop      Command = "stop"
+	CommandNext      Command = "next"
+	CommandPrevious  Command = "previous"
+)
+
+// State is the playback state published to the desktop
+type State struct {
+	Playing  bool
+	Stopped  bool // Nothing is loaded on the player
+	Title    string
+	Artist   string
+	Album    string
+	ArtURL   string
+	Length   time.Duration
+	Position time.Duration
+	Volume   float64 // 0.0 to 1.0
+}
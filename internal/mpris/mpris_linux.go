@@ -0,0 +1,326 @@
+//go:build linux
+
+package mpris
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+)
+
+// objPath is the object all MPRIS interfaces live on
+const objPath dbus.ObjectPath = "/org/mpris/MediaPlayer2"
+
+// MPRIS and standard D-Bus interface names
+const (
+	ifaceRoot          = "org.mpris.MediaPlayer2"
+	ifacePlayer        = "org.mpris.MediaPlayer2.Player"
+	ifaceProperties    = "org.freedesktop.DBus.Properties"
+	ifaceIntrospection = "org.freedesktop.DBus.Introspectable"
+)
+
+// noTrack is the MPRIS track id used when nothing is playing
+const noTrack dbus.ObjectPath = "/org/mpris/MediaPlayer2/TrackList/NoTrack"
+
+// Server registers the player on the session bus and answers MPRIS calls
+type Server struct {
+	conn     *dbus.Conn
+	commands chan Command
+
+	mu      sync.Mutex
+	state   State
+	trackID int  // Incremented for each new track, used to build its object path
+	closed  bool // The bus went away and commands is closed
+}
+
+// Start connects to the session bus and claims the MPRIS name
+func Start() (*Server, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the session bus: %w", err)
+	}
+
+	s := &Server{
+		conn:     conn,
+		commands: make(chan Command, 8),
+		state:    State{Stopped: true},
+	}
+	if err := s.export(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	reply, err := conn.RequestName(BusName, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to request %s: %w", BusName, err)
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		conn.Close()
+		return nil, fmt.Errorf("%s is already owned by another process", BusName)
+	}
+
+	// The app stops reading commands once the bus goes away
+	go func() {
+		<-conn.Context().Done()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.closed = true
+		close(s.commands)
+	}()
+	return s, nil
+}
+
+// export registers the MPRIS interfaces on objPath
+func (s *Server) export() error {
+	exports := []struct {
+		v     interface{}
+		iface string
+	}{
+		{root{}, ifaceRoot},
+		{player{s}, ifacePlayer},
+		{properties{s}, ifaceProperties},
+		{introspect.Introspectable(introspectXML), ifaceIntrospection},
+	}
+	for _, e := range exports {
+		if err := s.conn.Export(e.v, objPath, e.iface); err != nil {
+			return fmt.Errorf("failed to export %s: %w", e.iface, err)
+		}
+	}
+	return nil
+}
+
+// Commands returns the channel desktop playback commands are delivered on
+func (s *Server) Commands() <-chan Command {
+	return s.commands
+}
+
+// SetState publishes the current playback state, emitting PropertiesChanged
+// for whatever changed
+func (s *Server) SetState(state State) {
+	s.mu.Lock()
+	old := s.state
+	if state.Title != old.Title || state.Artist != old.Artist || state.Album != old.Album {
+		s.trackID++
+	}
+	s.state = state
+
+	changed := map[string]dbus.Variant{}
+	if playbackStatus(state) != playbackStatus(old) {
+		changed["PlaybackStatus"] = dbus.MakeVariant(playbackStatus(state))
+	}
+	if state.Title != old.Title || state.Artist != old.Artist || state.Album != old.Album ||
+		state.ArtURL != old.ArtURL || state.Length != old.Length {
+		changed["Metadata"] = dbus.MakeVariant(s.metadata())
+	}
+	if state.Volume != old.Volume {
+		changed["Volume"] = dbus.MakeVariant(state.Volume)
+	}
+	s.mu.Unlock()
+
+	if len(changed) == 0 {
+		return
+	}
+	// Errors mean the bus went away, which closes the commands channel
+	_ = s.conn.Emit(objPath, ifaceProperties+".PropertiesChanged", ifacePlayer, changed, []string{})
+}
+
+// Close releases the bus name and disconnects
+func (s *Server) Close() error {
+	return s.conn.Close()
+}
+
+// send forwards a playback call to the app
+// Commands are dropped rather than blocking the bus when the app falls behind
+func (s *Server) send(cmd Command) *dbus.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	select {
+	case s.commands <- cmd:
+	default:
+	}
+	return nil
+}
+
+// root implements org.mpris.MediaPlayer2
+// CanRaise and CanQuit are false, so its methods are accepted and ignored
+type root struct{}
+
+func (root) Raise() *dbus.Error { return nil }
+func (root) Quit() *dbus.Error  { return nil }
+
+// player implements org.mpris.MediaPlayer2.Player
+type player struct{ s *Server }
+
+func (p player) Play() *dbus.Error      { return p.s.send(CommandPlay) }
+func (p player) Pause() *dbus.Error     { return p.s.send(CommandPause) }
+func (p player) PlayPause() *dbus.Error { return p.s.send(CommandPlayPause) }
+func (p player) Stop() *dbus.Error      { return p.s.send(CommandStop) }
+func (p player) Next() *dbus.Error      { return p.s.send(CommandNext) }
+func (p player) Previous() *dbus.Error  { return p.s.send(CommandPrevious) }
+
+// properties implements org.freedesktop.DBus.Properties; every property is
+// read-only
+type properties struct{ s *Server }
+
+func (p properties) Get(iface, name string) (dbus.Variant, *dbus.Error) {
+	value, ok := p.s.properties(iface)[name]
+	if !ok {
+		return dbus.Variant{}, dbus.NewError("org.freedesktop.DBus.Error.UnknownProperty",
+			[]interface{}{"No such property " + name})
+	}
+	return value, nil
+}
+
+func (p properties) GetAll(iface string) (map[string]dbus.Variant, *dbus.Error) {
+	return p.s.properties(iface), nil
+}
+
+func (p properties) Set(iface, name string, value dbus.Variant) *dbus.Error {
+	return dbus.NewError("org.freedesktop.DBus.Error.PropertyReadOnly",
+		[]interface{}{"Properties are read-only"})
+}
+
+// properties returns the current properties of an interface
+func (s *Server) properties(iface string) map[string]dbus.Variant {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch iface {
+	case ifaceRoot:
+		return map[string]dbus.Variant{
+			"CanQuit":             dbus.MakeVariant(false),
+			"CanRaise":            dbus.MakeVariant(false),
+			"HasTrackList":        dbus.MakeVariant(false),
+			"Identity":            dbus.MakeVariant("Plexamp TUI"),
+			"SupportedUriSchemes": dbus.MakeVariant([]string{}),
+			"SupportedMimeTypes":  dbus.MakeVariant([]string{}),
+		}
+	case ifacePlayer:
+		return map[string]dbus.Variant{
+			"PlaybackStatus": dbus.MakeVariant(playbackStatus(s.state)),
+			"Rate":           dbus.MakeVariant(1.0),
+			"MinimumRate":    dbus.MakeVariant(1.0),
+			"MaximumRate":    dbus.MakeVariant(1.0),
+			"Metadata":       dbus.MakeVariant(s.metadata()),
+			"Volume":         dbus.MakeVariant(s.state.Volume),
+			"Position":       dbus.MakeVariant(s.state.Position.Microseconds()),
+			"CanGoNext":      dbus.MakeVariant(true),
+			"CanGoPrevious":  dbus.MakeVariant(true),
+			"CanPlay":        dbus.MakeVariant(true),
+			"CanPause":       dbus.MakeVariant(true),
+			"CanSeek":        dbus.MakeVariant(false),
+			"CanControl":     dbus.MakeVariant(true),
+		}
+	default:
+		return map[string]dbus.Variant{}
+	}
+}
+
+// metadata builds the MPRIS metadata map for the current track
+// Must be called with s.mu held
+func (s *Server) metadata() map[string]dbus.Variant {
+	if s.state.Stopped || s.state.Title == "" {
+		return map[string]dbus.Variant{"mpris:trackid": dbus.MakeVariant(noTrack)}
+	}
+
+	md := map[string]dbus.Variant{
+		"mpris:trackid": dbus.MakeVariant(dbus.ObjectPath(fmt.Sprintf("/org/mpris/MediaPlayer2/plexamptui/track/%d", s.trackID))),
+		"xesam:title":   dbus.MakeVariant(s.state.Title),
+	}
+	if s.state.Artist != "" {
+		md["xesam:artist"] = dbus.MakeVariant([]string{s.state.Artist})
+	}
+	if s.state.Album != "" {
+		md["xesam:album"] = dbus.MakeVariant(s.state.Album)
+	}
+	if s.state.ArtURL != "" {
+		md["mpris:artUrl"] = dbus.MakeVariant(s.state.ArtURL)
+	}
+	if s.state.Length > 0 {
+		md["mpris:length"] = dbus.MakeVariant(s.state.Length.Microseconds())
+	}
+	return md
+}
+
+// playbackStatus maps the state to an MPRIS PlaybackStatus value
+func playbackStatus(state State) string {
+	switch {
+	case state.Stopped:
+		return "Stopped"
+	case state.Playing:
+		return "Playing"
+	default:
+		return "Paused"
+	}
+}
+
+// introspectXML describes the interfaces implemented on objPath
+const introspectXML = `<!DOCTYPE node PUBLIC "-//freedesktop//DTD D-BUS Object Introspection 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/introspect.dtd">
+<node>
+  <interface name="org.freedesktop.DBus.Introspectable">
+    <method name="Introspect"><arg name="data" type="s" direction="out"/></method>
+  </interface>
+  <interface name="org.freedesktop.DBus.Peer">
+    <method name="Ping"/>
+  </interface>
+  <interface name="org.freedesktop.DBus.Properties">
+    <method name="Get">
+      <arg name="interface" type="s" direction="in"/>
+      <arg name="property" type="s" direction="in"/>
+      <arg name="value" type="v" direction="out"/>
+    </method>
+    <method name="GetAll">
+      <arg name="interface" type="s" direction="in"/>
+      <arg name="properties" type="a{sv}" direction="out"/>
+    </method>
+    <method name="Set">
+      <arg name="interface" type="s" direction="in"/>
+      <arg name="property" type="s" direction="in"/>
+      <arg name="value" type="v" direction="in"/>
+    </method>
+    <signal name="PropertiesChanged">
+      <arg name="interface" type="s"/>
+      <arg name="changed_properties" type="a{sv}"/>
+      <arg name="invalidated_properties" type="as"/>
+    </signal>
+  </interface>
+  <interface name="org.mpris.MediaPlayer2">
+    <method name="Raise"/>
+    <method name="Quit"/>
+    <property name="CanQuit" type="b" access="read"/>
+    <property name="CanRaise" type="b" access="read"/>
+    <property name="HasTrackList" type="b" access="read"/>
+    <property name="Identity" type="s" access="read"/>
+    <property name="SupportedUriSchemes" type="as" access="read"/>
+    <property name="SupportedMimeTypes" type="as" access="read"/>
+  </interface>
+  <interface name="org.mpris.MediaPlayer2.Player">
+    <method name="Next"/>
+    <method name="Previous"/>
+    <method name="Pause"/>
+    <method name="PlayPause"/>
+    <method name="Stop"/>
+    <method name="Play"/>
+    <property name="PlaybackStatus" type="s" access="read"/>
+    <property name="Rate" type="d" access="read"/>
+    <property name="Metadata" type="a{sv}" access="read"/>
+    <property name="Volume" type="d" access="read"/>
+    <property name="Position" type="x" access="read"/>
+    <property name="MinimumRate" type="d" access="read"/>
+    <property name="MaximumRate" type="d" access="read"/>
+    <property name="CanGoNext" type="b" access="read"/>
+    <property name="CanGoPrevious" type="b" access="read"/>
+    <property name="CanPlay" type="b" access="read"/>
+    <property name="CanPause" type="b" access="read"/>
+    <property name="CanSeek" type="b" access="read"/>
+    <property name="CanControl" type="b" access="read"/>
+    <signal name="Seeked"><arg name="Position" type="x"/></signal>
+  </interface>
+</node>`
@@ -0,0 +1,24 @@
+//go:build !linux
+
+package mpris
+
+// Server is a no-op outside of Linux
+type Server struct{}
+
+// Start always fails outside of Linux
+func Start() (*Server, error) {
+	return nil, ErrUnsupported
+}
+
+// Commands returns a channel that never receives
+func (s *Server) Commands() <-chan Command {
+	return nil
+}
+
+// SetState does nothing outside of Linux
+func (s *Server) SetState(State) {}
+
+// Close does nothing outside of Linux
+func (s *Server) Close() error {
+	return nil
+}
@@ -3,9 +3,11 @@ package plex
 import (
 	"encoding/xml"
 	"fmt"
-	"io"
 	"net/http"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
 //curl "https://plex.tv/api/resources?includeHttps=1&includeRelay=1&X-Plex-Token=<token>"
@@ -14,6 +16,9 @@ const (
 	plexCloudBaseURL = "https://plex.tv"
 )
 
+// connectionProbeTimeout bounds how long each server connection is probed for
+const connectionProbeTimeout = 3 * time.Second
+
 type PlexDeviceInfo struct {
 	Name                 string           `xml:"name,attr"`
 	Product              string           `xml:"product,attr"`
@@ -33,6 +38,18 @@ type PlexDeviceInfo struct {
 	Connections          []PlexConnection `xml:"Connection"`
 }
 
+// ProvidesCapability reports whether capability, such as "server" or
+// "player", is one of the entries in the device's comma-separated provides
+// list. Matching whole entries keeps "pubsub-player" from counting as "player".
+func (d PlexDeviceInfo) ProvidesCapability(capability string) bool {
+	for _, provided := range strings.Split(d.Provides, ",") {
+		if strings.TrimSpace(provided) == capability {
+			return true
+		}
+	}
+	return false
+}
+
 type PlexConnection struct {
 	Protocol string `xml:"protocol,attr"`
 	Address  string `xml:"address,attr"`
@@ -55,100 +72,196 @@ type PlexConnectionSelection struct {
 	Local            string `xml:"local,attr"`
 	Port             string `xml:"port,attr"`
 	URI              string `xml:"uri,attr"`
+	Relay            string `xml:"relay,attr"`
+
+	// Connections lists every connection of a player in probing order; Address
+	// and Port hold the first of them
+	Connections []PlexConnection `xml:"-"`
 }
 
-func (p *PlexClient) GetPlexServerInformation() ([]PlexConnectionSelection, error) {
-	token := p.GetPlexToken()
-	urlStr := fmt.Sprintf("%s/api/resources?includeHttps=1&includeRelay=1&X-Plex-Token=%s", plexCloudBaseURL, token)
+// PlayerPort is the port Plexamp players listen on for remote control
+const PlayerPort = "32500"
 
-	resp, err := http.Get(urlStr)
-	if err != nil {
-		p.logger.Debug(fmt.Sprintf("Request error: %v", err))
-		return nil, fmt.Errorf("failed to connect to %s: %w", plexCloudBaseURL, err)
+// connectionRank orders connections for probing: local first, then remote,
+// then relayed connections which are bandwidth limited
+func connectionRank(c PlexConnection) int {
+	switch {
+	case c.Relay == "1":
+		return 2
+	case c.Local == "1":
+		return 0
+	default:
+		return 1
 	}
-	defer resp.Body.Close()
+}
 
-	p.logger.Debug(fmt.Sprintf("Response status: %d", resp.StatusCode))
+// sortConnections returns a copy of connections in probing order
+func sortConnections(connections []PlexConnection) []PlexConnection {
+	sorted := make([]PlexConnection, len(connections))
+	copy(sorted, connections)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return connectionRank(sorted[i]) < connectionRank(sorted[j])
+	})
+	return sorted
+}
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return nil, fmt.Errorf("server returned status %d", resp.StatusCode)
+// probeConnections tries connections in order and returns the first whose
+// probe URL answers with 200. When none answer the first connection is
+// returned with ok set to false.
+func (p *PlexClient) probeConnections(name string, connections []PlexConnection, probeURL func(PlexConnection) string) (PlexConnection, bool) {
+	probe := &http.Client{Timeout: connectionProbeTimeout}
+	for _, connection := range connections {
+		target := probeURL(connection)
+		resp, err := probe.Get(target)
+		if err != nil {
+			p.logger.Debug("Connection %s for %s is unreachable: %v", target, name, err)
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			p.logger.Debug("Using connection %s for %s", target, name)
+			return connection, true
+		}
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+	if len(connections) == 0 {
+		return PlexConnection{}, false
 	}
+	return connections[0], false
+}
 
-	var container PlexDeviceContainer
-	if err := xml.Unmarshal(body, &container); err != nil {
-		return nil, fmt.Errorf("failed to parse XML: %w", err)
-	}
+// pickBestConnection probes a server's connections in order of preference
+// and returns the first one that answers
+func (p *PlexClient) pickBestConnection(device PlexDeviceInfo) (PlexConnection, bool) {
+	return p.probeConnections(device.Name, sortConnections(device.Connections), func(c PlexConnection) string {
+		base := c.URI
+		if base == "" {
+			base = fmt.Sprintf("http://%s:%s", c.Address, c.Port)
+		}
+		return strings.TrimSuffix(base, "/") + "/identity"
+	})
+}
 
-	var servers []PlexConnectionSelection
-	for _, device := range container.Devices {
-		if !strings.Contains(device.Provides, "server") {
+// playerConnections returns the connections worth probing for a player's
+// remote control, in probing order. Relayed connections go through a Plex
+// relay that only forwards the server port, so they are left out unless
+// there is nothing else, and addresses listed more than once (for instance
+// over both http and https) are probed once.
+func playerConnections(connections []PlexConnection) []PlexConnection {
+	var direct []PlexConnection
+	seen := make(map[string]bool)
+	for _, c := range sortConnections(connections) {
+		if c.Relay == "1" || seen[c.Address] {
 			continue
 		}
-		for _, connection := range device.Connections {
-			serverConnection := PlexConnectionSelection{
+		seen[c.Address] = true
+		direct = append(direct, c)
+	}
+	if len(direct) == 0 {
+		return sortConnections(connections)
+	}
+	return direct
+}
+
+// PickPlayerConnection probes a player's connections in order, local before
+// remote, and returns the first one whose Plexamp remote control answers
+func (p *PlexClient) PickPlayerConnection(name string, connections []PlexConnection) (PlexConnection, bool) {
+	return p.probeConnections(name, connections, func(c PlexConnection) string {
+		return fmt.Sprintf("http://%s:%s/resources", c.Address, PlayerPort)
+	})
+}
+
+// fetchResources lists the servers and players on the account from plex.tv
+func (p *PlexClient) fetchResources() (PlexDeviceContainer, error) {
+	urlStr := plexCloudBaseURL + "/api/resources?includeHttps=1&includeRelay=1"
+
+	var container PlexDeviceContainer
+	if err := p.api.GetXML(urlStr, p.GetPlexToken(), &container); err != nil {
+		p.logger.Debug("Request error: %v", err)
+		return container, p.requestError("failed to connect to "+plexCloudBaseURL, err)
+	}
+	return container, nil
+}
+
+func (p *PlexClient) GetPlexServerInformation() ([]PlexConnectionSelection, error) {
+	container, err := p.fetchResources()
+	if err != nil {
+		return nil, err
+	}
+
+	devices := serverDevices(container)
+
+	// Probe servers in parallel so one unreachable server doesn't hold up the rest
+	servers := make([]PlexConnectionSelection, len(devices))
+	var wg sync.WaitGroup
+	for i, device := range devices {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			connection, ok := p.pickBestConnection(device)
+			if !ok {
+				p.logger.Warn("No reachable connection for server %s", device.Name)
+			}
+			servers[i] = PlexConnectionSelection{
 				Name:             device.Name,
 				ClientIdentifier: device.ClientIdentifier,
 				Address:          connection.Address,
 				Local:            connection.Local,
 				Port:             connection.Port,
 				URI:              connection.URI,
+				Relay:            connection.Relay,
 			}
-			servers = append(servers, serverConnection)
-		}
+		}()
 	}
+	wg.Wait()
 
 	return servers, nil
 }
 
-func (p *PlexClient) GetPlexPlayers() ([]PlexConnectionSelection, error) {
-	token := p.GetPlexToken()
-	urlStr := fmt.Sprintf("%s/api/resources?includeHttps=1&includeRelay=1&X-Plex-Token=%s", plexCloudBaseURL, token)
-
-	resp, err := http.Get(urlStr)
-	if err != nil {
-		p.logger.Debug(fmt.Sprintf("Request error: %v", err))
-		return nil, fmt.Errorf("failed to connect to %s: %w", plexCloudBaseURL, err)
-	}
-	defer resp.Body.Close()
-
-	p.logger.Debug(fmt.Sprintf("Response status: %d", resp.StatusCode))
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return nil, fmt.Errorf("server returned status %d", resp.StatusCode)
+// serverDevices lists the devices on the account that serve libraries. A
+// device that is both a server and a player, such as Plexamp with its bundled
+// server, shows up in both lists.
+func serverDevices(container PlexDeviceContainer) []PlexDeviceInfo {
+	var devices []PlexDeviceInfo
+	for _, device := range container.Devices {
+		if device.ProvidesCapability("server") && len(device.Connections) > 0 {
+			devices = append(devices, device)
+		}
 	}
+	return devices
+}
 
-	body, err := io.ReadAll(resp.Body)
+func (p *PlexClient) GetPlexPlayers() ([]PlexConnectionSelection, error) {
+	container, err := p.fetchResources()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
 
-	var container PlexDeviceContainer
-	if err := xml.Unmarshal(body, &container); err != nil {
-		return nil, fmt.Errorf("failed to parse XML: %w", err)
-	}
+	return playerSelections(container), nil
+}
 
-	var servers []PlexConnectionSelection
+// playerSelections lists the devices on the account that can be played on,
+// one entry per player with its connections in probing order. Controllers,
+// such as the Plex mobile apps acting as a remote, provide no "player" and
+// are left out.
+func playerSelections(container PlexDeviceContainer) []PlexConnectionSelection {
+	var players []PlexConnectionSelection
 	for _, device := range container.Devices {
-		if !strings.Contains(device.Provides, "player") {
+		if !device.ProvidesCapability("player") || len(device.Connections) == 0 {
 			continue
 		}
-		for _, connection := range device.Connections {
-			serverConnection := PlexConnectionSelection{
-				Name:             device.Name,
-				ClientIdentifier: device.ClientIdentifier,
-				Address:          connection.Address,
-				Local:            connection.Local,
-				Port:             connection.Port,
-				URI:              connection.URI,
-			}
-			servers = append(servers, serverConnection)
-		}
+		connections := playerConnections(device.Connections)
+		players = append(players, PlexConnectionSelection{
+			Name:             device.Name,
+			ClientIdentifier: device.ClientIdentifier,
+			Address:          connections[0].Address,
+			Local:            connections[0].Local,
+			Port:             connections[0].Port,
+			URI:              connections[0].URI,
+			Relay:            connections[0].Relay,
+			Connections:      connections,
+		})
 	}
 
-	return servers, nil
+	return players
 }
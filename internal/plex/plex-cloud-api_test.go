@@ -0,0 +1,92 @@
+package plex
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+// resourcesXML is a trimmed /api/resources response: a dedicated server, a
+// headless Plexamp that is both a server and a player, a desktop Plexamp, a
+// phone that only acts as a remote and a device that only provides a player
+// lookalike
+const resourcesXML = `<MediaContainer size="5">
+	<Device name="NAS" product="Plex Media Server" provides="server" clientIdentifier="nas">
+		<Connection protocol="https" address="172.17.0.2" port="32400" uri="https://172-17-0-2.abc.plex.direct:32400" local="1" relay="0"/>
+		<Connection protocol="https" address="203.0.113.5" port="32400" uri="https://203-0-113-5.abc.plex.direct:32400" local="0" relay="0"/>
+	</Device>
+	<Device name="Kitchen Pi" product="Plexamp" provides="server,client,player,pubsub-player" clientIdentifier="pi">
+		<Connection protocol="https" address="203.0.113.9" port="32400" uri="https://relay.plex.direct:8443" local="0" relay="1"/>
+		<Connection protocol="http" address="192.168.1.20" port="32400" uri="http://192.168.1.20:32400" local="1" relay="0"/>
+		<Connection protocol="https" address="192.168.1.20" port="32400" uri="https://192-168-1-20.def.plex.direct:32400" local="1" relay="0"/>
+	</Device>
+	<Device name="Desktop" product="Plexamp" provides="client,player,pubsub-player" clientIdentifier="desktop">
+		<Connection protocol="http" address="192.168.1.30" port="32500" uri="http://192.168.1.30:32500" local="1" relay="0"/>
+	</Device>
+	<Device name="Phone" product="Plex for iOS" provides="client,controller,sync-target" clientIdentifier="phone">
+		<Connection protocol="http" address="192.168.1.40" port="32500" uri="http://192.168.1.40:32500" local="1" relay="0"/>
+	</Device>
+	<Device name="Bridge" product="Companion" provides="pubsub-player" clientIdentifier="bridge">
+		<Connection protocol="http" address="192.168.1.50" port="32500" uri="http://192.168.1.50:32500" local="1" relay="0"/>
+	</Device>
+</MediaContainer>`
+
+func parseResources(t *testing.T) PlexDeviceContainer {
+	t.Helper()
+	var container PlexDeviceContainer
+	if err := xml.Unmarshal([]byte(resourcesXML), &container); err != nil {
+		t.Fatal(err)
+	}
+	return container
+}
+
+func TestServerDevices(t *testing.T) {
+	devices := serverDevices(parseResources(t))
+
+	var names []string
+	for _, device := range devices {
+		names = append(names, device.Name)
+	}
+	if len(names) != 2 || names[0] != "NAS" || names[1] != "Kitchen Pi" {
+		t.Errorf("servers %v, want [NAS Kitchen Pi]", names)
+	}
+}
+
+func TestPlayerSelections(t *testing.T) {
+	players := playerSelections(parseResources(t))
+
+	if len(players) != 2 {
+		t.Fatalf("got %d players, want Kitchen Pi and Desktop: %+v", len(players), players)
+	}
+
+	// The combined device is controlled over its local address, once per
+	// address, and never through the relay
+	pi := players[0]
+	if pi.Name != "Kitchen Pi" || pi.ClientIdentifier != "pi" {
+		t.Fatalf("first player is %q, want Kitchen Pi", pi.Name)
+	}
+	if pi.Address != "192.168.1.20" || pi.Local != "1" || pi.Relay != "0" {
+		t.Errorf("Kitchen Pi connects to %s (local %s, relay %s), want its local address", pi.Address, pi.Local, pi.Relay)
+	}
+	if len(pi.Connections) != 1 {
+		t.Errorf("Kitchen Pi has %d connections to probe, want 1: %+v", len(pi.Connections), pi.Connections)
+	}
+
+	if players[1].Name != "Desktop" || players[1].Address != "192.168.1.30" {
+		t.Errorf("second player is %q at %s, want Desktop at 192.168.1.30", players[1].Name, players[1].Address)
+	}
+}
+
+func TestPlayerSelectionsRelayOnly(t *testing.T) {
+	container := PlexDeviceContainer{Devices: []PlexDeviceInfo{{
+		Name:     "Away",
+		Provides: "player",
+		Connections: []PlexConnection{
+			{Address: "203.0.113.9", Relay: "1"},
+		},
+	}}}
+
+	players := playerSelections(container)
+	if len(players) != 1 || players[0].Address != "203.0.113.9" {
+		t.Errorf("got %+v, want the relayed connection when it is the only one", players)
+	}
+}
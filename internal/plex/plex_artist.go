@@ -0,0 +1,95 @@
+package plex
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+)
+
+// =====================
+// Artist Details
+// =====================
+
+// ArtistDetails is what the library knows about an artist beyond its name
+type ArtistDetails struct {
+	Title      string
+	Summary    string // Biography from the metadata agent, empty when there is none
+	Genres     []string
+	AlbumCount int
+	TrackCount int
+}
+
+// artistMetadataContainer is an artist's full metadata
+type artistMetadataContainer struct {
+	XMLName     xml.Name `xml:"MediaContainer"`
+	Directories []struct {
+		Title      string `xml:"title,attr"`
+		Summary    string `xml:"summary,attr"`
+		ChildCount int    `xml:"childCount,attr"` // Albums
+		Genres     []struct {
+			Tag string `xml:"tag,attr"`
+		} `xml:"Genre"`
+	} `xml:"Directory"`
+}
+
+// FetchArtistDetails retrieves an artist's biography, genres and how many
+// albums and tracks of theirs the library holds
+func (p *PlexClient) FetchArtistDetails(serverAddr, ratingKey, token string) (*ArtistDetails, error) {
+	p.logger.Debug("Fetching details of artist %s", ratingKey)
+
+	urlStr := fmt.Sprintf("%s/library/metadata/%s", ServerBaseURL(serverAddr), ratingKey)
+	var container artistMetadataContainer
+	if err := p.api.GetXML(urlStr, token, &container); err != nil {
+		return nil, p.requestError("failed to fetch artist", err)
+	}
+	if len(container.Directories) == 0 {
+		return nil, fmt.Errorf("artist %s not found", ratingKey)
+	}
+
+	dir := container.Directories[0]
+	details := &ArtistDetails{Title: dir.Title, Summary: dir.Summary, AlbumCount: dir.ChildCount}
+	for _, genre := range dir.Genres {
+		details.Genres = append(details.Genres, genre.Tag)
+	}
+
+	// Older servers leave the album count out of the artist's metadata
+	if details.AlbumCount == 0 {
+		albums, err := p.FetchArtistAlbums(serverAddr, ratingKey, token)
+		if err != nil {
+			return nil, err
+		}
+		details.AlbumCount = len(albums)
+	}
+
+	tracks, err := p.countArtistTracks(serverAddr, ratingKey, token)
+	if err != nil {
+		return nil, err
+	}
+	details.TrackCount = tracks
+
+	return details, nil
+}
+
+// countArtistTracks returns how many tracks of an artist the library holds,
+// asking for the total without the tracks themselves
+func (p *PlexClient) countArtistTracks(serverAddr, ratingKey, token string) (int, error) {
+	urlStr := fmt.Sprintf("%s/library/metadata/%s/allLeaves", ServerBaseURL(serverAddr), ratingKey)
+
+	req, err := p.api.NewRequest(http.MethodGet, urlStr, token)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("X-Plex-Container-Start", "0")
+	req.Header.Set("X-Plex-Container-Size", "0")
+
+	var container PlexMediaContainer
+	if err := p.api.DoXML(req, &container); err != nil {
+		return 0, p.requestError("failed to count artist tracks", err)
+	}
+
+	// Servers that ignore the pagination headers return every track without a totalSize
+	if container.TotalSize > 0 {
+		return container.TotalSize, nil
+	}
+	return container.Size, nil
+}
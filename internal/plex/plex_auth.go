@@ -3,13 +3,19 @@ package plex
 import (
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
+
+	"plexamp-tui/internal/config"
+	"plexamp-tui/internal/plexhttp"
+	"plexamp-tui/internal/version"
 )
 
 // =====================
@@ -20,11 +26,13 @@ const (
 	PlexAPIURL   = "https://plex.tv/api/v2"
 	PlexClientID = "plexamp-tui-" // Will be appended with a unique identifier
 	PlexProduct  = "Plexamp TUI"
-	PlexVersion  = "1.0.0"
 	PlexPlatform = "Linux"
 	PlexDevice   = "Terminal"
 )
 
+// DefaultProfile is the auth profile stored in plex_auth.json
+const DefaultProfile = "default"
+
 // PlexAuthConfig stores the Plex authentication token
 type PlexAuthConfig struct {
 	Token     string    `json:"token"`
@@ -57,8 +65,8 @@ type PlexUser struct {
 	Title    string   `xml:"title,attr"`
 }
 
-// plexAuthConfigPath returns the path to the Plex auth config file
-func plexAuthConfigPath() (string, error) {
+// plexAuthDir returns the directory holding the Plex auth config files
+func plexAuthDir() (string, error) {
 	base := os.Getenv("XDG_CONFIG_HOME")
 	if base == "" {
 		home, err := os.UserHomeDir()
@@ -67,12 +75,57 @@ func plexAuthConfigPath() (string, error) {
 		}
 		base = filepath.Join(home, ".config")
 	}
-	return filepath.Join(base, "plexamp-tui", "plex_auth.json"), nil
+	return filepath.Join(base, "plexamp-tui"), nil
+}
+
+// plexAuthConfigPath returns the path to the Plex auth config file for a profile
+// The default profile keeps the original plex_auth.json filename
+func plexAuthConfigPath(profile string) (string, error) {
+	dir, err := plexAuthDir()
+	if err != nil {
+		return "", err
+	}
+	if profile == "" || profile == DefaultProfile {
+		return filepath.Join(dir, "plex_auth.json"), nil
+	}
+	if strings.ContainsAny(profile, `/\`) || strings.Contains(profile, "..") {
+		return "", fmt.Errorf("invalid profile name %q", profile)
+	}
+	return filepath.Join(dir, fmt.Sprintf("plex_auth_%s.json", profile)), nil
+}
+
+// ListProfiles returns the names of all profiles with a stored auth config
+func ListProfiles() ([]string, error) {
+	dir, err := plexAuthDir()
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, "plex_auth*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles []string
+	for _, match := range matches {
+		name := strings.TrimSuffix(filepath.Base(match), ".json")
+		if name == "plex_auth" {
+			profiles = append(profiles, DefaultProfile)
+		} else if profile := strings.TrimPrefix(name, "plex_auth_"); profile != name && profile != "" {
+			profiles = append(profiles, profile)
+		}
+	}
+	sort.Strings(profiles)
+
+	return profiles, nil
 }
 
-// loadPlexAuthConfig loads the Plex authentication config
-func loadPlexAuthConfig() (*PlexAuthConfig, error) {
-	path, err := plexAuthConfigPath()
+// ErrCorruptAuthConfig is returned when a stored auth config could not be parsed
+var ErrCorruptAuthConfig = errors.New("auth config is not valid JSON")
+
+// loadPlexAuthConfig loads the Plex authentication config for a profile
+func loadPlexAuthConfig(profile string) (*PlexAuthConfig, error) {
+	path, err := plexAuthConfigPath(profile)
 	if err != nil {
 		return nil, err
 	}
@@ -85,17 +138,23 @@ func loadPlexAuthConfig() (*PlexAuthConfig, error) {
 		return nil, err
 	}
 
-	var config PlexAuthConfig
-	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, err
+	var authConfig PlexAuthConfig
+	if err := json.Unmarshal(data, &authConfig); err != nil {
+		// Move the broken file aside so the profile reads as signed out and
+		// --auth can write a fresh one
+		backup, backupErr := config.BackupCorruptFile(path)
+		if backupErr != nil {
+			return nil, fmt.Errorf("auth config is not valid JSON (%v) and could not be backed up: %w", err, backupErr)
+		}
+		return nil, fmt.Errorf("%w (%v), moved it to %s", ErrCorruptAuthConfig, err, backup)
 	}
 
-	return &config, nil
+	return &authConfig, nil
 }
 
-// savePlexAuthConfig saves the Plex authentication config
-func savePlexAuthConfig(config *PlexAuthConfig) error {
-	path, err := plexAuthConfigPath()
+// savePlexAuthConfig saves the Plex authentication config for a profile
+func savePlexAuthConfig(profile string, config *PlexAuthConfig) error {
+	path, err := plexAuthConfigPath(profile)
 	if err != nil {
 		return err
 	}
@@ -129,45 +188,31 @@ func createPlexHeaders() map[string]string {
 	return map[string]string{
 		"X-Plex-Client-Identifier": getClientID(),
 		"X-Plex-Product":           PlexProduct,
-		"X-Plex-Version":           PlexVersion,
+		"X-Plex-Version":           version.Version,
 		"X-Plex-Platform":          PlexPlatform,
 		"X-Plex-Device":            PlexDevice,
-		"Accept":                   "application/json",
 	}
 }
 
-// requestPlexPIN requests a new PIN from Plex for authentication
-func requestPlexPIN() (*PlexPinResponse, error) {
-	client := &http.Client{Timeout: 10 * time.Second}
-
-	// Create the request
-	req, err := http.NewRequest("POST", PlexAPIURL+"/pins?strong=true", nil)
-	if err != nil {
-		return nil, err
-	}
+// authTimeout bounds each request made to plex.tv while signing in or out
+const authTimeout = 10 * time.Second
 
-	// Add headers
-	headers := createPlexHeaders()
-	for key, value := range headers {
-		req.Header.Set(key, value)
-	}
+// authAPI returns the client used for the plex.tv sign in and sign out requests
+func authAPI() *plexhttp.Client {
+	return plexhttp.New(&http.Client{Timeout: authTimeout}, createPlexHeaders())
+}
 
-	// Make the request
-	resp, err := client.Do(req)
+// requestPlexPIN requests a new PIN from Plex for authentication
+func requestPlexPIN() (*PlexPinResponse, error) {
+	api := authAPI()
+	req, err := api.NewRequest(http.MethodPost, PlexAPIURL+"/pins?strong=true", "")
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to create PIN: %s - %s", resp.Status, string(body))
-	}
 
-	// Parse response
 	var pinResp PlexPinResponse
-	if err := json.NewDecoder(resp.Body).Decode(&pinResp); err != nil {
-		return nil, err
+	if err := api.DoJSON(req, &pinResp); err != nil {
+		return nil, fmt.Errorf("failed to create PIN: %w", err)
 	}
 
 	return &pinResp, nil
@@ -175,36 +220,15 @@ func requestPlexPIN() (*PlexPinResponse, error) {
 
 // checkPlexPIN checks if a PIN has been authorized
 func checkPlexPIN(pinID int) (*PlexPinResponse, error) {
-	client := &http.Client{Timeout: 10 * time.Second}
-
-	// Create the request
-	url := fmt.Sprintf("%s/pins/%d", PlexAPIURL, pinID)
-	req, err := http.NewRequest("GET", url, nil)
+	api := authAPI()
+	req, err := api.NewRequest(http.MethodGet, fmt.Sprintf("%s/pins/%d", PlexAPIURL, pinID), "")
 	if err != nil {
 		return nil, err
 	}
 
-	// Add headers
-	headers := createPlexHeaders()
-	for key, value := range headers {
-		req.Header.Set(key, value)
-	}
-
-	// Make the request
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to check PIN: %s", resp.Status)
-	}
-
-	// Parse response
 	var pinResp PlexPinResponse
-	if err := json.NewDecoder(resp.Body).Decode(&pinResp); err != nil {
-		return nil, err
+	if err := api.DoJSON(req, &pinResp); err != nil {
+		return nil, fmt.Errorf("failed to check PIN: %w", err)
 	}
 
 	return &pinResp, nil
@@ -212,43 +236,53 @@ func checkPlexPIN(pinID int) (*PlexPinResponse, error) {
 
 // getPlexUser fetches the current user's information
 func getPlexUser(token string) (*PlexUser, error) {
-	client := &http.Client{Timeout: 10 * time.Second}
+	var user PlexUser
+	if err := authAPI().GetXML("https://plex.tv/users/account", token, &user); err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
 
-	// Create the request
-	req, err := http.NewRequest("GET", "https://plex.tv/users/account", nil)
+	return &user, nil
+}
+
+// signOutPlex invalidates the given token on plex.tv
+func signOutPlex(token string) error {
+	api := authAPI()
+	req, err := api.NewRequest(http.MethodDelete, PlexAPIURL+"/users/signout", token)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	// Add headers
-	headers := createPlexHeaders()
-	headers["X-Plex-Token"] = token
-	for key, value := range headers {
-		req.Header.Set(key, value)
+	if _, err := api.Do(req); err != nil {
+		return fmt.Errorf("failed to sign out: %w", err)
 	}
 
-	// Make the request
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
+	return nil
+}
+
+// ClearPlexAuth signs the stored token out of plex.tv and deletes the local auth config.
+// A failed server-side sign out is logged but does not stop the local token from being removed.
+func (p *PlexClient) ClearPlexAuth() error {
+	if token := p.GetPlexToken(); token != "" {
+		if err := signOutPlex(token); err != nil {
+			p.logger.Warn("Failed to invalidate Plex token server-side: %v", err)
+		}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get user info: %s", resp.Status)
+	path, err := plexAuthConfigPath(p.authProfile())
+	if err != nil {
+		return err
 	}
 
-	// Parse XML response
-	var user PlexUser
-	if err := xml.NewDecoder(resp.Body).Decode(&user); err != nil {
-		return nil, err
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove auth config: %w", err)
 	}
 
-	return &user, nil
+	return nil
 }
 
-// AuthenticateWithPlex performs the full Plex authentication flow
-func (p *PlexClient) AuthenticateWithPlex() (*PlexAuthConfig, error) {
+// AuthenticateWithPlex performs the full Plex authentication flow and stores
+// the token under the given profile name (empty for the default profile)
+func (p *PlexClient) AuthenticateWithPlex(profile string) (*PlexAuthConfig, error) {
 	// Request a PIN
 	pin, err := requestPlexPIN()
 	if err != nil {
@@ -303,7 +337,7 @@ func (p *PlexClient) AuthenticateWithPlex() (*PlexAuthConfig, error) {
 					fmt.Printf("Logged in as: %s\n", user.Username)
 				}
 
-				if err := savePlexAuthConfig(config); err != nil {
+				if err := savePlexAuthConfig(profile, config); err != nil {
 					return nil, fmt.Errorf("failed to save auth config: %w", err)
 				}
 
@@ -315,7 +349,7 @@ func (p *PlexClient) AuthenticateWithPlex() (*PlexAuthConfig, error) {
 
 // isPlexAuthenticated checks if we have a valid Plex token
 func isPlexAuthenticated() bool {
-	config, err := loadPlexAuthConfig()
+	config, err := loadPlexAuthConfig("")
 	if err != nil || config == nil || config.Token == "" {
 		return false
 	}
@@ -324,13 +358,26 @@ func isPlexAuthenticated() bool {
 
 // GetPlexToken returns the stored Plex token, or empty string if not authenticated
 func (p *PlexClient) GetPlexToken() string {
-	config, err := loadPlexAuthConfig()
+	config, err := loadPlexAuthConfig(p.authProfile())
+	if errors.Is(err, ErrCorruptAuthConfig) {
+		p.logger.Warn("Treating profile %s as signed out: %v", p.Profile(), err)
+	}
 	if err != nil || config == nil {
 		return ""
 	}
 	return config.Token
 }
 
+// IsTokenExpired reports whether the stored token is past its ExpiresAt time.
+// Tokens without an expiry are treated as not expired.
+func (p *PlexClient) IsTokenExpired() bool {
+	config, err := loadPlexAuthConfig(p.authProfile())
+	if err != nil || config == nil || config.ExpiresAt.IsZero() {
+		return false
+	}
+	return time.Now().After(config.ExpiresAt)
+}
+
 // VerifyPlexAuthentication checks if the stored token is valid by making a test API call
 func (p *PlexClient) VerifyPlexAuthentication() bool {
 	token := p.GetPlexToken()
@@ -338,6 +385,11 @@ func (p *PlexClient) VerifyPlexAuthentication() bool {
 		return false
 	}
 
+	if p.IsTokenExpired() {
+		p.logger.Warn("Stored Plex token has expired")
+		return false
+	}
+
 	// Try to get user info to verify token is valid
 	_, err := getPlexUser(token)
 	return err == nil
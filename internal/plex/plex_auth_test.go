@@ -0,0 +1,39 @@
+package plex
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadBrokenAuthConfig(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path, err := plexAuthConfigPath(DefaultProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"auth_token": `), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	authConfig, err := loadPlexAuthConfig(DefaultProfile)
+	if !errors.Is(err, ErrCorruptAuthConfig) {
+		t.Fatalf("got error %v, want ErrCorruptAuthConfig", err)
+	}
+	if authConfig != nil {
+		t.Errorf("got auth config %+v from a broken file", authConfig)
+	}
+	if _, err := os.Stat(path + ".bak"); err != nil {
+		t.Errorf("broken file wasn't backed up: %v", err)
+	}
+
+	// The profile now reads as signed out rather than failing every time
+	authConfig, err = loadPlexAuthConfig(DefaultProfile)
+	if err != nil || authConfig != nil {
+		t.Errorf("second load got %+v, %v; want a signed out profile", authConfig, err)
+	}
+}
@@ -1,15 +1,145 @@
 package plex
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
 	"plexamp-tui/internal/logger"
+	"plexamp-tui/internal/plexhttp"
 )
 
+// ErrUnauthorized is returned when Plex rejects the stored token, either
+// because it expired or because it was revoked server-side
+var ErrUnauthorized = plexhttp.ErrUnauthorized
+
+// DefaultRequestTimeout is used when no request timeout is configured
+const DefaultRequestTimeout = 15 * time.Second
+
 type PlexClient struct {
-	logger *logger.Logger
+	logger     *logger.Logger
+	httpClient *http.Client
+	api        *plexhttp.Client // Sends requests through httpClient with the Plex headers
+	profile    string           // Auth profile whose token is used, empty for the default profile
+
+	profileMu sync.RWMutex // Guards profile, which the UI switches while requests read it
 }
 
-func NewPlexClient(logger *logger.Logger) *PlexClient {
+func NewPlexClient(logger *logger.Logger, timeout time.Duration) *PlexClient {
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+	httpClient := &http.Client{Timeout: timeout}
 	return &PlexClient{
-		logger: logger,
+		logger:     logger,
+		httpClient: httpClient,
+		api:        plexhttp.New(httpClient, createPlexHeaders()),
+	}
+}
+
+// SetProfile selects the auth profile whose token is used for requests
+func (p *PlexClient) SetProfile(profile string) {
+	if profile == DefaultProfile {
+		profile = ""
+	}
+	p.profileMu.Lock()
+	defer p.profileMu.Unlock()
+	p.profile = profile
+}
+
+// authProfile returns the profile whose auth config is used, empty for the default profile
+func (p *PlexClient) authProfile() string {
+	p.profileMu.RLock()
+	defer p.profileMu.RUnlock()
+	return p.profile
+}
+
+// Profile returns the name of the active auth profile
+func (p *PlexClient) Profile() string {
+	profile := p.authProfile()
+	if profile == "" {
+		return DefaultProfile
+	}
+	return profile
+}
+
+// HTTPClient returns the shared HTTP client used for Plex server and player requests
+func (p *PlexClient) HTTPClient() *http.Client {
+	return p.httpClient
+}
+
+// retryBackoff holds the delays before each retry of a player command
+var retryBackoff = []time.Duration{200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond}
+
+// GetWithRetry performs a GET request, retrying with exponential backoff on
+// connection errors and 5xx responses. 4xx responses are returned as-is since
+// repeating the same request will not change the outcome. Other transport
+// errors, such as a timeout waiting for the response, aren't retried either:
+// the request may already have reached the player, and commands like
+// skipNext must not run twice.
+func (p *PlexClient) GetWithRetry(urlStr string) (*http.Response, error) {
+	var lastErr error
+	for attempt := 0; attempt <= len(retryBackoff); attempt++ {
+		if attempt > 0 {
+			time.Sleep(retryBackoff[attempt-1])
+		}
+
+		resp, err := p.httpClient.Get(urlStr)
+		if err != nil {
+			p.logger.Warn("Request attempt %d/%d failed: %v", attempt+1, len(retryBackoff)+1, err)
+			if !notSent(err) {
+				return nil, err
+			}
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			resp.Body.Close()
+			lastErr = fmt.Errorf("server returned status %d", resp.StatusCode)
+			p.logger.Warn("Request attempt %d/%d failed: %v", attempt+1, len(retryBackoff)+1, lastErr)
+			continue
+		}
+		return resp, nil
+	}
+	return nil, lastErr
+}
+
+// notSent reports whether err means the request never reached the other end,
+// so sending it again can't repeat its effect
+func notSent(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// ServerBaseURL returns the base URL for a Plex server. A full URI (such as
+// an HTTPS or relay connection) is used verbatim; a bare address:port is
+// assumed to be plain HTTP.
+func ServerBaseURL(server string) string {
+	if strings.Contains(server, "://") {
+		return strings.TrimSuffix(server, "/")
+	}
+	return "http://" + server
+}
+
+// statusError converts an unexpected response status into an error
+func statusError(statusCode int) error {
+	return plexhttp.ErrorForStatus(statusCode)
+}
+
+// requestError wraps a failed request, spelling out timeouts so they are
+// distinguishable from other connection errors
+func (p *PlexClient) requestError(action string, err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf("%s: request timed out after %s", action, p.httpClient.Timeout)
 	}
+	return fmt.Errorf("%s: %w", action, err)
 }
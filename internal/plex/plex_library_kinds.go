@@ -0,0 +1,76 @@
+package plex
+
+import "strings"
+
+// Kinds of content a music library can hold. Plex keeps audiobooks and
+// podcasts in music sections too, filing authors and podcasts as artists,
+// books and shows as albums and chapters and episodes as tracks.
+const (
+	KindMusic      = "music"
+	KindAudiobooks = "audiobooks"
+	KindPodcasts   = "podcasts"
+)
+
+// Metadata types listed by /library/sections/<id>/all?type=. Plex files the
+// authors and podcasts of audiobook and podcast libraries under the same
+// types as artists and albums, so these are the defaults for every kind; a
+// library whose agent files them differently sets its own.
+const (
+	TypeArtist = 8
+	TypeAlbum  = 9
+)
+
+// LibraryTypes describes the levels of a library: the metadata types listed
+// as its artists and albums and what each level is called
+type LibraryTypes struct {
+	ArtistType int // Metadata type listed as artists, TypeArtist unless set
+	AlbumType  int // Metadata type listed as albums, TypeAlbum unless set
+
+	Artists string // Plural names, e.g. "Authors", "Books" and "Chapters"
+	Albums  string
+	Tracks  string
+	Track   string // A single track, used to number chapters and episodes
+}
+
+// libraryTypes holds the levels of each kind of library
+var libraryTypes = map[string]LibraryTypes{
+	KindMusic:      {ArtistType: TypeArtist, AlbumType: TypeAlbum, Artists: "Artists", Albums: "Albums", Tracks: "Tracks"},
+	KindAudiobooks: {ArtistType: TypeArtist, AlbumType: TypeAlbum, Artists: "Authors", Albums: "Books", Tracks: "Chapters", Track: "Chapter"},
+	KindPodcasts:   {ArtistType: TypeArtist, AlbumType: TypeAlbum, Artists: "Podcasts", Albums: "Shows", Tracks: "Episodes", Track: "Episode"},
+}
+
+// TypesForKind returns the levels of a kind of library, treating unknown
+// kinds as music
+func TypesForKind(kind string) LibraryTypes {
+	if types, ok := libraryTypes[kind]; ok {
+		return types
+	}
+	return libraryTypes[KindMusic]
+}
+
+// WithTypes returns the levels with the metadata types listed as artists and
+// albums replaced, leaving either unchanged when it is 0
+func (t LibraryTypes) WithTypes(artistType, albumType int) LibraryTypes {
+	if artistType != 0 {
+		t.ArtistType = artistType
+	}
+	if albumType != 0 {
+		t.AlbumType = albumType
+	}
+	return t
+}
+
+// DetectKind guesses what a music library holds from its metadata agent and
+// title, since Plex has no section type for audiobooks or podcasts
+func DetectKind(title, agent string) string {
+	title, agent = strings.ToLower(title), strings.ToLower(agent)
+	switch {
+	case strings.Contains(agent, "audnexus"), strings.Contains(agent, "audiobook"),
+		strings.Contains(title, "audiobook"), strings.Contains(title, "audio book"):
+		return KindAudiobooks
+	case strings.Contains(agent, "podcast"), strings.Contains(title, "podcast"):
+		return KindPodcasts
+	default:
+		return KindMusic
+	}
+}
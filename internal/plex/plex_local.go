@@ -3,11 +3,12 @@ package plex
 import (
 	"encoding/xml"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"plexamp-tui/internal/config"
+	"plexamp-tui/internal/plexhttp"
 	"sort"
+	"strconv"
 )
 
 // =====================
@@ -24,6 +25,7 @@ type PlexLibrary struct {
 	Key   string `xml:"key,attr"`
 	Title string `xml:"title,attr"`
 	Type  string `xml:"type,attr"`
+	Agent string `xml:"agent,attr"` // Metadata agent, a hint to what a music library holds
 }
 
 // PlexDirectory represents a generic directory item from Plex
@@ -34,6 +36,15 @@ type PlexDirectory struct {
 	Type        string   `xml:"type,attr"`
 	ParentTitle string   `xml:"parentTitle,attr"` // For albums
 	Year        string   `xml:"year,attr"`
+	UserRating  float64  `xml:"userRating,attr"` // 0-10, missing when unrated
+
+	// Play statistics of the signed in user; zero when never played
+	ViewCount    int   `xml:"viewCount,attr"`
+	LastViewedAt int64 `xml:"lastViewedAt,attr"` // Unix time
+
+	AddedAt int64 `xml:"addedAt,attr"` // Unix time the item was added to the library
+
+	ViewOffset int `xml:"viewOffset,attr"` // Resume position in milliseconds, 0 when not partly played
 }
 
 // PlexArtist represents an artist from the Plex library
@@ -41,15 +52,56 @@ type PlexArtist struct {
 	RatingKey string `xml:"ratingKey,attr"`
 	Title     string `xml:"title,attr"`
 	Type      string `xml:"type,attr"`
+
+	UserRating   float64 `xml:"userRating,attr"` // 0-10, missing when unrated
+	ViewCount    int     `xml:"viewCount,attr"`
+	LastViewedAt int64   `xml:"lastViewedAt,attr"` // Unix time
+	AddedAt      int64   `xml:"addedAt,attr"`      // Unix time
+}
+
+// artistFromDirectory converts an artist Directory entry into a PlexArtist
+func artistFromDirectory(dir PlexDirectory) PlexArtist {
+	return PlexArtist{
+		RatingKey:    dir.RatingKey,
+		Title:        dir.Title,
+		Type:         dir.Type,
+		UserRating:   dir.UserRating,
+		ViewCount:    dir.ViewCount,
+		LastViewedAt: dir.LastViewedAt,
+		AddedAt:      dir.AddedAt,
+	}
 }
 
 // PlexAlbum represents an album from the Plex library
 type PlexAlbum struct {
-	RatingKey   string `xml:"ratingKey,attr"`
-	Title       string `xml:"title,attr"`
-	ParentTitle string `xml:"parentTitle,attr"` // Artist name
-	Year        string `xml:"year,attr"`
-	Type        string `xml:"type,attr"`
+	RatingKey   string  `xml:"ratingKey,attr"`
+	Title       string  `xml:"title,attr"`
+	ParentTitle string  `xml:"parentTitle,attr"` // Artist name
+	Year        string  `xml:"year,attr"`
+	Type        string  `xml:"type,attr"`
+	UserRating  float64 `xml:"userRating,attr"` // 0-10, missing when unrated
+
+	ViewCount    int   `xml:"viewCount,attr"`
+	LastViewedAt int64 `xml:"lastViewedAt,attr"` // Unix time
+	AddedAt      int64 `xml:"addedAt,attr"`      // Unix time
+
+	ViewOffset int `xml:"viewOffset,attr"` // Resume position in milliseconds
+}
+
+// albumFromDirectory converts an album Directory entry into a PlexAlbum
+func albumFromDirectory(dir PlexDirectory) PlexAlbum {
+	return PlexAlbum{
+		RatingKey:    dir.RatingKey,
+		Title:        dir.Title,
+		ParentTitle:  dir.ParentTitle,
+		Year:         dir.Year,
+		Type:         dir.Type,
+		UserRating:   dir.UserRating,
+		ViewCount:    dir.ViewCount,
+		LastViewedAt: dir.LastViewedAt,
+		AddedAt:      dir.AddedAt,
+		ViewOffset:   dir.ViewOffset,
+	}
 }
 
 // PlexPlaylist represents a playlist from the Plex library
@@ -59,13 +111,34 @@ type PlexPlaylist struct {
 	Type      string `xml:"playlistType,attr"`
 }
 
+// PlexTrack represents a track from the Plex library
+type PlexTrack struct {
+	RatingKey        string  `xml:"ratingKey,attr"`
+	Title            string  `xml:"title,attr"`
+	Index            string  `xml:"index,attr"`
+	ParentTitle      string  `xml:"parentTitle,attr"`      // Album name
+	GrandparentTitle string  `xml:"grandparentTitle,attr"` // Artist name
+	Duration         int     `xml:"duration,attr"`
+	Type             string  `xml:"type,attr"`
+	UserRating       float64 `xml:"userRating,attr"` // 0-10, missing when unrated
+
+	ViewOffset int `xml:"viewOffset,attr"` // Resume position in milliseconds, 0 when not partly played
+}
+
 // PlexMediaContainer is the root element for Plex API responses
 type PlexMediaContainer struct {
 	XMLName     xml.Name        `xml:"MediaContainer"`
 	Size        int             `xml:"size,attr"`
+	TotalSize   int             `xml:"totalSize,attr"` // Only set on paginated responses
 	Directories []PlexDirectory `xml:"Directory"`
 }
 
+type PlexTrackContainer struct {
+	XMLName xml.Name    `xml:"MediaContainer"`
+	Size    int         `xml:"size,attr"`
+	Tracks  []PlexTrack `xml:"Track"`
+}
+
 type PlexPlaylistContainer struct {
 	XMLName   xml.Name       `xml:"MediaContainer"`
 	Size      int            `xml:"size,attr"`
@@ -76,101 +149,140 @@ type PlexPlaylistContainer struct {
 // Library Fetching
 // =====================
 
-// FetchArtists retrieves all artists from the Plex library
-func (p *PlexClient) FetchArtists(serverAddr, libraryID, token string) ([]PlexArtist, error) {
-	urlStr := fmt.Sprintf("http://%s/library/sections/%s/all?type=8&X-Plex-Token=%s",
-		serverAddr, libraryID, url.QueryEscape(token))
+// FetchArtists retrieves all artists from the Plex library, listing the
+// artist type of the library
+func (p *PlexClient) FetchArtists(serverAddr, libraryID string, types LibraryTypes, token string) ([]PlexArtist, error) {
+	urlStr := fmt.Sprintf("%s/library/sections/%s/all?type=%d",
+		ServerBaseURL(serverAddr), libraryID, types.ArtistType)
 
-	p.logger.Debug(fmt.Sprintf("Fetching artists from: %s", urlStr))
+	p.logger.Debug("Fetching artists from: %s", plexhttp.RedactToken(urlStr))
 
-	resp, err := http.Get(urlStr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch artists: %w", err)
+	var container PlexMediaContainer
+	if err := p.api.GetXML(urlStr, token, &container); err != nil {
+		p.logger.Debug("Failed to fetch artists: %v", err)
+		return nil, p.requestError("failed to fetch artists", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		p.logger.Debug(fmt.Sprintf("Server returned status %d", resp.StatusCode))
-		return nil, fmt.Errorf("server returned status %d", resp.StatusCode)
+	var artists []PlexArtist
+	for _, dir := range container.Directories {
+		if dir.Type == "artist" {
+			artists = append(artists, artistFromDirectory(dir))
+		}
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	p.logger.Debug("Fetched %d artists", len(artists))
+
+	// Sort artists alphabetically by title
+	sort.Slice(artists, func(i, j int) bool {
+		return artists[i].Title < artists[j].Title
+	})
+
+	return artists, nil
+}
+
+// FetchArtistsPage retrieves a single page of artists from the Plex library in
+// the server-side order sortBy, such as "titleSort" or "addedAt:desc".
+// It returns the artists in the page along with the total number of artists in the library.
+func (p *PlexClient) FetchArtistsPage(serverAddr, libraryID string, types LibraryTypes, token, sortBy string, start, size int) ([]PlexArtist, int, error) {
+	urlStr := fmt.Sprintf("%s/library/sections/%s/all?type=%d&sort=%s",
+		ServerBaseURL(serverAddr), libraryID, types.ArtistType, url.QueryEscape(sortBy))
+
+	p.logger.Debug("Fetching artists page (start: %d, size: %d, sort: %s) from library %s", start, size, sortBy, libraryID)
+
+	req, err := p.api.NewRequest(http.MethodGet, urlStr, token)
 	if err != nil {
-		p.logger.Debug(fmt.Sprintf("Failed to read response: %v", err))
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, 0, err
 	}
+	req.Header.Set("X-Plex-Container-Start", strconv.Itoa(start))
+	req.Header.Set("X-Plex-Container-Size", strconv.Itoa(size))
 
 	var container PlexMediaContainer
-	if err := xml.Unmarshal(body, &container); err != nil {
-		p.logger.Debug(fmt.Sprintf("Failed to parse XML: %v", err))
-		return nil, fmt.Errorf("failed to parse XML: %w", err)
+	if err := p.api.DoXML(req, &container); err != nil {
+		p.logger.Debug("Failed to fetch artists page: %v", err)
+		return nil, 0, p.requestError("failed to fetch artists", err)
 	}
 
 	var artists []PlexArtist
 	for _, dir := range container.Directories {
 		if dir.Type == "artist" {
-			artists = append(artists, PlexArtist{
-				RatingKey: dir.RatingKey,
-				Title:     dir.Title,
-				Type:      dir.Type,
-			})
+			artists = append(artists, artistFromDirectory(dir))
 		}
 	}
 
-	p.logger.Debug(fmt.Sprintf("Fetched %d artists", len(artists)))
+	// Servers that ignore the pagination headers return everything without a totalSize
+	total := container.TotalSize
+	if total == 0 {
+		total = start + container.Size
+	}
 
-	// Sort artists alphabetically by title
-	sort.Slice(artists, func(i, j int) bool {
-		return artists[i].Title < artists[j].Title
-	})
+	p.logger.Debug("Fetched %d artists (total: %d)", len(artists), total)
 
-	return artists, nil
+	return artists, total, nil
 }
 
-// FetchAlbums retrieves all albums from the Plex library
-func (p *PlexClient) FetchAlbums(serverAddr, libraryID, token string) ([]PlexAlbum, error) {
-	urlStr := fmt.Sprintf("http://%s/library/sections/%s/all?type=9&X-Plex-Token=%s",
-		serverAddr, libraryID, url.QueryEscape(token))
+// TopArtists retrieves the n most played artists of the library, most played
+// first. Artists that have never been played are left out, so fewer than n
+// may be returned.
+func (p *PlexClient) TopArtists(serverAddr, libraryID string, types LibraryTypes, token string, n int) ([]PlexArtist, error) {
+	urlStr := fmt.Sprintf("%s/library/sections/%s/all?type=%d&sort=viewCount:desc",
+		ServerBaseURL(serverAddr), libraryID, types.ArtistType)
 
-	p.logger.Debug(fmt.Sprintf("Fetching albums from: %s", urlStr))
+	p.logger.Debug("Fetching the %d most played artists of library %s", n, libraryID)
 
-	resp, err := http.Get(urlStr)
+	req, err := p.api.NewRequest(http.MethodGet, urlStr, token)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch albums: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
+	req.Header.Set("X-Plex-Container-Start", "0")
+	req.Header.Set("X-Plex-Container-Size", strconv.Itoa(n))
 
-	if resp.StatusCode != http.StatusOK {
-		p.logger.Debug(fmt.Sprintf("Server returned status %d", resp.StatusCode))
-		return nil, fmt.Errorf("server returned status %d", resp.StatusCode)
+	var container PlexMediaContainer
+	if err := p.api.DoXML(req, &container); err != nil {
+		return nil, p.requestError("failed to fetch top artists", err)
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		p.logger.Debug(fmt.Sprintf("Failed to read response: %v", err))
-		return nil, fmt.Errorf("failed to read response: %w", err)
+	var artists []PlexArtist
+	for _, dir := range container.Directories {
+		if dir.Type == "artist" && dir.ViewCount > 0 {
+			artists = append(artists, artistFromDirectory(dir))
+		}
+	}
+
+	// Servers that ignore the sort or the page size still get the right artists
+	sort.SliceStable(artists, func(i, j int) bool {
+		return artists[i].ViewCount > artists[j].ViewCount
+	})
+	if len(artists) > n {
+		artists = artists[:n]
 	}
 
+	p.logger.Debug("Fetched %d top artists", len(artists))
+
+	return artists, nil
+}
+
+// FetchAlbums retrieves all albums from the Plex library, listing the album
+// type of the library
+func (p *PlexClient) FetchAlbums(serverAddr, libraryID string, types LibraryTypes, token string) ([]PlexAlbum, error) {
+	urlStr := fmt.Sprintf("%s/library/sections/%s/all?type=%d",
+		ServerBaseURL(serverAddr), libraryID, types.AlbumType)
+
+	p.logger.Debug("Fetching albums from: %s", plexhttp.RedactToken(urlStr))
+
 	var container PlexMediaContainer
-	if err := xml.Unmarshal(body, &container); err != nil {
-		p.logger.Debug(fmt.Sprintf("Failed to parse XML: %v", err))
-		return nil, fmt.Errorf("failed to parse XML: %w", err)
+	if err := p.api.GetXML(urlStr, token, &container); err != nil {
+		p.logger.Debug("Failed to fetch albums: %v", err)
+		return nil, p.requestError("failed to fetch albums", err)
 	}
 
 	var albums []PlexAlbum
 	for _, dir := range container.Directories {
 		if dir.Type == "album" {
-			albums = append(albums, PlexAlbum{
-				RatingKey:   dir.RatingKey,
-				Title:       dir.Title,
-				ParentTitle: dir.ParentTitle,
-				Year:        dir.Year,
-				Type:        dir.Type,
-			})
+			albums = append(albums, albumFromDirectory(dir))
 		}
 	}
 
-	p.logger.Debug(fmt.Sprintf("Fetched %d albums", len(albums)))
+	p.logger.Debug("Fetched %d albums", len(albums))
 
 	// Sort albums alphabetically by title
 	sort.Slice(albums, func(i, j int) bool {
@@ -182,89 +294,100 @@ func (p *PlexClient) FetchAlbums(serverAddr, libraryID, token string) ([]PlexAlb
 
 // FetchArtistAlbums retrieves albums for a specific artist
 func (p *PlexClient) FetchArtistAlbums(serverAddr, artistRatingKey, token string) ([]PlexAlbum, error) {
-	urlStr := fmt.Sprintf("http://%s/library/metadata/%s/children?X-Plex-Token=%s",
-		serverAddr, artistRatingKey, url.QueryEscape(token))
+	urlStr := fmt.Sprintf("%s/library/metadata/%s/children",
+		ServerBaseURL(serverAddr), artistRatingKey)
 
-	p.logger.Debug(fmt.Sprintf("Fetching albums for artist %s from: %s", artistRatingKey, urlStr))
+	p.logger.Debug("Fetching albums for artist %s from: %s", artistRatingKey, plexhttp.RedactToken(urlStr))
 
-	resp, err := http.Get(urlStr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch artist albums: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("server returned status %d", resp.StatusCode)
+	var container PlexMediaContainer
+	if err := p.api.GetXML(urlStr, token, &container); err != nil {
+		return nil, p.requestError("failed to fetch artist albums", err)
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+	albums := []PlexAlbum{}
+	for _, dir := range container.Directories {
+		if dir.Type == "album" {
+			albums = append(albums, albumFromDirectory(dir))
+		}
 	}
 
-	var container PlexMediaContainer
-	if err := xml.Unmarshal(body, &container); err != nil {
-		return nil, fmt.Errorf("failed to parse XML: %w", err)
-	}
+	p.logger.Debug("Fetched %d albums for artist %s", len(albums), artistRatingKey)
 
-	return []PlexAlbum{}, nil
+	return albums, nil
 }
 
-func (p *PlexClient) FetchPlaylists(serverAddr, token string) ([]PlexPlaylist, error) {
-	urlStr := fmt.Sprintf("http://%s/playlists?X-Plex-Token=%s", serverAddr, url.QueryEscape(token))
+// FetchAlbumTracks retrieves the tracks for a specific album in album order
+func (p *PlexClient) FetchAlbumTracks(serverAddr, albumRatingKey, token string) ([]PlexTrack, error) {
+	urlStr := fmt.Sprintf("%s/library/metadata/%s/children",
+		ServerBaseURL(serverAddr), albumRatingKey)
 
-	p.logger.Debug(fmt.Sprintf("Fetching playlists from: %s", urlStr))
+	p.logger.Debug("Fetching tracks for album %s", albumRatingKey)
 
-	resp, err := http.Get(urlStr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch playlists: %w", err)
+	var container PlexTrackContainer
+	if err := p.api.GetXML(urlStr, token, &container); err != nil {
+		return nil, p.requestError("failed to fetch album tracks", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("server returned status %d", resp.StatusCode)
+	var tracks []PlexTrack
+	for _, track := range container.Tracks {
+		if track.Type == "track" {
+			tracks = append(tracks, track)
+		}
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	p.logger.Debug("Fetched %d tracks", len(tracks))
+
+	return tracks, nil
+}
+
+// RateItem sets the user rating of a library item on a 0-10 scale, with 0
+// clearing it. Ratings are stored by the server, so they show up in every
+// Plex client.
+func (p *PlexClient) RateItem(serverAddr, ratingKey string, rating int, token string) error {
+	urlStr := fmt.Sprintf("%s/:/rate?key=%s&identifier=com.plexapp.plugins.library&rating=%d",
+		ServerBaseURL(serverAddr), url.QueryEscape(ratingKey), rating)
+
+	p.logger.Debug("Rating %s as %d", ratingKey, rating)
+
+	req, err := p.api.NewRequest(http.MethodPut, urlStr, token)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return err
 	}
 
-	var container PlexPlaylistContainer
-	if err := xml.Unmarshal(body, &container); err != nil {
-		return nil, fmt.Errorf("failed to parse XML: %w", err)
+	if _, err := p.api.Do(req); err != nil {
+		return p.requestError("failed to rate item", err)
 	}
 
-	return container.Playlists, nil
+	return nil
 }
 
-func (p *PlexClient) FetchLibrary(serverAddr string) ([]config.PlexLibrary, error) {
-	token := p.GetPlexToken()
-	urlStr := fmt.Sprintf("http://%s/library/sections?X-Plex-Token=%s", serverAddr, url.QueryEscape(token))
+func (p *PlexClient) FetchPlaylists(serverAddr, token string) ([]PlexPlaylist, error) {
+	urlStr := fmt.Sprintf("%s/playlists", ServerBaseURL(serverAddr))
 
-	p.logger.Debug(fmt.Sprintf("Fetching library from: %s", urlStr))
+	p.logger.Debug("Fetching playlists from: %s", plexhttp.RedactToken(urlStr))
 
-	resp, err := http.Get(urlStr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch library: %w", err)
+	var container PlexPlaylistContainer
+	if err := p.api.GetXML(urlStr, token, &container); err != nil {
+		return nil, p.requestError("failed to fetch playlists", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("server returned status %d", resp.StatusCode)
-	}
+	return container.Playlists, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
+// FetchLibraries retrieves the music libraries of a server from
+// /library/sections, given either its address:port or the full URI of the
+// connection to use. Libraries of any other type are left out.
+func (p *PlexClient) FetchLibraries(serverAddr, token string) ([]config.PlexLibrary, error) {
+	urlStr := fmt.Sprintf("%s/library/sections", ServerBaseURL(serverAddr))
+
+	p.logger.Debug("Fetching libraries from: %s", ServerBaseURL(serverAddr))
 
 	var container PlexLibraryContainer
-	if err := xml.Unmarshal(body, &container); err != nil {
-		return nil, fmt.Errorf("failed to parse XML: %w", err)
+	if err := p.api.GetXML(urlStr, token, &container); err != nil {
+		return nil, p.requestError("failed to fetch libraries", err)
 	}
 
-	p.logger.Debug(fmt.Sprintf("Fetched %d libraries", len(container.Libraries)))
+	p.logger.Debug("Fetched %d libraries", len(container.Libraries))
 	// filter just artist libraries
 	var libraries []config.PlexLibrary
 	for _, lib := range container.Libraries {
@@ -273,11 +396,99 @@ func (p *PlexClient) FetchLibrary(serverAddr string) ([]config.PlexLibrary, erro
 				Key:   lib.Key,
 				Title: lib.Title,
 				Type:  lib.Type,
+				Kind:  DetectKind(lib.Title, lib.Agent),
 			})
 		}
 	}
 
-	p.logger.Debug(fmt.Sprintf("Fetched %d artist libraries", len(libraries)))
+	p.logger.Debug("Fetched %d artist libraries", len(libraries))
 
 	return libraries, nil
 }
+
+// PlexFilterValue is a value libraries can be filtered by, such as a genre or decade
+type PlexFilterValue struct {
+	Key   string `xml:"key,attr"`
+	Title string `xml:"title,attr"`
+}
+
+type PlexFilterContainer struct {
+	XMLName xml.Name          `xml:"MediaContainer"`
+	Size    int               `xml:"size,attr"`
+	Values  []PlexFilterValue `xml:"Directory"`
+}
+
+// FetchGenres retrieves the genres used in the Plex library
+func (p *PlexClient) FetchGenres(serverAddr, libraryID string, types LibraryTypes, token string) ([]PlexFilterValue, error) {
+	return p.fetchFilterValues(serverAddr, libraryID, types, "genre", token)
+}
+
+// FetchDecades retrieves the decades albums in the Plex library were released in
+func (p *PlexClient) FetchDecades(serverAddr, libraryID string, types LibraryTypes, token string) ([]PlexFilterValue, error) {
+	return p.fetchFilterValues(serverAddr, libraryID, types, "decade", token)
+}
+
+// fetchFilterValues retrieves the values of a library filter such as genre or decade
+func (p *PlexClient) fetchFilterValues(serverAddr, libraryID string, types LibraryTypes, filter, token string) ([]PlexFilterValue, error) {
+	urlStr := fmt.Sprintf("%s/library/sections/%s/%s?type=%d",
+		ServerBaseURL(serverAddr), libraryID, filter, types.AlbumType)
+
+	p.logger.Debug("Fetching %s values for library %s", filter, libraryID)
+
+	var container PlexFilterContainer
+	if err := p.api.GetXML(urlStr, token, &container); err != nil {
+		return nil, p.requestError("failed to fetch "+filter+"s", err)
+	}
+
+	p.logger.Debug("Fetched %d %s values", len(container.Values), filter)
+
+	return container.Values, nil
+}
+
+// FetchFilteredAlbums retrieves the albums matching a library filter, e.g. genre=123 or decade=1990
+func (p *PlexClient) FetchFilteredAlbums(serverAddr, libraryID string, types LibraryTypes, filter, value, token string) ([]PlexAlbum, error) {
+	urlStr := fmt.Sprintf("%s/library/sections/%s/all?type=%d&%s=%s&sort=titleSort",
+		ServerBaseURL(serverAddr), libraryID, types.AlbumType, url.QueryEscape(filter), url.QueryEscape(value))
+
+	p.logger.Debug("Fetching albums for %s=%s", filter, value)
+
+	var container PlexMediaContainer
+	if err := p.api.GetXML(urlStr, token, &container); err != nil {
+		return nil, p.requestError("failed to fetch albums", err)
+	}
+
+	var albums []PlexAlbum
+	for _, dir := range container.Directories {
+		if dir.Type == "album" {
+			albums = append(albums, albumFromDirectory(dir))
+		}
+	}
+
+	p.logger.Debug("Fetched %d albums for %s=%s", len(albums), filter, value)
+
+	return albums, nil
+}
+
+// FetchFilteredArtists retrieves the artists in a library matching a filter, such as title=<text>
+func (p *PlexClient) FetchFilteredArtists(serverAddr, libraryID string, types LibraryTypes, filter, value, token string) ([]PlexArtist, error) {
+	urlStr := fmt.Sprintf("%s/library/sections/%s/all?type=%d&%s=%s&sort=titleSort",
+		ServerBaseURL(serverAddr), libraryID, types.ArtistType, url.QueryEscape(filter), url.QueryEscape(value))
+
+	p.logger.Debug("Fetching artists for %s=%s", filter, value)
+
+	var container PlexMediaContainer
+	if err := p.api.GetXML(urlStr, token, &container); err != nil {
+		return nil, p.requestError("failed to fetch artists", err)
+	}
+
+	var artists []PlexArtist
+	for _, dir := range container.Directories {
+		if dir.Type == "artist" {
+			artists = append(artists, artistFromDirectory(dir))
+		}
+	}
+
+	p.logger.Debug("Fetched %d artists for %s=%s", len(artists), filter, value)
+
+	return artists, nil
+}
@@ -0,0 +1,151 @@
+package plex
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"plexamp-tui/internal/logger"
+)
+
+// newTestClient returns a client whose requests go to a server answering
+// every request with body
+func newTestClient(t *testing.T, body string) (*PlexClient, string) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	log, err := logger.NewLogger(false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewPlexClient(log, 0), server.URL
+}
+
+func TestFetchArtistAlbums(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		titles []string
+	}{
+		{
+			name:   "no albums",
+			body:   `<MediaContainer size="0"></MediaContainer>`,
+			titles: nil,
+		},
+		{
+			name: "albums only",
+			body: `<MediaContainer size="2">
+				<Directory ratingKey="11" title="Rumours" parentTitle="Fleetwood Mac" year="1977" type="album"/>
+				<Directory ratingKey="12" title="Tusk" parentTitle="Fleetwood Mac" year="1979" type="album"/>
+			</MediaContainer>`,
+			titles: []string{"Rumours", "Tusk"},
+		},
+		{
+			name: "other directories skipped",
+			body: `<MediaContainer size="2">
+				<Directory ratingKey="20" title="Popular Tracks" type="collection"/>
+				<Directory ratingKey="21" title="Mirage" parentTitle="Fleetwood Mac" year="1982" type="album"/>
+			</MediaContainer>`,
+			titles: []string{"Mirage"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, serverURL := newTestClient(t, tt.body)
+
+			albums, err := client.FetchArtistAlbums(serverURL, "1", "token")
+			if err != nil {
+				t.Fatalf("FetchArtistAlbums: %v", err)
+			}
+			if albums == nil {
+				t.Fatal("FetchArtistAlbums returned nil, want an empty list")
+			}
+			if len(albums) != len(tt.titles) {
+				t.Fatalf("got %d albums, want %d", len(albums), len(tt.titles))
+			}
+			for i, album := range albums {
+				if album.Title != tt.titles[i] {
+					t.Errorf("album %d: got %q, want %q", i, album.Title, tt.titles[i])
+				}
+				if album.ParentTitle != "Fleetwood Mac" {
+					t.Errorf("album %d: got artist %q, want Fleetwood Mac", i, album.ParentTitle)
+				}
+			}
+		})
+	}
+}
+
+func TestTokenNotLogged(t *testing.T) {
+	const token = "s3cr3t-token"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Plex-Token") != token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	logPath := filepath.Join(t.TempDir(), "plexamp-tui.log")
+	log, err := logger.NewLogger(true, logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Close()
+	client := NewPlexClient(log, 0)
+
+	// Failed requests log the most, including their errors
+	client.FetchArtists(server.URL, "1", TypesForKind(KindMusic), token)
+	client.FetchArtistsPage(server.URL, "1", TypesForKind(KindMusic), token, "titleSort", 0, 50)
+	client.FetchArtistAlbums(server.URL, "2", token)
+	client.FetchAlbumTracks(server.URL, "3", token)
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) == 0 {
+		t.Fatal("nothing was logged")
+	}
+	if strings.Contains(string(data), token) {
+		t.Errorf("the token was logged:\n%s", data)
+	}
+}
+
+func TestFetchUsesLibraryTypes(t *testing.T) {
+	var gotType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotType = r.URL.Query().Get("type")
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(`<MediaContainer size="0"></MediaContainer>`))
+	}))
+	defer server.Close()
+
+	log, err := logger.NewLogger(false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewPlexClient(log, 0)
+	types := TypesForKind(KindAudiobooks).WithTypes(0, 15)
+
+	if _, err := client.FetchArtists(server.URL, "1", types, "token"); err != nil {
+		t.Fatal(err)
+	}
+	if gotType != "8" {
+		t.Errorf("artists listed type %s, want the default 8", gotType)
+	}
+	if _, err := client.FetchAlbums(server.URL, "1", types, "token"); err != nil {
+		t.Fatal(err)
+	}
+	if gotType != "15" {
+		t.Errorf("albums listed type %s, want the configured 15", gotType)
+	}
+}
@@ -0,0 +1,143 @@
+package plex
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// =====================
+// Lyrics
+// =====================
+
+// LyricLine is a line of lyrics. TimeMs is when it is sung, and is only set
+// for synced lyrics.
+type LyricLine struct {
+	TimeMs int
+	Text   string
+}
+
+// Lyrics are the lyrics of a track, timed when Synced is set
+type Lyrics struct {
+	Lines  []LyricLine
+	Synced bool
+}
+
+// FetchLyrics retrieves the lyrics of a track, preferring synced lyrics to
+// plain ones. It returns nil lyrics when the track has none.
+func (p *PlexClient) FetchLyrics(serverAddr, trackRatingKey, token string) (*Lyrics, error) {
+	p.logger.Debug("Fetching lyrics for track %s", trackRatingKey)
+
+	container, err := p.fetchTrackMedia(serverAddr, trackRatingKey, token)
+	if err != nil {
+		return nil, err
+	}
+
+	var stream *mediaStream
+	for _, track := range container.Tracks {
+		for _, media := range track.Media {
+			for _, part := range media.Parts {
+				for i, s := range part.Streams {
+					if s.StreamType != lyricsStreamType || s.Key == "" {
+						continue
+					}
+					if stream == nil || (s.Codec == "lrc" && stream.Codec != "lrc") {
+						stream = &part.Streams[i]
+					}
+				}
+			}
+		}
+	}
+	if stream == nil {
+		p.logger.Debug("Track %s has no lyrics", trackRatingKey)
+		return nil, nil
+	}
+
+	req, err := p.api.NewRequest(http.MethodGet, ServerBaseURL(serverAddr)+stream.Key, token)
+	if err != nil {
+		return nil, err
+	}
+	data, err := p.api.Do(req)
+	if err != nil {
+		return nil, p.requestError("failed to fetch lyrics", err)
+	}
+
+	p.logger.Debug("Fetched %s lyrics for track %s", stream.Codec, trackRatingKey)
+
+	lyrics := ParseLyrics(string(data))
+	if len(lyrics.Lines) == 0 {
+		return nil, nil
+	}
+	return lyrics, nil
+}
+
+// lrcTimestamp matches a [mm:ss.xx] time tag of an LRC file
+var lrcTimestamp = regexp.MustCompile(`^\[(\d+):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+
+// ParseLyrics reads lyrics in LRC format, or as plain text when no line
+// carries a time tag. LRC header tags such as [ar:Artist] are dropped, and a
+// line with several time tags is repeated at each of them.
+func ParseLyrics(data string) *Lyrics {
+	var synced, plain []LyricLine
+	for _, raw := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		line := strings.TrimSpace(raw)
+
+		var times []int
+		for {
+			match := lrcTimestamp.FindStringSubmatch(line)
+			if match == nil {
+				break
+			}
+			times = append(times, lrcMillis(match[1], match[2], match[3]))
+			line = line[len(match[0]):]
+		}
+		line = strings.TrimSpace(line)
+
+		if len(times) == 0 {
+			if !isLRCTag(line) {
+				plain = append(plain, LyricLine{Text: line})
+			}
+			continue
+		}
+		for _, t := range times {
+			synced = append(synced, LyricLine{TimeMs: t, Text: line})
+		}
+	}
+
+	if len(synced) > 0 {
+		sort.SliceStable(synced, func(i, j int) bool { return synced[i].TimeMs < synced[j].TimeMs })
+		return &Lyrics{Lines: synced, Synced: true}
+	}
+
+	// Drop the blank lines around plain lyrics, keeping those between verses
+	for len(plain) > 0 && plain[0].Text == "" {
+		plain = plain[1:]
+	}
+	for len(plain) > 0 && plain[len(plain)-1].Text == "" {
+		plain = plain[:len(plain)-1]
+	}
+	return &Lyrics{Lines: plain}
+}
+
+// lrcMillis converts the minutes, seconds and fraction of a time tag to
+// milliseconds. The fraction may be in tenths, hundredths or thousandths.
+func lrcMillis(minutes, seconds, fraction string) int {
+	min, _ := strconv.Atoi(minutes)
+	sec, _ := strconv.Atoi(seconds)
+	ms := 0
+	if fraction != "" {
+		ms, _ = strconv.Atoi((fraction + "00")[:3])
+	}
+	return (min*60+sec)*1000 + ms
+}
+
+// isLRCTag reports whether a line is an LRC header tag such as [ti:Title]
+func isLRCTag(line string) bool {
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+		return false
+	}
+	name, _, ok := strings.Cut(line[1:len(line)-1], ":")
+	return ok && name != "" && !strings.ContainsAny(name, " ")
+}
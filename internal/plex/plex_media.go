@@ -0,0 +1,115 @@
+package plex
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// =====================
+// Track Media
+// =====================
+
+// Stream types of the streams in a track's media parts
+const (
+	audioStreamType  = 2
+	lyricsStreamType = 4
+)
+
+// mediaStream is a stream of a track's media part: its audio, or lyrics
+// attached by the metadata agent or a sidecar file
+type mediaStream struct {
+	Key          string `xml:"key,attr"`
+	StreamType   int    `xml:"streamType,attr"`
+	Codec        string `xml:"codec,attr"` // For lyrics, "lrc" when synced and "txt" when plain
+	SamplingRate int    `xml:"samplingRate,attr"`
+	BitDepth     int    `xml:"bitDepth,attr"`
+}
+
+// trackMediaContainer is a track's metadata down to the streams of its media
+type trackMediaContainer struct {
+	XMLName xml.Name `xml:"MediaContainer"`
+	Tracks  []struct {
+		Media []struct {
+			AudioCodec string `xml:"audioCodec,attr"`
+			Bitrate    int    `xml:"bitrate,attr"` // kbps
+			Parts      []struct {
+				Streams []mediaStream `xml:"Stream"`
+			} `xml:"Part"`
+		} `xml:"Media"`
+	} `xml:"Track"`
+}
+
+// fetchTrackMedia retrieves a track's metadata including its media streams
+func (p *PlexClient) fetchTrackMedia(serverAddr, trackRatingKey, token string) (*trackMediaContainer, error) {
+	urlStr := fmt.Sprintf("%s/library/metadata/%s", ServerBaseURL(serverAddr), trackRatingKey)
+
+	var container trackMediaContainer
+	if err := p.api.GetXML(urlStr, token, &container); err != nil {
+		return nil, p.requestError("failed to fetch track", err)
+	}
+	return &container, nil
+}
+
+// MediaInfo describes the audio of a track's file
+type MediaInfo struct {
+	Codec      string // e.g. "flac" or "mp3"
+	Bitrate    int    // kbps
+	SampleRate int    // Hz
+	BitDepth   int
+}
+
+// Label returns the audio quality for display, e.g. "FLAC 1411kbps 44.1kHz
+// 16-bit", leaving out what isn't known
+func (i MediaInfo) Label() string {
+	var parts []string
+	if i.Codec != "" {
+		parts = append(parts, strings.ToUpper(i.Codec))
+	}
+	if i.Bitrate > 0 {
+		parts = append(parts, fmt.Sprintf("%dkbps", i.Bitrate))
+	}
+	if i.SampleRate > 0 {
+		khz := fmt.Sprintf("%.1f", float64(i.SampleRate)/1000)
+		parts = append(parts, strings.TrimSuffix(khz, ".0")+"kHz")
+	}
+	if i.BitDepth > 0 {
+		parts = append(parts, fmt.Sprintf("%d-bit", i.BitDepth))
+	}
+	return strings.Join(parts, " ")
+}
+
+// FetchMediaInfo retrieves the codec, bitrate and sample rate of a track's
+// first media version. It returns nil when the server doesn't describe it.
+func (p *PlexClient) FetchMediaInfo(serverAddr, trackRatingKey, token string) (*MediaInfo, error) {
+	p.logger.Debug("Fetching media info for track %s", trackRatingKey)
+
+	container, err := p.fetchTrackMedia(serverAddr, trackRatingKey, token)
+	if err != nil {
+		return nil, err
+	}
+	if len(container.Tracks) == 0 || len(container.Tracks[0].Media) == 0 {
+		return nil, nil
+	}
+
+	media := container.Tracks[0].Media[0]
+	info := MediaInfo{Codec: media.AudioCodec, Bitrate: media.Bitrate}
+parts:
+	for _, part := range media.Parts {
+		for _, s := range part.Streams {
+			if s.StreamType != audioStreamType {
+				continue
+			}
+			if info.Codec == "" {
+				info.Codec = s.Codec
+			}
+			info.SampleRate = s.SamplingRate
+			info.BitDepth = s.BitDepth
+			break parts
+		}
+	}
+	if info.Label() == "" {
+		return nil, nil
+	}
+	return &info, nil
+}
@@ -0,0 +1,109 @@
+package plex
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// =====================
+// Play Queues
+// =====================
+
+// playQueueWindow is how many items around the current one are requested
+const playQueueWindow = 100
+
+// PlexQueueTrack is a track in a play queue
+type PlexQueueTrack struct {
+	PlexTrack
+	PlayQueueItemID string `xml:"playQueueItemID,attr"`
+}
+
+// PlexPlayQueue is the play queue a player is working through
+type PlexPlayQueue struct {
+	XMLName        xml.Name         `xml:"MediaContainer"`
+	ID             string           `xml:"playQueueID,attr"`
+	SelectedItemID string           `xml:"playQueueSelectedItemID,attr"`
+	Tracks         []PlexQueueTrack `xml:"Track"`
+}
+
+// FetchPlayQueue retrieves the tracks around the current item of a play queue
+func (p *PlexClient) FetchPlayQueue(serverAddr, playQueueID, token string) (*PlexPlayQueue, error) {
+	urlStr := fmt.Sprintf("%s/playQueues/%s?window=%d",
+		ServerBaseURL(serverAddr), url.PathEscape(playQueueID), playQueueWindow)
+
+	p.logger.Debug("Fetching play queue %s", playQueueID)
+
+	var queue PlexPlayQueue
+	if err := p.api.GetXML(urlStr, token, &queue); err != nil {
+		return nil, p.requestError("failed to fetch play queue", err)
+	}
+
+	p.logger.Debug("Fetched %d play queue items", len(queue.Tracks))
+
+	return &queue, nil
+}
+
+// RemoveFromPlayQueue deletes a single item from a play queue on the server
+// The player has to be told to refresh its copy of the queue afterwards
+func (p *PlexClient) RemoveFromPlayQueue(serverAddr, playQueueID, playQueueItemID, token string) error {
+	urlStr := fmt.Sprintf("%s/playQueues/%s/items/%s",
+		ServerBaseURL(serverAddr), url.PathEscape(playQueueID), url.PathEscape(playQueueItemID))
+
+	req, err := p.api.NewRequest(http.MethodDelete, urlStr, token)
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.api.Do(req); err != nil {
+		return p.requestError("failed to remove play queue item", err)
+	}
+
+	return nil
+}
+
+// ClearPlayQueue deletes every item from a play queue on the server
+// The player has to be told to refresh its copy of the queue afterwards
+func (p *PlexClient) ClearPlayQueue(serverAddr, playQueueID, token string) error {
+	urlStr := fmt.Sprintf("%s/playQueues/%s/items",
+		ServerBaseURL(serverAddr), url.PathEscape(playQueueID))
+
+	req, err := p.api.NewRequest(http.MethodDelete, urlStr, token)
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.api.Do(req); err != nil {
+		return p.requestError("failed to clear play queue", err)
+	}
+
+	return nil
+}
+
+// AddToPlayQueue adds the items described by source, either a uri or a
+// playlistID, to a play queue on the server. With next set they are queued
+// right after the current item, otherwise at the end.
+// The player has to be told to refresh its copy of the queue afterwards
+func (p *PlexClient) AddToPlayQueue(serverAddr, playQueueID string, source url.Values, next bool, token string) error {
+	params := url.Values{}
+	for k, v := range source {
+		params[k] = v
+	}
+	if next {
+		params.Set("next", "1")
+	}
+
+	urlStr := fmt.Sprintf("%s/playQueues/%s?%s", ServerBaseURL(serverAddr), url.PathEscape(playQueueID), params.Encode())
+
+	req, err := p.api.NewRequest(http.MethodPut, urlStr, token)
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.api.Do(req); err != nil {
+		return p.requestError("failed to add to play queue", err)
+	}
+
+	return nil
+}
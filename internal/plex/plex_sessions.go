@@ -0,0 +1,113 @@
+package plex
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"plexamp-tui/internal/plexhttp"
+)
+
+// =====================
+// Playback Sessions
+// =====================
+
+// How the server sends a track to the player
+const (
+	DecisionDirectPlay   = "directplay"   // The file as it is
+	DecisionDirectStream = "directstream" // Audio untouched, repackaged into another container
+	DecisionTranscode    = "transcode"    // Audio converted, e.g. to a lower bitrate
+)
+
+// ErrSessionsDenied is returned when the server won't list its sessions to
+// the user, as it does for everyone but its owner
+var ErrSessionsDenied = errors.New("playback sessions are only visible to the server owner")
+
+// sessionsContainer lists what is being played from the server
+type sessionsContainer struct {
+	XMLName xml.Name `xml:"MediaContainer"`
+	Tracks  []struct {
+		RatingKey string `xml:"ratingKey,attr"`
+		Media     []struct {
+			Parts []struct {
+				Decision string `xml:"decision,attr"`
+			} `xml:"Part"`
+		} `xml:"Media"`
+		Player struct {
+			Address string `xml:"address,attr"`
+		} `xml:"Player"`
+		TranscodeSession *struct {
+			AudioDecision string `xml:"audioDecision,attr"`
+		} `xml:"TranscodeSession"`
+	} `xml:"Track"`
+}
+
+// PlaybackDecision returns how the server is sending a track to a player,
+// one of the Decision constants, or "" when no session of the player is
+// playing it. The player is matched by host; when no session has that
+// host, the only session playing the track is used. Users other than the
+// server owner get ErrSessionsDenied.
+func (p *PlexClient) PlaybackDecision(serverAddr, trackRatingKey, playerAddr, token string) (string, error) {
+	urlStr := fmt.Sprintf("%s/status/sessions", ServerBaseURL(serverAddr))
+
+	var container sessionsContainer
+	if err := p.api.GetXML(urlStr, token, &container); err != nil {
+		var statusErr *plexhttp.StatusError
+		if errors.Is(err, ErrUnauthorized) || (errors.As(err, &statusErr) && statusErr.Code == http.StatusForbidden) {
+			return "", ErrSessionsDenied
+		}
+		return "", p.requestError("failed to fetch sessions", err)
+	}
+
+	match, matches := -1, 0
+	for i, track := range container.Tracks {
+		if track.RatingKey != trackRatingKey {
+			continue
+		}
+		if hostOf(track.Player.Address) == hostOf(playerAddr) {
+			match, matches = i, 1
+			break
+		}
+		match = i
+		matches++
+	}
+	if matches != 1 {
+		return "", nil
+	}
+
+	track := container.Tracks[match]
+	if track.TranscodeSession != nil {
+		switch strings.ToLower(track.TranscodeSession.AudioDecision) {
+		case "transcode":
+			return DecisionTranscode, nil
+		case "copy":
+			return DecisionDirectStream, nil
+		}
+	}
+	for _, media := range track.Media {
+		for _, part := range media.Parts {
+			switch strings.ToLower(part.Decision) {
+			case "transcode":
+				return DecisionTranscode, nil
+			case "copy", "directstream":
+				return DecisionDirectStream, nil
+			}
+		}
+	}
+	return DecisionDirectPlay, nil
+}
+
+// hostOf returns the host of an address that may carry a scheme or port
+func hostOf(addr string) string {
+	if u, err := url.Parse(addr); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
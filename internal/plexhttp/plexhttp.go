@@ -0,0 +1,127 @@
+// Package plexhttp builds requests to Plex servers and plex.tv and decodes
+// their responses, so the headers, token handling and status checks are the
+// same for every call.
+package plexhttp
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+)
+
+// ErrUnauthorized is returned when Plex rejects the token, either because it
+// expired or because it was revoked server-side
+var ErrUnauthorized = errors.New("plex token expired or revoked")
+
+// StatusError is returned for a response with an unexpected status
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("server returned status %d", e.Code)
+}
+
+// ErrorForStatus converts an unexpected response status into an error
+func ErrorForStatus(code int) error {
+	if code == http.StatusUnauthorized {
+		return ErrUnauthorized
+	}
+	return &StatusError{Code: code}
+}
+
+// tokenParam matches the value of an X-Plex-Token query parameter
+var tokenParam = regexp.MustCompile(`(?i)(X-Plex-Token=)[^&\s"']+`)
+
+// RedactToken masks any Plex token in a URL, or in text containing URLs, so
+// it can be logged or shown without giving the token away
+func RedactToken(url string) string {
+	return tokenParam.ReplaceAllString(url, "${1}***")
+}
+
+// Client sends Plex requests with a fixed set of headers
+type Client struct {
+	httpClient *http.Client
+	headers    map[string]string
+}
+
+// New creates a client that sends its requests through httpClient, whose
+// timeout applies to every request, adding the given headers to each of them
+func New(httpClient *http.Client, headers map[string]string) *Client {
+	return &Client{httpClient: httpClient, headers: headers}
+}
+
+// NewRequest creates a request carrying the client's headers. A non-empty
+// token is sent in the X-Plex-Token header rather than the query string.
+func (c *Client) NewRequest(method, url, token string) (*http.Request, error) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+	if token != "" {
+		req.Header.Set("X-Plex-Token", token)
+	}
+	return req, nil
+}
+
+// Do sends req and returns the body of the response. Any status outside
+// 2xx is returned as an error.
+func (c *Client) Do(req *http.Request) ([]byte, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, ErrorForStatus(resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, nil
+}
+
+// DoXML sends req asking for XML and decodes the response into v
+func (c *Client) DoXML(req *http.Request, v any) error {
+	req.Header.Set("Accept", "application/xml")
+	body, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	if err := xml.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse XML: %w", err)
+	}
+	return nil
+}
+
+// DoJSON sends req asking for JSON and decodes the response into v
+func (c *Client) DoJSON(req *http.Request, v any) error {
+	req.Header.Set("Accept", "application/json")
+	body, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return nil
+}
+
+// GetXML fetches url with the given token and decodes the XML response into v
+func (c *Client) GetXML(url, token string, v any) error {
+	req, err := c.NewRequest(http.MethodGet, url, token)
+	if err != nil {
+		return err
+	}
+	return c.DoXML(req, v)
+}
@@ -0,0 +1,55 @@
+package plexhttp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedactToken(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{
+			in:   "http://10.0.0.2:32400/library/sections/1/all?X-Plex-Token=abc123",
+			want: "http://10.0.0.2:32400/library/sections/1/all?X-Plex-Token=***",
+		},
+		{
+			in:   "http://10.0.0.2:32400/photo?x-plex-token=abc123&width=300",
+			want: "http://10.0.0.2:32400/photo?x-plex-token=***&width=300",
+		},
+		{
+			in:   `Get "http://10.0.0.2:32400/a?X-Plex-Token=abc123": dial tcp: connection refused`,
+			want: `Get "http://10.0.0.2:32400/a?X-Plex-Token=***": dial tcp: connection refused`,
+		},
+		{
+			in:   "http://10.0.0.2:32400/library/sections",
+			want: "http://10.0.0.2:32400/library/sections",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := RedactToken(tt.in); got != tt.want {
+			t.Errorf("RedactToken(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNewRequestSendsTokenInHeader(t *testing.T) {
+	client := New(http.DefaultClient, map[string]string{"X-Plex-Product": "plexamp-tui"})
+
+	req, err := client.NewRequest(http.MethodGet, "http://10.0.0.2:32400/library/sections", "abc123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := req.Header.Get("X-Plex-Token"); got != "abc123" {
+		t.Errorf("X-Plex-Token header is %q, want the token", got)
+	}
+	if strings.Contains(req.URL.String(), "abc123") {
+		t.Errorf("token is in the URL %s", req.URL)
+	}
+	if got := req.Header.Get("X-Plex-Product"); got != "plexamp-tui" {
+		t.Errorf("X-Plex-Product header is %q, want plexamp-tui", got)
+	}
+}
@@ -0,0 +1,39 @@
+//go:build !windows
+
+package presence
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// dial connects to the first available Discord IPC socket
+func dial() (io.ReadWriteCloser, error) {
+	var lastErr error
+	for _, dir := range socketDirs() {
+		for i := 0; i < 10; i++ {
+			path := filepath.Join(dir, fmt.Sprintf("discord-ipc-%d", i))
+			conn, err := net.DialTimeout("unix", path, time.Second)
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		}
+	}
+	return nil, lastErr
+}
+
+// socketDirs returns the directories Discord may place its socket in
+func socketDirs() []string {
+	var dirs []string
+	for _, env := range []string{"XDG_RUNTIME_DIR", "TMPDIR", "TMP", "TEMP"} {
+		if dir := os.Getenv(env); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return append(dirs, "/tmp")
+}
@@ -0,0 +1,22 @@
+//go:build windows
+
+package presence
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// dial connects to the first available Discord IPC named pipe
+func dial() (io.ReadWriteCloser, error) {
+	var lastErr error
+	for i := 0; i < 10; i++ {
+		pipe, err := os.OpenFile(fmt.Sprintf(`\\.\pipe\discord-ipc-%d`, i), os.O_RDWR, 0)
+		if err == nil {
+			return pipe, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
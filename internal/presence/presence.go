@@ -0,0 +1,215 @@
+package presence
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// =====================
+// Discord Rich Presence
+// =====================
+
+// ErrNotRunning is returned when no Discord client is listening on the IPC socket
+var ErrNotRunning = errors.New("discord is not running")
+
+// IPC frame opcodes
+const (
+	opHandshake = 0
+	opFrame     = 1
+	opClose     = 2
+)
+
+// activityTypeListening shows the presence as "Listening to ..."
+const activityTypeListening = 2
+
+// Activity is the presence shown on the user's Discord profile
+type Activity struct {
+	Details    string    // First line, e.g. the track title
+	State      string    // Second line, e.g. "by <artist>"
+	LargeImage string    // Asset key or image URL for the large image
+	LargeText  string    // Tooltip for the large image
+	SmallImage string    // Asset key for the small image
+	SmallText  string    // Tooltip for the small image
+	Start      time.Time // When set, Discord shows the elapsed time since Start
+}
+
+// Client talks to a local Discord client over its IPC socket
+// The connection is opened lazily and re-established after errors, so
+// Discord can be started or restarted while the app is running
+type Client struct {
+	clientID string
+	mu       sync.Mutex
+	conn     io.ReadWriteCloser
+}
+
+// NewClient creates a presence client for the given Discord application ID
+func NewClient(clientID string) *Client {
+	return &Client{clientID: clientID}
+}
+
+// SetActivity replaces the current presence
+func (c *Client) SetActivity(a Activity) error {
+	return c.send(a.payload())
+}
+
+// Clear removes the current presence
+func (c *Client) Clear() error {
+	c.mu.Lock()
+	connected := c.conn != nil
+	c.mu.Unlock()
+	if !connected {
+		// Nothing was ever shown, so don't start Discord's IPC just to clear it
+		return nil
+	}
+	return c.send(nil)
+}
+
+// Close closes the IPC connection, which also drops the presence
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	_ = writeFrame(c.conn, opClose, map[string]any{})
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// send issues a SET_ACTIVITY command, connecting first if needed
+func (c *Client) send(activity map[string]any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		if err := c.connect(); err != nil {
+			return err
+		}
+	}
+
+	cmd := map[string]any{
+		"cmd":   "SET_ACTIVITY",
+		"nonce": uuid.NewString(),
+		"args": map[string]any{
+			"pid":      os.Getpid(),
+			"activity": activity,
+		},
+	}
+	if err := writeFrame(c.conn, opFrame, cmd); err != nil {
+		c.reset()
+		return err
+	}
+	if _, err := readFrame(c.conn); err != nil {
+		c.reset()
+		return err
+	}
+	return nil
+}
+
+// connect dials the IPC socket and performs the handshake
+func (c *Client) connect() error {
+	conn, err := dial()
+	if err != nil {
+		return ErrNotRunning
+	}
+
+	handshake := map[string]any{"v": 1, "client_id": c.clientID}
+	if err := writeFrame(conn, opHandshake, handshake); err != nil {
+		conn.Close()
+		return err
+	}
+	// Discord answers with a READY dispatch, or closes the socket on a bad client ID
+	if _, err := readFrame(conn); err != nil {
+		conn.Close()
+		return fmt.Errorf("discord handshake failed: %w", err)
+	}
+
+	c.conn = conn
+	return nil
+}
+
+// reset drops a broken connection so the next call reconnects
+func (c *Client) reset() {
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+}
+
+// payload converts the activity to the JSON structure Discord expects
+func (a Activity) payload() map[string]any {
+	activity := map[string]any{
+		"type":    activityTypeListening,
+		"details": a.Details,
+		"state":   a.State,
+	}
+
+	assets := map[string]any{}
+	if a.LargeImage != "" {
+		assets["large_image"] = a.LargeImage
+	}
+	if a.LargeText != "" {
+		assets["large_text"] = a.LargeText
+	}
+	if a.SmallImage != "" {
+		assets["small_image"] = a.SmallImage
+	}
+	if a.SmallText != "" {
+		assets["small_text"] = a.SmallText
+	}
+	if len(assets) > 0 {
+		activity["assets"] = assets
+	}
+
+	if !a.Start.IsZero() {
+		activity["timestamps"] = map[string]any{"start": a.Start.Unix()}
+	}
+
+	return activity
+}
+
+// writeFrame writes a single IPC frame: opcode and length as little endian
+// uint32s followed by the JSON payload
+func writeFrame(w io.Writer, opcode uint32, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, 8)
+	binary.LittleEndian.PutUint32(header[0:4], opcode)
+	binary.LittleEndian.PutUint32(header[4:8], uint32(len(data)))
+	if _, err := w.Write(append(header, data...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// readFrame reads a single IPC frame and returns its payload
+func readFrame(r io.Reader) ([]byte, error) {
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	opcode := binary.LittleEndian.Uint32(header[0:4])
+	length := binary.LittleEndian.Uint32(header[4:8])
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	if opcode == opClose {
+		return nil, fmt.Errorf("discord closed the connection: %s", string(data))
+	}
+	return data, nil
+}
@@ -0,0 +1,130 @@
+package scrobble
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =====================
+// last.fm Scrobbling
+// =====================
+
+// APIURL is the last.fm web service endpoint
+const APIURL = "https://ws.audioscrobbler.com/2.0/"
+
+// Track describes a track to report to last.fm
+type Track struct {
+	Artist   string
+	Title    string
+	Album    string
+	Duration time.Duration
+}
+
+// Scrobbler sends now playing updates and scrobbles to last.fm
+type Scrobbler struct {
+	apiKey     string
+	apiSecret  string
+	sessionKey string
+	httpClient *http.Client
+}
+
+// apiError is the error body returned by the last.fm API
+type apiError struct {
+	Error   int    `json:"error"`
+	Message string `json:"message"`
+}
+
+// NewScrobbler creates a scrobbler for an authenticated last.fm session
+func NewScrobbler(apiKey, apiSecret, sessionKey string) *Scrobbler {
+	return &Scrobbler{
+		apiKey:     apiKey,
+		apiSecret:  apiSecret,
+		sessionKey: sessionKey,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// UpdateNowPlaying tells last.fm which track has just started
+func (s *Scrobbler) UpdateNowPlaying(t Track) error {
+	params := t.params()
+	params.Set("method", "track.updateNowPlaying")
+	return s.call(params)
+}
+
+// Scrobble records a played track, started at the given time
+func (s *Scrobbler) Scrobble(t Track, startedAt time.Time) error {
+	params := t.params()
+	params.Set("method", "track.scrobble")
+	params.Set("timestamp", strconv.FormatInt(startedAt.Unix(), 10))
+	return s.call(params)
+}
+
+// params returns the track fields as last.fm request parameters
+func (t Track) params() url.Values {
+	params := url.Values{}
+	params.Set("artist", t.Artist)
+	params.Set("track", t.Title)
+	if t.Album != "" {
+		params.Set("album", t.Album)
+	}
+	if t.Duration > 0 {
+		params.Set("duration", strconv.Itoa(int(t.Duration.Seconds())))
+	}
+	return params
+}
+
+// call signs and posts a write request to the last.fm API
+func (s *Scrobbler) call(params url.Values) error {
+	params.Set("api_key", s.apiKey)
+	params.Set("sk", s.sessionKey)
+	params.Set("api_sig", s.sign(params))
+	params.Set("format", "json")
+
+	resp, err := s.httpClient.PostForm(APIURL, params)
+	if err != nil {
+		return fmt.Errorf("last.fm request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var apiErr apiError
+	if err := json.NewDecoder(resp.Body).Decode(&apiErr); err == nil && apiErr.Error != 0 {
+		return fmt.Errorf("last.fm error %d: %s", apiErr.Error, apiErr.Message)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("last.fm returned %s", resp.Status)
+	}
+
+	return nil
+}
+
+// sign builds the api_sig parameter: the md5 of every parameter name and
+// value in alphabetical order followed by the shared secret. The format
+// parameter is excluded from the signature.
+func (s *Scrobbler) sign(params url.Values) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if k == "format" || k == "api_sig" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteString(params.Get(k))
+	}
+	b.WriteString(s.apiSecret)
+
+	sum := md5.Sum([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,98 @@
+package statusfile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// =====================
+// Status Output
+// =====================
+
+// DefaultFormat is the line written when no format is configured
+const DefaultFormat = "{state} {artist} - {title}"
+
+// ErrNoReader is returned when the output is a named pipe nothing is reading
+var ErrNoReader = errors.New("no reader on the pipe")
+
+// State is the playback state written to the output
+type State struct {
+	State    string // "playing", "paused" or "stopped"
+	Artist   string
+	Title    string
+	Album    string
+	Position string // Elapsed time, e.g. "1:23"
+	Duration string
+	Volume   int
+}
+
+// Writer writes the playback state to a file, or to a named pipe for status
+// bars that read one line per update
+type Writer struct {
+	path   string
+	format string
+}
+
+// New creates a writer for path, formatting the state with format. The format
+// uses {state}, {artist}, {title}, {album}, {position}, {duration} and
+// {volume}; DefaultFormat is used when it is empty.
+func New(path, format string) *Writer {
+	if format == "" {
+		format = DefaultFormat
+	}
+	return &Writer{path: path, format: format}
+}
+
+// Format returns the line written for a state
+func (w *Writer) Format(s State) string {
+	return strings.NewReplacer(
+		"{state}", s.State,
+		"{artist}", s.Artist,
+		"{title}", s.Title,
+		"{album}", s.Album,
+		"{position}", s.Position,
+		"{duration}", s.Duration,
+		"{volume}", fmt.Sprint(s.Volume),
+	).Replace(w.format)
+}
+
+// Write replaces the output with line. A regular file is replaced atomically,
+// so readers never see it half written. A named pipe gets the line appended,
+// and ErrNoReader when nothing has it open.
+func (w *Writer) Write(line string) error {
+	data := []byte(line + "\n")
+
+	if info, err := os.Stat(w.path); err == nil && info.Mode()&os.ModeNamedPipe != 0 {
+		pipe, err := os.OpenFile(w.path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+		if errors.Is(err, syscall.ENXIO) {
+			return ErrNoReader
+		}
+		if err != nil {
+			return err
+		}
+		defer pipe.Close()
+		_, err = pipe.Write(data)
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(w.path), "."+filepath.Base(w.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Fails harmlessly once renamed
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), w.path)
+}
@@ -2,25 +2,84 @@ package ui
 
 import (
 	"fmt"
+	"strings"
+
+	"plexamp-tui/internal/config"
 
 	"github.com/charmbracelet/lipgloss"
 )
 
+// controlEntries lists what the controls pane shows. As in the help overlay,
+// the keys of global actions come from the keymap.
+var controlEntries = []helpEntry{
+	{keys: "↑/↓", description: "navigate"},
+	{keys: "tab", description: "Focus list/Now Playing"},
+	{keys: "enter", description: "select"},
+	{action: config.ActionPlayPause, description: "Play/Pause"},
+	{action: config.ActionStop, description: "Stop"},
+	{action: config.ActionNext, description: "Next"},
+	{action: config.ActionPrevious, description: "Previous"},
+	{keys: "←/→", description: "Seek ±10s"},
+	{keys: "alt+0-9", description: "Jump to 0-90%"},
+	{action: config.ActionVolumeUp, description: "Volume up"},
+	{action: config.ActionVolumeDown, description: "Volume down"},
+	{action: config.ActionSetVolume, description: "Set volume"},
+	{action: config.ActionMute, description: "Mute"},
+	{action: config.ActionGoToAlbum, description: "Go to album"},
+	{action: config.ActionGoToArtist, description: "Go to artist"},
+	{action: config.ActionLyrics, description: "Lyrics"},
+	{action: config.ActionCompact, description: "Compact view"},
+	{action: config.ActionRandomAlbum, description: "Random album"},
+	{action: config.ActionSleepTimer, description: "Sleep timer"},
+	{action: config.ActionCrossfade, description: "Crossfade"},
+	{keys: "q", description: "Back"},
+	{action: config.ActionQuit, description: "Quit"},
+	{action: config.ActionHelp, description: "Help"},
+}
+
+// panelEntries lists the panels shown on one line of the controls pane
+// The profile switcher stays available so an expired profile can be switched away from
+var panelEntries = []helpEntry{
+	{action: config.ActionArtists, description: "Artists"},
+	{action: config.ActionAlbums, description: "Albums"},
+	{action: config.ActionPlaylists, description: "Playlists"},
+	{action: config.ActionHistory, description: "History"},
+	{action: config.ActionProfiles, description: "Profiles"},
+	{action: config.ActionGenres, description: "Genres"},
+	{action: config.ActionLibraries, description: "Libraries"},
+	{action: config.ActionQueue, description: "Queue"},
+}
+
 func (m model) appControlsView() string {
 	body := ""
 
 	if m.usingDefaultCfg {
-		body += lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5555")).Render(
+		body += lipgloss.NewStyle().Foreground(theme.Negative).Render(
 			"⚠️ Using default config\n\n")
 	}
 
-	plexControls := ""
-	if m.plexAuthenticated {
-		plexControls = "\n  1 Artists  2 Albums  3 Playlists"
+	var lines []string
+	for _, entry := range controlEntries {
+		if keys := entry.boundKeys(); keys != "" {
+			lines = append(lines, fmt.Sprintf("  %s %s", keys, entry.description))
+		}
+	}
+
+	var panels []string
+	for _, entry := range panelEntries {
+		if !m.plexAuthenticated && entry.action != config.ActionProfiles {
+			continue
+		}
+		if keys := entry.boundKeys(); keys != "" {
+			panels = append(panels, fmt.Sprintf("%s %s", keys, entry.description))
+		}
+	}
+	if len(panels) > 0 {
+		lines = append(lines, "  "+strings.Join(panels, "  "))
 	}
 
-	controlsText := fmt.Sprintf("Controls:\n  ↑/↓ navigate\n  Enter select\n  [p / space] Play/Pause\n  n Next\n  b Previous\n  +/- Volume %s\n  q Quit", plexControls)
-	controls := lipgloss.NewStyle().MarginTop(1).Foreground(lipgloss.Color("#8888ff")).Render(controlsText)
+	controlsText := "Controls:\n" + strings.Join(lines, "\n")
+	controls := lipgloss.NewStyle().MarginTop(1).Foreground(theme.Info).Render(controlsText)
 
 	return fmt.Sprintf("%s%s", body, controls)
 }
@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"plexamp-tui/internal/plex"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// artistInfoWidth is the widest the artist info popup gets
+const artistInfoWidth = 72
+
+// artistInfoMsg carries the details fetched for an artist
+type artistInfoMsg struct {
+	ratingKey string
+	details   *plex.ArtistDetails
+	err       error
+}
+
+// openArtistInfo shows the popup with the selected artist's biography,
+// fetching it unless it is already loaded
+func (m *model) openArtistInfo() tea.Cmd {
+	selected, ok := m.artistList.SelectedItem().(artistItem)
+	if !ok || selected.ratingKey == "" || m.config == nil {
+		return nil
+	}
+	m.artistInfoVisible = true
+	if m.artistInfoKey == selected.ratingKey && m.artistInfoErr == nil && m.artistInfo != nil {
+		return nil
+	}
+
+	ratingKey := selected.ratingKey
+	m.artistInfoKey = ratingKey
+	m.artistInfoName = strings.TrimSuffix(selected.title, " ★")
+	m.artistInfo = nil
+	m.artistInfoErr = nil
+
+	token := plexClient.GetPlexToken()
+	serverAddr := m.config.ServerURL()
+
+	return tea.Batch(m.startLoading("artist-info"), func() tea.Msg {
+		details, err := plexClient.FetchArtistDetails(serverAddr, ratingKey, token)
+		return artistInfoMsg{ratingKey: ratingKey, details: details, err: err}
+	})
+}
+
+// handleArtistInfo stores fetched artist details, unless another artist has
+// been opened since
+func (m *model) handleArtistInfo(msg artistInfoMsg) {
+	if msg.ratingKey != m.artistInfoKey {
+		return
+	}
+	m.stopLoading("artist-info")
+	if msg.err != nil {
+		if m.handleAuthError(msg.err) {
+			m.artistInfoVisible = false
+			return
+		}
+		m.artistInfoErr = msg.err
+		return
+	}
+	m.artistInfo = msg.details
+}
+
+// handleArtistInfoKey dismisses the popup on esc, q or i; other keys are ignored
+func (m *model) handleArtistInfoKey(key string) {
+	if key == "esc" || key == "q" || key == "i" {
+		m.artistInfoVisible = false
+	}
+}
+
+// artistInfoView renders the popup, centered in the height left below the title
+func (m model) artistInfoView(height int) string {
+	width := min(artistInfoWidth, m.width-8)
+	muted := lipgloss.NewStyle().Foreground(theme.Muted)
+	label := lipgloss.NewStyle().Foreground(theme.Label)
+	value := lipgloss.NewStyle().Foreground(theme.Value)
+
+	name := m.artistInfoName
+	if m.artistInfo != nil && m.artistInfo.Title != "" {
+		name = m.artistInfo.Title
+	}
+	body := lipgloss.NewStyle().Bold(true).Foreground(theme.Accent).Render(name) + "\n\n"
+
+	switch {
+	case m.loading["artist-info"]:
+		body += lipgloss.NewStyle().Foreground(theme.Accent).Render(m.spinner.View()) + " " + lipgloss.NewStyle().Foreground(theme.Info).Render("Loading...")
+	case m.artistInfoErr != nil:
+		body += muted.Render(fmt.Sprintf("Couldn't load artist: %v", m.artistInfoErr))
+	case m.artistInfo != nil:
+		info := m.artistInfo
+		if len(info.Genres) > 0 {
+			body += label.Render("Genres: ") + value.Render(strings.Join(info.Genres, ", ")) + "\n"
+		}
+		body += label.Render("In library: ") + value.Render(fmt.Sprintf("%s, %s",
+			pluralize(info.AlbumCount, "album", "albums"), pluralize(info.TrackCount, "track", "tracks"))) + "\n\n"
+
+		summary := info.Summary
+		if summary == "" {
+			summary = "No biography available"
+		}
+		// Leave room for the border, the lines above and the footer
+		body += lipgloss.NewStyle().Width(width).MaxHeight(max(height-10, 3)).Render(summary)
+	}
+
+	body += "\n\n" + muted.Render("Press i or esc to close")
+	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(theme.Title).Padding(0, 1).Width(width + 2).Render(body)
+	return lipgloss.Place(m.width, height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// pluralize formats a count with the singular or plural noun, e.g. "1 album"
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
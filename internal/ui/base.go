@@ -3,17 +3,27 @@ package ui
 
 import (
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"plexamp-tui/internal/config"
+	"plexamp-tui/internal/discovery"
+	"plexamp-tui/internal/httpapi"
 	"plexamp-tui/internal/logger"
+	"plexamp-tui/internal/mpris"
 	"plexamp-tui/internal/plex"
+	"plexamp-tui/internal/presence"
+	"plexamp-tui/internal/scrobble"
+	"plexamp-tui/internal/statusfile"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -27,47 +37,182 @@ type item struct {
 	Name        string
 	Type        string
 	MetadataKey string
+	Tags        []string
 }
 
 func (i item) Title() string          { return string(i.Name) }
-func (i item) Description() string    { return string(i.Type) }
+func (i item) Description() string    { return string(i.Type) + tagsSuffix(i.Tags) }
 func (i item) FilterValue() string    { return string(i.Name) }
 func (i item) GetMetadataKey() string { return i.MetadataKey }
 
 type model struct {
 	playbackList      list.Model
 	artistList        list.Model // Plex artist browse list
+	artistsTotal      int        // Total artists in the library, may exceed what is loaded
+	artistsLoading    bool       // Whether an artist page request is in flight
 	albumList         list.Model // Plex album browse list
+	trackList         list.Model // Plex track browse list for a single album
 	playlistList      list.Model // Plex playlist browse list
 	serverList        list.Model // Plex server browse list
 	playerList        list.Model // Plex player browse list
+	historyList       list.Model // Recently played list
+	profileList       list.Model // Plex auth profile list
+	queueList         list.Model // Player's current play queue
+	genreList         list.Model // Plex genre or decade browse list
+	libraryList       list.Model // Music libraries of the selected server
 	selected          string
 	status            string
 	width             int
 	height            int
 	isPlaying         bool
+	isStopped         bool // Whether the player has nothing loaded, as opposed to paused
 	lastCommand       string
 	currentTrack      string
+	currentThumb      string // Album art path from the timeline (e.g. /library/metadata/123/thumb/456)
+	albumArtURL       string // Fully resolved album art URL for the current track
 	volume            int
 	durationMs        int
 	positionMs        int
 	lastUpdate        time.Time
+	progressTicking   bool // Whether the fast progress tick is running
+	scrobble          scrobbleState
+	presence          presenceState
+	statusOutput      string // Line last written to the status output
 	usingDefaultCfg   bool
 	shuffle           bool // Tracks shuffle state
+	repeat            int  // Repeat mode: 0 = off, 1 = repeat one, 2 = repeat all
+	shuffleSynced     bool // Whether the player has been sent our shuffle state, so its own can be trusted
+	crossfade         int  // Crossfade duration in seconds, 0 for gapless
 	plexAuthenticated bool // Plex authentication status
 	timelineRequestID int
-
-	// Panel mode: "servers", "playback", "edit", "plex-servers", "plex-libraries", "plex-artists", "plex-albums"
+	focusedPane       string // Pane receiving navigation keys: focusList or focusPlayback
+	artistSort        string // Order of the artist list, one of artistSortModes
+	albumSort         string // Order of the album list, one of albumSortModes
+	jumpLetter        string // Letter of the last jump to letter, pressed again for the next match
+	jumpActive        bool   // Whether the next key is the letter to jump to
+	jumpStart         int    // Index the jump to letter searches from
+	jumpPending       bool   // Whether the jump to letter waits for the next page of the list
+	favFilter         string // Favorite type shown in the favorites panel, empty for all
+	favTag            string // Tag the favorites panel is limited to, empty for none
+	playerFailures    int    // Consecutive failed timeline polls of the selected player
+	playerChecked     bool   // Whether the selected player has been polled yet
+	playQueueID       string // Play queue the player is working through
+	playQueueItemID   string // Queue item that is currently playing
+
+	current currentItems // Library items of the playing track
+
+	playbackRate     float64 // Playback speed, 1 for normal
+	speedUnsupported bool    // Whether the player rejected a speed change, so no more are sent
+
+	// Timelines the player answered with something else, e.g. an HTML error page
+	timelineUnexpected bool      // Whether the last poll couldn't be read
+	timelineWarnedAt   time.Time // When the status line last reported it
+
+	// Panel mode: "servers", "playback", "edit", "plex-servers", "plex-libraries", "plex-artists", "plex-albums", "plex-tracks"
 	panelMode      string
+	trackAlbumKey  string // Rating key of the album shown in the track browser
+	albumArtistKey string // Rating key of the artist the album browser is scoped to, empty for the whole library
+	albumFilter    string // "genre" or "decade" when the album browser is scoped to a filter value
+	albumFilterKey string // Filter value the album browser is scoped to
+	albumScopeName string // Name of the artist or filter value the album browser is scoped to
+	genreFilter    string // Whether the genre browser lists genres or decades
+	restoreIndex   int    // Selection to restore once the panel restored at startup has loaded
+	libraryCache   *libraryCache
 	playbackConfig *config.Favorites
 	config         *config.Config // Store config for server ID access
 
+	trackCrumbs []string // Breadcrumb of the track browser, which depends on how it was opened
+
+	// Players the last local network search found, merged into the player list
+	localPlayers []discovery.Player
+
+	// Loading state of the browse panels
+	loading map[string]bool // Panels waiting for a fetch, keyed by panel mode
+	spinner spinner.Model   // Shown in place of an empty browse list while it loads
+
 	// Edit mode fields
 	editMode       string // "server" or "playback"
 	editIndex      int    // Index of item being edited
 	editInputs     []textinput.Model
 	typeSelect     list.Model // Dropdown for type selection
 	editFocusIndex int
+	editErrors     map[int]string // Validation errors by focus index, shown under the field
+
+	// Library search of the edit panel, which fills in a favorite's name and key
+	editSearchActive bool
+	editSearchInput  textinput.Model
+	editSearchList   list.Model
+	editSearchQuery  string // Query the shown results belong to
+	editSearchStatus string
+
+	volumeStep int // Percent the volume keys change the volume by
+
+	// Right-hand panels shown, and whether Controls sits above Now Playing
+	layout panelLayout
+
+	compact bool // Whether only the one-line status is shown
+
+	pollInterval time.Duration // Shortest time between timeline polls while the player is reachable
+	pollSeq      int           // Number of the latest timeline poll of the polling loop
+	pollAnswered int           // Number of the latest loop poll the player answered
+	pollStarted  time.Time     // When the latest loop poll was sent
+
+	// Mute state; unmuting restores preMuteVolume
+	muted         bool
+	preMuteVolume int
+
+	// Volume prompt fields
+	volumeInput       textinput.Model
+	volumeInputActive bool
+
+	quitConfirm bool // Whether the next key answers "Quit while playing?"
+	helpVisible bool // Whether the keybinding overlay covers the panels
+
+	resume *resumeOffer // Playback waiting for an answer to "Resume at ...?"
+
+	// Sleep timer fields
+	sleepInput       textinput.Model
+	sleepInputActive bool
+	sleepTimerID     int       // Incremented on every (re)schedule so older timers are ignored
+	sleepDeadline    time.Time // When playback will be paused, zero when no timer is running
+
+	// Prompt for the number of most played artists to add to the favorites
+	topArtistsInput       textinput.Model
+	topArtistsInputActive bool
+
+	// Lyrics panel fields
+	lyrics       *plex.Lyrics // Lyrics of the track in lyricsKey, nil when it has none
+	lyricsKey    string       // Rating key of the track the lyrics were fetched for
+	lyricsErr    error        // Set when the lyrics couldn't be fetched
+	lyricsScroll int          // First line shown of plain lyrics
+
+	// Audio quality of the playing track, fetched once per track
+	mediaInfo    *plex.MediaInfo
+	mediaInfoKey string // Rating key of the track mediaInfo was fetched for
+
+	// Whether the server direct plays or transcodes the playing track
+	decision       string // One of the plex.Decision constants, "" when unknown
+	decisionKey    string // Rating key of the track decision was looked up for
+	decisionTries  int    // Looks for the track's session that came up empty
+	sessionsDenied bool   // Whether the server refused to list its sessions
+
+	// Artist info popup
+	artistInfoVisible bool
+	artistInfo        *plex.ArtistDetails // Details of the artist in artistInfoKey
+	artistInfoKey     string              // Rating key of the artist shown
+	artistInfoName    string              // Name of that artist, shown while loading
+	artistInfoErr     error               // Set when the details couldn't be fetched
+
+	// Rating keys of the rows marked in the artist and album lists. The maps
+	// are shared with the lists' delegates, so they are cleared, never replaced.
+	artistMarks map[string]bool
+	albumMarks  map[string]bool
+
+	// Album last picked at random, named in the status once it plays
+	randomAlbumKey  string
+	randomAlbumName string
+
+	upNext *plex.PlexQueueTrack // Track queued after the playing one, nil when unknown or none
 }
 
 type MediaContainer struct {
@@ -80,28 +225,59 @@ type Timeline struct {
 	Time     int    `xml:"time,attr"`
 	Duration int    `xml:"duration,attr"`
 	Volume   int    `xml:"volume,attr"`
+	Repeat   int    `xml:"repeat,attr"`
+	Shuffle  string `xml:"shuffle,attr"`
 	Track    Track  `xml:"Track"`
+
+	PlayQueueID     string `xml:"playQueueID,attr"`
+	PlayQueueItemID string `xml:"playQueueItemID,attr"`
 }
 
 type Track struct {
 	Title            string `xml:"title,attr"`
 	ParentTitle      string `xml:"parentTitle,attr"`
 	GrandparentTitle string `xml:"grandparentTitle,attr"`
+	Thumb            string `xml:"thumb,attr"`
+
+	RatingKey            string `xml:"ratingKey,attr"`
+	ParentRatingKey      string `xml:"parentRatingKey,attr"`      // The track's album
+	GrandparentRatingKey string `xml:"grandparentRatingKey,attr"` // The track's artist
 }
 
 type (
 	trackMsg string
 	errMsg   struct{ err error }
-	pollMsg  struct{}
+	pollMsg  struct {
+		seq      int  // Loop poll the message follows on from
+		fallback bool // Sent when that poll may have hung, rather than to space polls out
+	}
 )
 
 type trackMsgWithState struct {
 	TrackText string
+	Artist    string
+	Title     string
+	Album     string
+	Thumb     string
 	IsPlaying bool
+	Stopped   bool // Nothing is loaded on the player
 	Duration  int
 	Position  int
 	Volume    int
+	Repeat    int
+	Shuffle   string // "1" or "0", empty when the player didn't report it
 	RequestID int
+	LoopSeq   int // Number of the loop poll answered, 0 for a poll outside the loop
+
+	PlayQueueID     string
+	PlayQueueItemID string
+
+	TrackKey  string // Rating key of the track
+	ArtistKey string // Rating key of the track's artist
+	AlbumKey  string // Rating key of the track's album
+
+	Err     error // Set when the player couldn't be reached
+	DataErr error // Set when the player answered with something other than a timeline
 }
 
 type playbackTriggeredMsg struct {
@@ -114,16 +290,25 @@ type UiManager struct {
 }
 
 var (
-	cfg         *config.Config
-	favs        *config.Favorites
-	plexClient  *plex.PlexClient
-	cfgManager  *config.Manager
-	log         *logger.Logger
-	favsManager *config.FavoritesManager
+	cfg            *config.Config
+	favs           *config.Favorites
+	plexClient     *plex.PlexClient
+	cfgManager     *config.Manager
+	log            *logger.Logger
+	favsManager    *config.FavoritesManager
+	historyManager *config.HistoryManager
+	scrobbler      *scrobble.Scrobbler
+	presenceClient *presence.Client
+	statusWriter   *statusfile.Writer
+	mprisServer    *mpris.Server
+	apiServer      *httpapi.Server
+	keyBindings    map[string]string // Key to the global action it triggers
+	globalKeys     config.KeyMap     // Keys of each global action, for the help overlay
 )
 
 func NewUiManager(logger *logger.Logger, config *config.Config, manager *config.Manager,
 	favorites *config.Favorites, client *plex.PlexClient, favoritesMgr *config.FavoritesManager,
+	historyMgr *config.HistoryManager,
 ) *UiManager {
 	log = logger
 	cfg = config
@@ -131,6 +316,16 @@ func NewUiManager(logger *logger.Logger, config *config.Config, manager *config.
 	favs = favorites
 	plexClient = client
 	favsManager = favoritesMgr
+	historyManager = historyMgr
+	scrobbler = newScrobbler(cfg)
+	presenceClient = newPresenceClient(cfg)
+	statusWriter = newStatusWriter(cfg)
+	mprisServer = startMPRIS()
+	apiServer = startHTTPAPI(cfg)
+	applyTheme(cfg.Theme)
+	keyMap, keyMapErr := cfgManager.LoadKeyMap()
+	keyBindings = keyMap.Lookup()
+	globalKeys = keyMap
 
 	// Create playback list
 	var playbackItems []list.Item
@@ -139,7 +334,7 @@ func NewUiManager(logger *logger.Logger, config *config.Config, manager *config.
 			playbackItems = append(playbackItems, item{Name: pb.Name, Type: pb.Type, MetadataKey: pb.MetadataKey})
 		}
 	}
-	playbackList := list.New(playbackItems, list.NewDefaultDelegate(), 0, 0)
+	playbackList := list.New(playbackItems, newFavoriteDelegate(cfg.PlainFavoriteTypes), 0, 0)
 	playbackList.Title = "Favorites"
 	// Add keys to the short help (shown at the bottom of the list)
 	playbackList.AdditionalShortHelpKeys = func() []key.Binding {
@@ -174,6 +369,26 @@ func NewUiManager(logger *logger.Logger, config *config.Config, manager *config.
 				key.WithKeys("d"),
 				key.WithHelp("d", "Delete selected item"),
 			),
+			key.NewBinding(
+				key.WithKeys("K", "shift+up"),
+				key.WithHelp("K/shift+↑", "Move item up"),
+			),
+			key.NewBinding(
+				key.WithKeys("J", "shift+down"),
+				key.WithHelp("J/shift+↓", "Move item down"),
+			),
+			key.NewBinding(
+				key.WithKeys("P"),
+				key.WithHelp("P", "Play all favorites"),
+			),
+			key.NewBinding(
+				key.WithKeys("t"),
+				key.WithHelp("t", "Show one type"),
+			),
+			key.NewBinding(
+				key.WithKeys("#"),
+				key.WithHelp("#", "Show one tag"),
+			),
 		}
 	}
 
@@ -181,18 +396,50 @@ func NewUiManager(logger *logger.Logger, config *config.Config, manager *config.
 		playbackList:      playbackList,
 		artistList:        list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0),
 		albumList:         list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0),
+		trackList:         list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0),
 		playlistList:      list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0),
 		serverList:        list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0),
 		playerList:        list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0),
+		profileList:       list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0),
+		queueList:         list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0),
+		genreList:         list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0),
+		libraryList:       list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0),
+		libraryCache:      newLibraryCache(),
+		loading:           make(map[string]bool),
+		artistMarks:       make(map[string]bool),
+		albumMarks:        make(map[string]bool),
+		spinner:           spinner.New(spinner.WithSpinner(spinner.Dot)),
+		historyList:       list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0),
 		selected:          cfg.SelectedPlayer,
 		usingDefaultCfg:   cfgManager.UsingDefault,
 		playbackConfig:    favs,
 		config:            cfg,
 		panelMode:         "playback",
-		shuffle:           true, // Default shuffle to ON
+		shuffle:           cfg.ShuffleOnLaunch(),
+		crossfade:         cfg.CrossfadeSeconds,
+		playbackRate:      1,
+		artistSort:        validSortMode(artistSortModes, cfg.ArtistSort),
+		albumSort:         validSortMode(albumSortModes, cfg.AlbumSort),
+		volumeStep:        cfg.VolumeStepPercent(),
+		pollInterval:      cfg.TimelinePollInterval(),
+		layout:            layoutFromConfig(cfg),
+		compact:           cfg.CompactView,
 		plexAuthenticated: plexClient.VerifyPlexAuthentication(),
 	}
 
+	m.restoreLastPanel()
+
+	if favsManager != nil && favsManager.RecoveredFrom() != "" {
+		m.status = fmt.Sprintf("Favorites database was damaged, moved it to %s - restore a backup with --import-favorites", favsManager.RecoveredFrom())
+	}
+	if keyMapErr != nil {
+		log.Warn("Failed to load keymap, using defaults: %v", keyMapErr)
+		m.status = fmt.Sprintf("Keymap error, using defaults: %v", keyMapErr)
+	} else if conflicts := keyMap.Conflicts(); len(conflicts) > 0 {
+		log.Warn("Keymap conflicts: %s", strings.Join(conflicts, "; "))
+		m.status = "Keymap conflict: " + strings.Join(conflicts, "; ")
+	}
+
 	return &UiManager{
 		Model: m,
 	}
@@ -203,16 +450,45 @@ func NewUiManager(logger *logger.Logger, config *config.Config, manager *config.
 // =====================
 
 func (m model) Init() tea.Cmd {
-	return tea.Batch(m.pollTimeline(), tick())
+	return tea.Batch(startPolling(m.pollSeq), m.refreshCurrentPanel(), waitForMPRISCmd(), waitForAPICmd(), m.restoreCrossfadeCmd(), m.syncShuffleCmd())
 }
 
-func tick() tea.Cmd {
-	return tea.Tick(time.Second*2, func(time.Time) tea.Msg {
-		return pollMsg{}
+// progressInterval is how often the progress bar is redrawn while playing
+const progressInterval = time.Second
+
+// progressTickMsg redraws the Now Playing panel between timeline polls
+type progressTickMsg struct{}
+
+// progressTick schedules the next progress redraw
+// It doesn't poll the timeline; currentPosition extrapolates from the last poll
+func progressTick() tea.Cmd {
+	return tea.Tick(progressInterval, func(time.Time) tea.Msg {
+		return progressTickMsg{}
 	})
 }
 
+// Update handles a message, dropping the marks and the jump to letter of a
+// panel that it leaves
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	panel := m.panelMode
+	next, cmd := m.update(msg)
+	switch next := next.(type) {
+	case model:
+		if next.panelMode != panel {
+			next.clearMarks(panel)
+			next.endJump()
+		}
+		return next, cmd
+	case *model:
+		if next.panelMode != panel {
+			next.clearMarks(panel)
+			next.endJump()
+		}
+	}
+	return next, cmd
+}
+
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case playerSelectMsg:
 		if msg.err != nil {
@@ -223,26 +499,49 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.config.SelectedPlayer = msg.player.address
 			m.config.SelectedPlayerName = msg.player.title
 			m.selected = msg.player.address
+			// Polls still out to the previous player are stale, so a new
+			// loop is started for this one
+			m.timelineRequestID++
+			// Reachability is tracked per player
+			m.playerFailures = 0
+			m.playerChecked = false
+			m.playbackRate = 1
+			m.speedUnsupported = false
 			cfgManager.Save(m.config)
 			m.lastCommand = "Player Selected"
 			m.status = ""
 			m.panelMode = "playback" // Return to playback view after selection
+			cmd := tea.Batch(m.syncShuffleCmd(), m.loopPollCmd())
+			return m, cmd
 		}
 		return m, nil
 
+	case shuffleSyncedMsg:
+		m.shuffleSynced = true
+		return m, nil
+
 	case serverSelectMsg:
 		if msg.err != nil {
+			if m.handleAuthError(msg.err) {
+				return m, nil
+			}
+			if errors.Is(msg.err, errNoPlayer) {
+				m.status = noPlayerStatus()
+				return m, nil
+			}
 			m.status = "Error selecting server: " + msg.err.Error()
 			return m, nil
 		}
 		if msg.success {
+			// Library keys only mean something on the server they came from
+			sameServer := m.config.ServerID == msg.server.clientIdentifier
 			m.config.ServerID = msg.server.clientIdentifier
 			m.config.PlexServerAddr = msg.server.address + ":" + msg.server.port
+			m.config.PlexServerURI = msg.server.uri
 			m.config.PlexServerName = msg.server.title
-			m.config.PlexLibraries = msg.libraries
-
-			found := false
+			m.sessionsDenied = false // Another server may list its sessions
 			if len(msg.libraries) == 0 {
+				m.config.PlexLibraries = nil
 				log.Debug("No libraries found on this server")
 				m.panelMode = "playback"
 				m.lastCommand = "Server Selected Failed, No Libraries"
@@ -250,21 +549,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
-			// check if new library list has our configured library
-			for _, lib := range msg.libraries {
-				if lib.Title == m.config.PlexLibraryName {
-					found = true
-					break
-				}
-			}
+			m.applyLibraries(msg.libraries, sameServer)
 
-			if !found {
-				log.Debug("Current Library not found on this server, using first library")
-				m.config.PlexLibraryName = msg.libraries[0].Title
-				m.config.PlexLibraryID = msg.libraries[0].Key
-			}
-
-			log.Debug(fmt.Sprintf("Saving server config: %v", m.config))
+			log.Debug("Saving server config: %v", m.config)
 			cfgManager.Save(m.config)
 			m.lastCommand = "Server Selected"
 			m.status = ""
@@ -275,26 +562,82 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width, m.height = msg.Width, msg.Height
 
-		// Reserve a few lines for the footer (and maybe the title)
-		footerHeight := 3 // adjust if your footer grows taller
-		titleHeight := 3
-		availableHeight := msg.Height - footerHeight - titleHeight - 2
-
-		m.playbackList.SetSize(msg.Width/2-4, availableHeight)
-		m.artistList.SetSize(msg.Width/2-4, availableHeight)
-		m.albumList.SetSize(msg.Width/2-4, availableHeight)
-		m.playlistList.SetSize(msg.Width/2-4, availableHeight)
-		m.serverList.SetSize(msg.Width/2-4, availableHeight)
-		m.playerList.SetSize(msg.Width/2-4, availableHeight)
+		m.resizeLists()
 
 		return m, nil
 
 	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, m.quit()
+		}
+
+		// The quit confirmation takes the next key, whatever it is
+		if m.quitConfirm {
+			return m, m.handleQuitConfirm(msg.String())
+		}
+
+		// So does the resume question
+		if m.resume != nil {
+			return m, m.handleResumeKey(msg.String())
+		}
+
+		// The help overlay swallows keys until it is dismissed
+		if m.helpVisible {
+			m.handleHelpKey(msg.String())
+			return m, nil
+		}
+
+		// So does the artist info popup
+		if m.artistInfoVisible {
+			m.handleArtistInfoKey(msg.String())
+			return m, nil
+		}
+
+		// The volume prompt captures all keys while it is open
+		if m.volumeInputActive {
+			modelPtr := &m
+			_, cmd := modelPtr.handleVolumeInputUpdate(msg)
+			return m, cmd
+		}
+
+		// So does the sleep timer prompt
+		if m.sleepInputActive {
+			modelPtr := &m
+			_, cmd := modelPtr.handleSleepInputUpdate(msg)
+			return m, cmd
+		}
+
+		// And the top artists prompt
+		if m.topArtistsInputActive {
+			modelPtr := &m
+			_, cmd := modelPtr.handleTopArtistsInputUpdate(msg)
+			return m, cmd
+		}
+
+		// The compact view only has playback controls, since no list is shown
+		if m.compact {
+			cmd := m.handleCompactKey(msg.String())
+			return m, cmd
+		}
+
 		// Handle edit mode separately
 		if m.panelMode == "edit" {
 			return m.handleEditUpdate(msg)
 		}
 
+		// Tab moves focus between the list and the Now Playing pane
+		if !m.isFiltering() {
+			if msg.String() == "tab" {
+				m.toggleFocus()
+				return m, nil
+			}
+			if m.focusedPane == focusPlayback {
+				if cmd, handled := m.handlePlaybackPaneKey(msg.String()); handled {
+					return m, cmd
+				}
+			}
+		}
+
 		// Handle artist browse mode
 		if m.panelMode == "plex-artists" {
 			// Create a pointer to the current model
@@ -325,6 +668,21 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+		// Handle track browse mode
+		if m.panelMode == "plex-tracks" {
+			// Create a pointer to the current model
+			modelPtr := &m
+			// Call handleTrackBrowseUpdate which will modify the model directly
+			updatedModel, cmd := modelPtr.handleTrackBrowseUpdate(msg)
+			// The updated model might be a different instance, so we need to update our local copy
+			if updatedModel != nil {
+				if m2, ok := updatedModel.(model); ok {
+					m = m2
+				}
+			}
+			return m, cmd
+		}
+
 		// Handle playlist browse mode
 		if m.panelMode == "plex-playlists" {
 			// Create a pointer to the current model
@@ -370,6 +728,74 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+		// Handle history browse mode
+		if m.panelMode == "history" {
+			// Create a pointer to the current model
+			modelPtr := &m
+			// Call handleHistoryBrowseUpdate which will modify the model directly
+			updatedModel, cmd := modelPtr.handleHistoryBrowseUpdate(msg)
+			// The updated model might be a different instance, so we need to update our local copy
+			if updatedModel != nil {
+				if m2, ok := updatedModel.(model); ok {
+					m = m2
+				}
+			}
+			return m, cmd
+		}
+
+		// Handle play queue mode
+		if m.panelMode == "queue" {
+			modelPtr := &m
+			updatedModel, cmd := modelPtr.handleQueueBrowseUpdate(msg)
+			if updatedModel != nil {
+				if m2, ok := updatedModel.(model); ok {
+					m = m2
+				}
+			}
+			return m, cmd
+		}
+
+		// Handle genre browse mode
+		if m.panelMode == "plex-genres" {
+			modelPtr := &m
+			updatedModel, cmd := modelPtr.handleGenreBrowseUpdate(msg)
+			if updatedModel != nil {
+				if m2, ok := updatedModel.(model); ok {
+					m = m2
+				}
+			}
+			return m, cmd
+		}
+
+		// Handle profile browse mode
+		if m.panelMode == "plex-profiles" {
+			modelPtr := &m
+			updatedModel, cmd := modelPtr.handleProfileBrowseUpdate(msg)
+			if updatedModel != nil {
+				if m2, ok := updatedModel.(model); ok {
+					m = m2
+				}
+			}
+			return m, cmd
+		}
+
+		// Handle library browse mode
+		if m.panelMode == "libraries" {
+			modelPtr := &m
+			updatedModel, cmd := modelPtr.handleLibraryBrowseUpdate(msg)
+			if updatedModel != nil {
+				if m2, ok := updatedModel.(model); ok {
+					m = m2
+				}
+			}
+			return m, cmd
+		}
+
+		// Handle the lyrics view
+		if m.panelMode == "lyrics" {
+			return m, m.handleLyricsKey(msg.String())
+		}
+
 		// Handle playback selection (when in playback/favorites mode)
 		if m.panelMode == "playback" {
 			// Check if we're in filtering mode for the playback list
@@ -380,6 +806,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			switch msg.String() {
+			case "left":
+				// The favorites list has no horizontal navigation, so arrows seek here
+				return m, m.seek(-10)
+
+			case "right":
+				return m, m.seek(10)
+
 			case "a":
 				// Add new playback item
 				m.initEditMode("playback", -1)
@@ -387,14 +820,40 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			case "e":
 				// Edit selected playback item
-				index := m.playbackList.Index()
-				m.initEditMode("playback", index)
+				if index := m.selectedFavoriteIndex(); index >= 0 {
+					m.initEditMode("playback", index)
+				}
+				return m, nil
+
+			case "t":
+				// Show only favorites of the next type
+				m.cycleFavoriteFilter()
+				return m, nil
+
+			case "#":
+				// Show only favorites with the next tag
+				m.cycleFavoriteTag()
 				return m, nil
 
 			case "d":
 				// Delete selected playback item
-				index := m.playbackList.Index()
-				m.deletePlaybackItem(index)
+				if err := m.deletePlaybackItem(); err != nil {
+					m.status = fmt.Sprintf("Error deleting favorite: %v", err)
+				}
+				return m, nil
+
+			case "P":
+				// Play every favorite in one queue
+				return m, m.playAllFavoritesCmd()
+
+			case "K", "shift+up":
+				// Move selected favorite up
+				m.moveFavorite(-1)
+				return m, nil
+
+			case "J", "shift+down":
+				// Move selected favorite down
+				m.moveFavorite(1)
 				return m, nil
 
 			case "r":
@@ -426,8 +885,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		key := msg.String()
 
 		switch key {
-		case "ctrl+c", "q":
-			return m, tea.Quit
+		case "q":
+			// q goes back a level in the other panels; this one is the top
+			m.status = "Press Q to quit"
+			return m, nil
 
 		default:
 			// Try the common controls
@@ -437,20 +898,147 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case pollMsg:
-		return m, tea.Batch(m.pollTimeline(), tick())
+		cmd := m.handlePollMsg(msg)
+		return m, cmd
+
+	case mprisCommandMsg:
+		modelPtr := &m
+		cmd := modelPtr.handleMPRISCommand(msg)
+		return m, tea.Batch(cmd, waitForMPRISCmd())
+
+	case apiCommandMsg:
+		modelPtr := &m
+		cmd := modelPtr.handleAPICommand(msg)
+		return m, tea.Batch(cmd, waitForAPICmd())
+
+	case crossfadeSetMsg:
+		if msg.err != nil {
+			if errors.Is(msg.err, errCrossfadeUnsupported) {
+				m.status = "Crossfade not supported by this player"
+			} else {
+				m.status = fmt.Sprintf("Error setting crossfade: %v", msg.err)
+			}
+			return m, nil
+		}
+		m.crossfade = msg.seconds
+		m.lastCommand = "Crossfade " + crossfadeLabel(msg.seconds)
+		if m.config != nil {
+			m.config.CrossfadeSeconds = msg.seconds
+			cfgManager.Save(m.config)
+		}
+		return m, nil
+
+	case topArtistsMsg:
+		m.importTopArtists(msg)
+		return m, nil
+
+	case resumeSeekMsg:
+		return m, m.resumeAt(msg.offsetMs)
+
+	case speedSetMsg:
+		m.handleSpeedSet(msg)
+		return m, nil
+
+	case lyricsMsg:
+		m.handleLyrics(msg)
+		return m, nil
+
+	case mediaInfoMsg:
+		m.handleMediaInfo(msg)
+		return m, nil
+
+	case artistInfoMsg:
+		m.handleArtistInfo(msg)
+		return m, nil
+
+	case decisionMsg:
+		return m, m.handleDecision(msg)
+
+	case decisionRetryMsg:
+		if msg.trackKey != m.decisionKey {
+			return m, nil
+		}
+		return m, m.lookUpDecisionCmd()
+
+	case sleepTimerMsg:
+		return m, m.handleSleepTimer(msg)
+
+	case presenceGraceMsg:
+		return m, m.handlePresenceGrace(msg)
+
+	case progressTickMsg:
+		scrobbleCmd := m.scrobbleTick()
+		// Stop ticking while paused; the next playing timeline restarts it
+		if !m.isPlaying {
+			m.progressTicking = false
+			return m, scrobbleCmd
+		}
+		return m, tea.Batch(scrobbleCmd, progressTick())
 
 	case trackMsgWithState:
 		// Discard if this response is stale
 		if msg.RequestID != m.timelineRequestID {
 			return m, nil
 		}
+		// As is an answer to a loop poll the fallback has already replaced
+		if msg.LoopSeq != 0 && msg.LoopSeq != m.pollSeq {
+			return m, nil
+		}
+		m.recordPlayerPoll(msg.Err)
+		var nextPoll tea.Cmd
+		if msg.LoopSeq != 0 {
+			nextPoll = m.nextPollCmd(msg.Err != nil)
+		}
+		if msg.Err != nil {
+			// Keep the last known track, but stop extrapolating its progress
+			m.isPlaying = false
+			return m, nextPoll
+		}
+		m.recordTimelineData(msg.DataErr)
+		// Needs the previous play state, so run before it is overwritten
+		reportCmd := tea.Batch(nextPoll, m.trackScrobble(msg), m.updatePresence(msg), m.publishMPRIS(msg), m.publishAPIState(msg), m.writeStatusOutput(msg))
 		m.currentTrack = msg.TrackText
+		m.currentThumb = msg.Thumb
+		m.current = currentItems{trackKey: msg.TrackKey, title: msg.Title, artist: msg.Artist, artistKey: msg.ArtistKey, album: msg.Album, albumKey: msg.AlbumKey}
+		m.albumArtURL = m.buildAlbumArtURL(msg.Thumb)
 		m.isPlaying = msg.IsPlaying
+		m.isStopped = msg.Stopped
 		m.durationMs = msg.Duration
 		m.positionMs = msg.Position
 		m.volume = msg.Volume
+		m.repeat = msg.Repeat
+		if msg.Shuffle != "" && m.shuffleSynced {
+			// Follow the player, which may have been changed from elsewhere
+			m.shuffle = msg.Shuffle == "1"
+		}
 		m.lastUpdate = time.Now()
-		return m, nil
+
+		// Reload the queue view when the player moves on to another track
+		queueChanged := msg.PlayQueueID != m.playQueueID || msg.PlayQueueItemID != m.playQueueItemID
+		m.playQueueID = msg.PlayQueueID
+		m.playQueueItemID = msg.PlayQueueItemID
+		if queueChanged && m.panelMode == "queue" {
+			reportCmd = tea.Batch(reportCmd, m.fetchQueueCmd())
+		}
+		// And the up next line
+		if queueChanged {
+			reportCmd = tea.Batch(reportCmd, m.fetchUpNextCmd())
+		}
+		// And the lyrics view when it changes track
+		if m.panelMode == "lyrics" && m.current.trackKey != m.lyricsKey {
+			reportCmd = tea.Batch(reportCmd, m.fetchLyricsCmd())
+		}
+		if m.current.trackKey != m.mediaInfoKey {
+			reportCmd = tea.Batch(reportCmd, m.fetchMediaInfoCmd())
+		}
+		if m.current.trackKey != m.decisionKey {
+			reportCmd = tea.Batch(reportCmd, m.fetchDecisionCmd())
+		}
+		if m.isPlaying && !m.progressTicking {
+			m.progressTicking = true
+			return m, tea.Batch(reportCmd, progressTick())
+		}
+		return m, reportCmd
 
 	case trackMsg:
 		m.currentTrack = string(msg)
@@ -464,13 +1052,91 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.success {
 			m.lastCommand = "Playback Started"
 			m.status = "Playback triggered successfully"
+		} else if errors.Is(msg.err, errNoPlayer) {
+			m.lastCommand = "Playback Failed"
+			m.status = noPlayerStatus()
 		} else {
 			m.lastCommand = "Playback Failed"
 			m.status = fmt.Sprintf("Playback error: %v", msg.err)
 		}
 		return m, nil
 
+	case artistPlaybackMsg:
+		m.handlePlaybackResult("Artist", msg.success, msg.err, msg.played)
+		return m, nil
+
+	case albumPlaybackMsg:
+		m.handlePlaybackResult("Album", msg.success, msg.err, msg.played)
+		if msg.success && msg.played.MetadataKey == m.randomAlbumKey {
+			m.status = "Playing random album: " + m.randomAlbumName
+		}
+		return m, nil
+
+	case randomAlbumMsg:
+		return m, m.handleRandomAlbum(msg)
+
+	case playlistPlaybackMsg:
+		m.handlePlaybackResult("Playlist", msg.success, msg.err, msg.played)
+		return m, nil
+
+	case trackPlaybackMsg:
+		m.handlePlaybackResult("Track", msg.success, msg.err, msg.played)
+		return m, nil
+
+	case genrePlaybackMsg:
+		m.handlePlaybackResult("Genre", msg.success, msg.err, msg.played)
+		return m, nil
+
+	case commandSentMsg:
+		return m, m.handleCommandSent(msg)
+
+	case enqueuedMsg:
+		m.handleEnqueued(msg)
+		if msg.err != nil {
+			return m, nil
+		}
+		cmd := m.fetchUpNextCmd()
+		return m, cmd
+
+	case upNextMsg:
+		m.handleUpNext(msg)
+		return m, nil
+
+	case editSearchResultsMsg:
+		if m.panelMode == "edit" && m.editSearchActive {
+			modelPtr := &m
+			modelPtr.handleEditSearchUpdate(msg)
+		}
+		return m, nil
+
+	case favoritesPlaybackMsg:
+		m.handleFavoritesPlayback(msg)
+		return m, nil
+
+	case ratedMsg:
+		m.handleRated(msg)
+		return m, nil
+
+	case spinner.TickMsg:
+		return m, m.handleSpinnerTick(msg)
+
+	case historyFetchedMsg:
+		m.stopLoading("history")
+		// Forward the message to the history browse handler
+		if m.panelMode == "history" {
+			modelPtr := &m
+			updatedModel, cmd := modelPtr.handleHistoryBrowseUpdate(msg)
+			if updatedModel != nil {
+				if m2, ok := updatedModel.(model); ok {
+					m = m2
+				}
+			}
+			return m, cmd
+		}
+		return m, nil
+
 	case artistsFetchedMsg:
+		m.stopLoading("plex-artists")
 		// Forward the message to the artist browse handler
 		if m.panelMode == "plex-artists" {
 			modelPtr := &m
@@ -485,6 +1151,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case albumsFetchedMsg:
+		m.stopLoading("plex-albums")
 		// Forward the message to the album browse handler
 		if m.panelMode == "plex-albums" {
 			modelPtr := &m
@@ -498,7 +1165,23 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case tracksFetchedMsg:
+		m.stopLoading("plex-tracks")
+		// Forward the message to the track browse handler
+		if m.panelMode == "plex-tracks" {
+			modelPtr := &m
+			updatedModel, cmd := modelPtr.handleTrackBrowseUpdate(msg)
+			if updatedModel != nil {
+				if m2, ok := updatedModel.(model); ok {
+					m = m2
+				}
+			}
+			return m, cmd
+		}
+		return m, nil
+
 	case playlistsFetchedMsg:
+		m.stopLoading("plex-playlists")
 		// Forward the message to the playlist browse handler
 		if m.panelMode == "plex-playlists" {
 			modelPtr := &m
@@ -513,6 +1196,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case serversFetchedMsg:
+		m.stopLoading("plex-servers")
 		// Forward the message to the server browse handler
 		if m.panelMode == "plex-servers" {
 			modelPtr := &m
@@ -526,7 +1210,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case localPlayerMsg:
+		return m, m.handleLocalPlayer(msg)
+
 	case playersFetchedMsg:
+		m.stopLoading("plex-players")
 		// Forward the message to the player browse handler
 		if m.panelMode == "plex-players" {
 			modelPtr := &m
@@ -538,7 +1226,85 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, cmd
 		}
+		// Players refreshed in the background, such as after a profile switch
+		if msg.err == nil {
+			m.showPlayers(msg.players)
+		}
+		return m, nil
+
+	case queueFetchedMsg, queueItemRemovedMsg, queueClearedMsg:
+		m.stopLoading("queue")
+		if fetched, ok := msg.(queueFetchedMsg); ok && fetched.err == nil {
+			m.setUpNext(fetched.queue)
+		}
+		// Forward the message to the play queue handler
+		if m.panelMode == "queue" {
+			modelPtr := &m
+			updatedModel, cmd := modelPtr.handleQueueBrowseUpdate(msg)
+			if updatedModel != nil {
+				if m2, ok := updatedModel.(model); ok {
+					m = m2
+				}
+			}
+			return m, cmd
+		}
+		return m, nil
+
+	case genresFetchedMsg:
+		m.stopLoading("plex-genres")
+		// Forward the message to the genre browse handler
+		if m.panelMode == "plex-genres" {
+			modelPtr := &m
+			updatedModel, cmd := modelPtr.handleGenreBrowseUpdate(msg)
+			if updatedModel != nil {
+				if m2, ok := updatedModel.(model); ok {
+					m = m2
+				}
+			}
+			return m, cmd
+		}
+		return m, nil
+
+	case profilesFetchedMsg:
+		m.stopLoading("plex-profiles")
+		// Forward the message to the profile browse handler
+		if m.panelMode == "plex-profiles" {
+			modelPtr := &m
+			updatedModel, cmd := modelPtr.handleProfileBrowseUpdate(msg)
+			if updatedModel != nil {
+				if m2, ok := updatedModel.(model); ok {
+					m = m2
+				}
+			}
+			return m, cmd
+		}
+		return m, nil
+
+	case librariesFetchedMsg:
+		// Forward the message to the library browse handler
+		if m.panelMode == "libraries" {
+			modelPtr := &m
+			updatedModel, cmd := modelPtr.handleLibraryBrowseUpdate(msg)
+			if updatedModel != nil {
+				if m2, ok := updatedModel.(model); ok {
+					m = m2
+				}
+			}
+			return m, cmd
+		}
 		return m, nil
+
+	case profileSwitchedMsg:
+		m.plexAuthenticated = msg.authenticated
+		m.lastCommand = fmt.Sprintf("Profile %s", msg.profile)
+		if !msg.authenticated {
+			m.status = fmt.Sprintf("Profile %s is not authenticated - run with --auth --profile %s", msg.profile, msg.profile)
+			return m, nil
+		}
+		// Servers and players belong to the account, so reload them for the new profile
+		m.initServerBrowse()
+		m.status = fmt.Sprintf("Switched to profile %s", msg.profile)
+		return m, tea.Batch(m.fetchServersCmd(), m.fetchPlayersCmd())
 	}
 
 	// Update the appropriate list based on panel mode
@@ -549,24 +1315,53 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.artistList, cmd = m.artistList.Update(msg)
 	} else if m.panelMode == "plex-albums" {
 		m.albumList, cmd = m.albumList.Update(msg)
+	} else if m.panelMode == "plex-tracks" {
+		m.trackList, cmd = m.trackList.Update(msg)
 	} else if m.panelMode == "plex-playlists" {
 		m.playlistList, cmd = m.playlistList.Update(msg)
 	} else if m.panelMode == "plex-servers" {
 		m.serverList, cmd = m.serverList.Update(msg)
 	} else if m.panelMode == "plex-players" {
 		m.playerList, cmd = m.playerList.Update(msg)
+	} else if m.panelMode == "history" {
+		m.historyList, cmd = m.historyList.Update(msg)
+	} else if m.panelMode == "plex-profiles" {
+		m.profileList, cmd = m.profileList.Update(msg)
+	} else if m.panelMode == "queue" {
+		m.queueList, cmd = m.queueList.Update(msg)
+	} else if m.panelMode == "plex-genres" {
+		m.genreList, cmd = m.genreList.Update(msg)
+	} else if m.panelMode == "libraries" {
+		m.libraryList, cmd = m.libraryList.Update(msg)
 	}
 	return m, cmd
 }
 
 func (m model) View() string {
 	border := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
-	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00ffff")).Render("🎧 Plexamp Control")
+	title := m.titleView()
+
+	// The compact view is meant for panes too small for the layout
+	if m.compact && !m.helpVisible {
+		return m.compactView()
+	}
+
+	if m.tooSmall() {
+		return m.tooSmallView()
+	}
+
+	if m.helpVisible {
+		return lipgloss.JoinVertical(lipgloss.Left, title, border.Width(max(m.width-4, 1)).Render(m.helpView()))
+	}
+
+	if m.artistInfoVisible {
+		return lipgloss.JoinVertical(lipgloss.Left, title, m.artistInfoView(max(m.height-lipgloss.Height(title), 1)))
+	}
 
 	// Show edit panel if in edit mode
 	if m.panelMode == "edit" {
 		editContent := m.editPanelView()
-		editPanel := border.Width(m.width - 4).Render(editContent)
+		editPanel := border.Width(max(m.width-4, 1)).Render(editContent)
 		return lipgloss.JoinVertical(lipgloss.Left, title, editPanel)
 	}
 
@@ -576,26 +1371,59 @@ func (m model) View() string {
 	case "playback":
 		leftPanelContent = m.playbackList.View()
 	case "plex-artists":
-		leftPanelContent = m.artistList.View()
+		leftPanelContent = m.browseView(m.artistList)
 	case "plex-albums":
-		leftPanelContent = m.albumList.View()
+		leftPanelContent = m.browseView(m.albumList)
+	case "plex-tracks":
+		leftPanelContent = m.browseView(m.trackList)
 	case "plex-playlists":
-		leftPanelContent = m.playlistList.View()
+		leftPanelContent = m.browseView(m.playlistList)
+	case "plex-genres":
+		leftPanelContent = m.browseView(m.genreList)
 	case "plex-servers":
-		leftPanelContent = m.serverList.View()
+		leftPanelContent = m.browseView(m.serverList)
 	case "plex-players":
-		leftPanelContent = m.playerList.View()
+		leftPanelContent = m.browseView(m.playerList)
+	case "history":
+		leftPanelContent = m.browseView(m.historyList)
+	case "plex-profiles":
+		leftPanelContent = m.browseView(m.profileList)
+	case "queue":
+		leftPanelContent = m.browseView(m.queueList)
+	case "libraries":
+		leftPanelContent = m.libraryList.View()
+	case "lyrics":
+		leftPanelContent = m.lyricsView()
 	}
 
 	// Left panel
-	leftPanel := border.Width(m.width/2 - 2).Render(leftPanelContent)
+	leftPanel := m.paneBorder(focusList).Width(m.panelWidth()).Render(leftPanelContent)
 
-	// Right side has two stacked panels
-	playbackPanel := border.Width(m.width/2 - 2).Render(m.playbackStatusView())
-	controlsPanel := border.Width(m.width/2 - 2).Render(m.appControlsView())
-	rightSide := lipgloss.JoinVertical(lipgloss.Left, playbackPanel, controlsPanel)
+	// Right side has up to two stacked panels, in the order of the layout
+	var rightPanels []string
+	if m.layout.status {
+		rightPanels = append(rightPanels, m.paneBorder(focusPlayback).Width(m.panelWidth()).Render(m.playbackStatusView()))
+	}
+	if m.layout.controls && !m.stacked() {
+		// Narrow terminals get a single column; the controls list doesn't fit
+		controlsPanel := border.Width(m.panelWidth()).Render(m.appControlsView())
+		if m.layout.controlsFirst {
+			rightPanels = append([]string{controlsPanel}, rightPanels...)
+		} else {
+			rightPanels = append(rightPanels, controlsPanel)
+		}
+	}
 
-	content := lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, rightSide)
+	var content string
+	switch {
+	case len(rightPanels) == 0:
+		content = leftPanel
+	case m.stacked():
+		content = lipgloss.JoinVertical(lipgloss.Left, leftPanel, rightPanels[0])
+	default:
+		rightSide := lipgloss.JoinVertical(lipgloss.Left, rightPanels...)
+		content = lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, rightSide)
+	}
 
 	// Combine all elements with the footer at the bottom
 	return lipgloss.JoinVertical(lipgloss.Left,
@@ -616,84 +1444,144 @@ func max(a, b int) int {
 // Plexamp control logic
 // =====================
 
-func (m *model) sendCommand(path string) {
+// commandSentMsg reports whether the player took a command
+type commandSentMsg struct {
+	path string
+	err  error
+}
+
+// sendCommand returns a command sending path to the player. It returns nil,
+// with the reason in the status line, when there is no player to send it to,
+// so callers can leave their state alone.
+func (m *model) sendCommand(path string) tea.Cmd {
 	if m.selected == "" {
-		m.status = "No Plexamp instance selected"
-		return
+		m.status = noPlayerStatus()
+		return nil
+	}
+	if m.playerDown() {
+		m.status = "Player unreachable, command not sent"
+		return nil
 	}
 	url := fmt.Sprintf("http://%s:32500/player/%s", m.selected, path)
-	go func() {
-		_, err := http.Get(url)
-		if err != nil {
-			m.status = fmt.Sprintf("Error: %v", err)
-		} else {
-			m.status = fmt.Sprintf("[%s] Sent %s", m.selected, path)
+	return func() tea.Msg {
+		resp, err := plexClient.GetWithRetry(url)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode >= http.StatusBadRequest {
+				err = fmt.Errorf("player returned status %d", resp.StatusCode)
+			}
 		}
-	}()
-	time.Sleep(50 * time.Millisecond)
+		return commandSentMsg{path: path, err: err}
+	}
 }
 
+// handleCommandSent reports a command the player didn't take, then polls the
+// timeline either way: to show the command's effect, or to undo the state the
+// caller assumed it would have
+func (m *model) handleCommandSent(msg commandSentMsg) tea.Cmd {
+	if msg.err != nil {
+		command, _, _ := strings.Cut(msg.path, "?")
+		log.Warn("Player command %s failed: %v", command, msg.err)
+		m.status = fmt.Sprintf("Error sending %s: %v", command, msg.err)
+	}
+	return m.pollTimeline()
+}
+
+// pollTimeline fetches the player's timeline once, outside the polling loop,
+// e.g. to show the effect of a command straight away
 func (m *model) pollTimeline() tea.Cmd {
+	return m.timelineCmd(0)
+}
+
+// timelineCmd fetches the player's timeline, tagging the answer with the
+// number of the loop poll it belongs to. Loop polls are long polls the player
+// holds until something changes; a one-off poll is answered straight away.
+func (m *model) timelineCmd(loopSeq int) tea.Cmd {
 	if m.selected == "" {
 		return nil
 	}
 	reqID := m.timelineRequestID
 	selected := m.selected
+	client, wait := plexClient.HTTPClient(), 0
+	if loopSeq != 0 {
+		client, wait = longPollClient, 1
+	}
 
 	return func() tea.Msg {
-		url := fmt.Sprintf("http://%s:32500/player/timeline/poll?wait=1&includeMetadata=1&commandID=1&type=music", selected)
-		resp, err := http.Get(url)
+		url := fmt.Sprintf("http://%s:32500/player/timeline/poll?wait=%d&includeMetadata=1&commandID=1&type=music", selected, wait)
+		resp, err := client.Get(url)
 		if err != nil {
-			return trackMsgWithState{RequestID: reqID, TrackText: "", IsPlaying: false, Duration: 0, Position: 0, Volume: 0}
+			return trackMsgWithState{RequestID: reqID, LoopSeq: loopSeq, Err: err}
 		}
 		defer resp.Body.Close()
 
 		data, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return trackMsgWithState{RequestID: reqID, TrackText: "", IsPlaying: false, Duration: 0, Position: 0, Volume: 0}
+			return trackMsgWithState{RequestID: reqID, LoopSeq: loopSeq, Stopped: true, DataErr: err}
 		}
 
-		var mc MediaContainer
-		if err := xml.Unmarshal(data, &mc); err != nil {
-			return trackMsgWithState{RequestID: reqID, TrackText: "", IsPlaying: false, Duration: 0, Position: 0, Volume: 0}
+		msg, err := parseTimeline(data)
+		if resp.StatusCode != http.StatusOK {
+			msg, err = trackMsgWithState{Stopped: true}, fmt.Errorf("status %d", resp.StatusCode)
 		}
-
-		var chosen *Timeline
-		for i := range mc.Timelines {
-			t := &mc.Timelines[i]
-			if t.Type == "music" {
-				chosen = t
-				break
-			}
-		}
-		if chosen == nil && len(mc.Timelines) > 0 {
-			chosen = &mc.Timelines[0]
+		if err != nil {
+			log.Debug("Unexpected timeline from %s (%v): %.1000s", selected, err, data)
+			msg.DataErr = err
 		}
+		msg.RequestID = reqID
+		msg.LoopSeq = loopSeq
+		return msg
+	}
+}
 
-		track := ""
-		isPlaying := false
-		duration := 0
-		position := 0
-		volume := 0
-		if chosen != nil {
-			if chosen.Track.Title != "" {
-				track = fmt.Sprintf("%s - %s (%s)", chosen.Track.GrandparentTitle, chosen.Track.Title, chosen.Track.ParentTitle)
-			}
-			isPlaying = chosen.State == "playing"
-			duration = chosen.Duration
-			position = chosen.Time
-			volume = chosen.Volume
-		}
+// parseTimeline turns a timeline poll response into the playback state. A
+// stopped player, a response without a music timeline and one that can't be
+// parsed all give a cleared, stopped state, so no stale track stays on screen.
+// Only the last returns an error.
+func parseTimeline(data []byte) (trackMsgWithState, error) {
+	var mc MediaContainer
+	if err := xml.Unmarshal(data, &mc); err != nil {
+		return trackMsgWithState{Stopped: true}, fmt.Errorf("not a timeline: %w", err)
+	}
 
-		return trackMsgWithState{
-			TrackText: track,
-			IsPlaying: isPlaying,
-			Duration:  duration,
-			Position:  position,
-			Volume:    volume,
-			RequestID: reqID,
+	var chosen *Timeline
+	for i := range mc.Timelines {
+		if mc.Timelines[i].Type == "music" {
+			chosen = &mc.Timelines[i]
+			break
 		}
 	}
+	if chosen == nil {
+		return trackMsgWithState{Stopped: true}, nil
+	}
+
+	msg := trackMsgWithState{
+		Volume:  chosen.Volume,
+		Repeat:  chosen.Repeat,
+		Shuffle: chosen.Shuffle,
+	}
+	// A stopped player may still report its last track, which is no longer loaded
+	if chosen.State == "stopped" {
+		msg.Stopped = true
+		return msg, nil
+	}
+
+	if chosen.Track.Title != "" {
+		msg.TrackText = fmt.Sprintf("%s - %s (%s)", chosen.Track.GrandparentTitle, chosen.Track.Title, chosen.Track.ParentTitle)
+	}
+	msg.Artist = chosen.Track.GrandparentTitle
+	msg.Title = chosen.Track.Title
+	msg.Album = chosen.Track.ParentTitle
+	msg.Thumb = chosen.Track.Thumb
+	msg.IsPlaying = chosen.State == "playing"
+	msg.Duration = chosen.Duration
+	msg.Position = chosen.Time
+	msg.PlayQueueID = chosen.PlayQueueID
+	msg.PlayQueueItemID = chosen.PlayQueueItemID
+	msg.TrackKey = chosen.Track.RatingKey
+	msg.ArtistKey = chosen.Track.GrandparentRatingKey
+	msg.AlbumKey = chosen.Track.ParentRatingKey
+	return msg, nil
 }
 
 // =====================
@@ -714,6 +1602,21 @@ func (m model) currentPosition() int {
 	return pos
 }
 
+// buildAlbumArtURL resolves a timeline thumb path against the configured Plex server
+// Returns an empty string when there is no thumb to resolve. The token stays in
+// the query string since the URL is handed to clients that fetch it themselves.
+func (m model) buildAlbumArtURL(thumb string) string {
+	if thumb == "" || m.config == nil || m.config.ServerURL() == "" {
+		return ""
+	}
+	base := plex.ServerBaseURL(m.config.ServerURL())
+	token := plexClient.GetPlexToken()
+	if token == "" {
+		return base + thumb
+	}
+	return fmt.Sprintf("%s%s?X-Plex-Token=%s", base, thumb, url.QueryEscape(token))
+}
+
 func formatTime(ms int) string {
 	if ms <= 0 {
 		return "0:00"
@@ -724,49 +1627,79 @@ func formatTime(ms int) string {
 	return fmt.Sprintf("%d:%02d", m, s)
 }
 
-func progressBar(pos, dur, width int) string {
-	if dur <= 0 || width <= 0 {
-		bar := "["
-		for i := 0; i < width; i++ {
-			bar += "-"
-		}
-		bar += "]"
-		return bar
+// progressBar draws how far pos is into dur with block glyphs, the played
+// part in the accent color. With ascii_progress_bar set it is drawn with
+// # and - for terminals that can't show the glyphs.
+func (m model) progressBar(pos, dur, width int) string {
+	filledGlyph, emptyGlyph, left, right := "▰", "▱", "", ""
+	if m.config != nil && m.config.ASCIIProgressBar {
+		filledGlyph, emptyGlyph, left, right = "#", "-", "[", "]"
 	}
-	f := float64(pos) / float64(dur)
-	if f < 0 {
-		f = 0
+	if width <= 0 {
+		return left + right
 	}
-	if f > 1 {
-		f = 1
+
+	filled := 0
+	if dur > 0 {
+		filled = min(max(int(float64(pos)/float64(dur)*float64(width)), 0), width)
 	}
-	filled := int(f * float64(width))
-	bar := "["
-	for i := 0; i < width; i++ {
-		if i < filled {
-			bar += "#"
-		} else {
-			bar += "-"
-		}
+	played := lipgloss.NewStyle().Foreground(theme.Accent).Render(strings.Repeat(filledGlyph, filled))
+	rest := lipgloss.NewStyle().Foreground(theme.Muted).Render(strings.Repeat(emptyGlyph, width-filled))
+	return left + played + rest + right
+}
+
+// remainingTime formats the time left of the playing item, e.g. "-2:12", or
+// returns "" when its duration isn't known
+func remainingTime(pos, dur int) string {
+	if dur <= 0 {
+		return ""
 	}
-	bar += "]"
-	return bar
+	return "-" + formatTime(max(dur-pos, 0))
 }
 
-// setVolume sets the volume directly to the specified value (0-100)
-func (m *model) setVolume(v int) {
-	if m.selected == "" {
-		return
+// setVolume returns a command setting the volume to the specified value
+// (0-100), or nil when it can't be sent
+func (m *model) setVolume(v int) tea.Cmd {
+	cmd := m.sendCommand(fmt.Sprintf("playback/setParameters?volume=%d&commandID=1&type=music", v))
+	if cmd != nil {
+		m.volume = v
+	}
+	return cmd
+}
+
+// handleAuthError marks Plex as unauthenticated when a request was rejected
+// because the token expired or was revoked. Returns true if the error was handled.
+func (m *model) handleAuthError(err error) bool {
+	if !errors.Is(err, plex.ErrUnauthorized) {
+		return false
+	}
+	log.Warn("Plex rejected the stored token: %v", err)
+	m.plexAuthenticated = false
+	m.status = "Plex token expired — run with --auth"
+	return true
+}
+
+// handlePlaybackResult updates the status after a play command finishes and
+// records successful plays in the history
+func (m *model) handlePlaybackResult(label string, success bool, err error, played config.HistoryItem) {
+	switch {
+	case success:
+		m.lastCommand = label + " Playback Started"
+		m.status = "Playback triggered successfully"
+		m.recordPlayback(played)
+	case errors.Is(err, errNoPlayer):
+		m.lastCommand = "Playback Failed"
+		m.status = noPlayerStatus()
+	default:
+		m.lastCommand = "Playback Failed"
+		m.status = fmt.Sprintf("Playback error: %v", err)
 	}
-	m.volume = v
-	url := fmt.Sprintf("http://%s:32500/player/playback/setParameters?volume=%d&commandID=1&type=music", m.selected, v)
-	go func() { _, _ = http.Get(url) }()
 }
 
 func (m *model) triggerPlaybackCmd(fullURL string) tea.Cmd {
 	if m.selected == "" {
 		return func() tea.Msg {
-			return playbackTriggeredMsg{success: false, err: fmt.Errorf("no server selected")}
+			return playbackTriggeredMsg{success: false, err: errNoPlayer}
 		}
 	}
 
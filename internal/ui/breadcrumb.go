@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"strings"
+
+	"plexamp-tui/internal/config"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// breadcrumbSeparator joins the parts of the breadcrumb
+const breadcrumbSeparator = " ▸ "
+
+// breadcrumbs describes where the left panel is, from the top level down to
+// what a browse is scoped to, e.g. Music ▸ Artists ▸ Radiohead ▸ Albums
+func (m model) breadcrumbs() []string {
+	switch m.panelMode {
+	case "playback":
+		return []string{"Favorites"}
+	case "edit":
+		return []string{"Favorites", "Edit"}
+	case "plex-artists":
+		return []string{m.libraryCrumb(), "Artists"}
+	case "plex-albums":
+		switch {
+		case m.albumArtistKey != "":
+			return []string{m.libraryCrumb(), "Artists", m.albumScopeName, "Albums"}
+		case m.albumFilter != "":
+			return []string{m.libraryCrumb(), filterCrumb(m.albumFilter), m.albumScopeName, "Albums"}
+		}
+		return []string{m.libraryCrumb(), "Albums"}
+	case "plex-tracks":
+		return m.trackCrumbs
+	case "plex-playlists":
+		return []string{m.libraryCrumb(), "Playlists"}
+	case "plex-genres":
+		return []string{m.libraryCrumb(), filterCrumb(m.genreFilter)}
+	case "libraries":
+		return []string{"Libraries"}
+	case "history":
+		return []string{"History"}
+	case "queue":
+		return []string{"Play Queue"}
+	case "lyrics":
+		return []string{"Lyrics"}
+	case "plex-servers":
+		return []string{"Servers"}
+	case "plex-players":
+		return []string{"Players"}
+	case "plex-profiles":
+		return []string{"Profiles"}
+	}
+	return nil
+}
+
+// libraryCrumb names the library being browsed, falling back to "Library"
+// until one has been chosen
+func (m model) libraryCrumb() string {
+	if m.config == nil || m.config.PlexLibraryName == "" || config.IsPlaceholder(m.config.PlexLibraryName) {
+		return "Library"
+	}
+	return m.config.PlexLibraryName
+}
+
+// filterCrumb names the list of values of a library filter
+func filterCrumb(filter string) string {
+	if filter == "decade" {
+		return "Decades"
+	}
+	return "Genres"
+}
+
+// breadcrumbView renders the breadcrumb on one line, cut to the terminal width
+func (m model) breadcrumbView() string {
+	style := lipgloss.NewStyle().Foreground(theme.Muted)
+	if m.width > 0 {
+		style = style.MaxWidth(m.width)
+	}
+	return style.Render(strings.Join(m.breadcrumbs(), breadcrumbSeparator))
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// compactBarWidth is the width of the progress bar in the compact view
+const compactBarWidth = 10
+
+// toggleCompact switches between the full layout and the one-line compact
+// view, and saves the choice for the next launch
+func (m *model) toggleCompact() {
+	m.compact = !m.compact
+	m.focusedPane = focusList
+	if m.compact {
+		m.lastCommand = "Compact view"
+	} else {
+		m.lastCommand = "Full view"
+		m.resizeLists()
+	}
+	if m.config != nil {
+		m.config.CompactView = m.compact
+		cfgManager.Save(m.config)
+	}
+}
+
+// handleCompactKey handles a key in the compact view. The arrows and digits
+// work as in the Now Playing pane, and the global keys as everywhere else.
+// Keys that act on a list are ignored, since none is shown.
+func (m *model) handleCompactKey(key string) tea.Cmd {
+	if key == "esc" {
+		return nil
+	}
+	if cmd, handled := m.handlePlaybackPaneKey(key); handled {
+		return cmd
+	}
+	cmd, _ := m.handleControl(key)
+	return cmd
+}
+
+// compactView renders the playback state on a single line, e.g.
+// "▶ Artist – Track [##--------] 1:23/3:45 🔊70". Questions waiting for an
+// answer and open prompts take the line's place until they are dealt with.
+func (m model) compactView() string {
+	line := m.compactStatus()
+	switch {
+	case m.quitConfirm, m.resume != nil:
+		line = m.status
+	case m.volumeInputActive:
+		line = m.volumeInputView()
+	case m.sleepInputActive:
+		line = m.sleepInputView()
+	}
+	if m.width > 0 {
+		return lipgloss.NewStyle().MaxWidth(m.width).Render(line)
+	}
+	return line
+}
+
+// compactStatus describes what is playing in one line
+func (m model) compactStatus() string {
+	if m.isStopped || m.current.title == "" {
+		return "⏹ Nothing playing"
+	}
+
+	state := "⏸"
+	if m.isPlaying {
+		state = "▶"
+	}
+	track := m.current.title
+	if m.current.artist != "" {
+		track = m.current.artist + " – " + track
+	}
+
+	elapsed := m.currentPosition()
+	volume := fmt.Sprintf("🔊%d", m.volume)
+	if m.muted {
+		volume = "🔇"
+	}
+
+	return fmt.Sprintf("%s %s %s %s/%s %s", state, track,
+		m.progressBar(elapsed, m.durationMs, compactBarWidth), formatTime(elapsed), formatTime(m.durationMs), volume)
+}
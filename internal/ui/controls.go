@@ -1,6 +1,10 @@
 package ui
 
-import tea "github.com/charmbracelet/bubbletea"
+import (
+	"plexamp-tui/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
 
 // handleControl processes common playback control key presses
 // Returns the command to execute and a boolean indicating if a control was handled
@@ -8,11 +12,27 @@ import tea "github.com/charmbracelet/bubbletea"
 func (m *model) refreshCurrentPanel() tea.Cmd {
 	switch m.panelMode {
 	case "plex-artists":
+		m.libraryCache.invalidate()
 		return m.fetchArtistsCmd()
 	case "plex-albums":
+		m.libraryCache.invalidate()
 		return m.fetchAlbumsCmd()
+	case "plex-tracks":
+		return m.fetchTracksCmd()
 	case "plex-playlists":
 		return m.fetchPlaylistsCmd()
+	case "history":
+		return m.fetchHistoryCmd()
+	case "plex-profiles":
+		return m.fetchProfilesCmd()
+	case "queue":
+		return m.fetchQueueCmd()
+	case "lyrics":
+		return m.fetchLyricsCmd()
+	case "plex-genres":
+		return m.fetchGenresCmd()
+	case "libraries":
+		return m.fetchLibrariesCmd()
 	default:
 		return nil
 	}
@@ -22,45 +42,144 @@ func (m *model) refreshCurrentPanel() tea.Cmd {
 // Returns the command to execute and a boolean indicating if a control was handled
 func (m *model) handleControl(key string) (tea.Cmd, bool) {
 	switch key {
-	case " ", "p": // Space or 'p' for play/pause
+	case "alt+0", "alt+1", "alt+2", "alt+3", "alt+4", "alt+5", "alt+6", "alt+7", "alt+8", "alt+9":
+		// Jump to 0-90% of the track; the plain digits open panels
+		return m.seekPercent(int(key[len(key)-1]-'0') * 10), true
+	}
+
+	// Everything else is looked up in the keymap
+	switch keyBindings[key] {
+	case config.ActionPlayPause:
 		return m.togglePlayback(), true
 
-	case "n": // Next track
+	case config.ActionStop:
+		return m.stopPlayback(), true
+
+	case config.ActionNext:
 		return m.nextTrack(), true
 
-	case "b": // Previous track
+	case config.ActionPrevious:
 		return m.previousTrack(), true
 
-	case "+", "]": // Volume up
-		return m.adjustVolume(5), true
+	case config.ActionVolumeUp:
+		return m.adjustVolume(m.volumeStep), true
+
+	case config.ActionVolumeDown:
+		return m.adjustVolume(-m.volumeStep), true
+
+	case config.ActionVolumeUpBig: // Fixed jump, whatever the configured step
+		return m.adjustVolume(bigVolumeStep), true
+
+	case config.ActionVolumeDownBig:
+		return m.adjustVolume(-bigVolumeStep), true
+
+	case config.ActionSeekBack: // Seek back a minute
+		return m.seek(-60), true
 
-	case "-", "[": // Volume down
-		return m.adjustVolume(-5), true
+	case config.ActionSeekForward: // Seek forward a minute
+		return m.seek(60), true
 
-	case "h": // Toggle shuffle
+	case config.ActionSetVolume: // Enter an absolute volume
+		return m.openVolumeInput(), true
+
+	case config.ActionMute: // Mute, or restore the volume from before muting
+		return m.toggleMute(), true
+
+	case config.ActionSleepTimer: // Start or cancel the sleep timer
+		return m.toggleSleepTimer(), true
+
+	case config.ActionShuffle:
 		return m.toggleShuffle(), true
 
-	case "tab": // Cycle library
+	case config.ActionRepeat: // Cycle repeat mode
+		return m.toggleRepeat(), true
+
+	case config.ActionCrossfade: // Cycle crossfade duration
+		return m.toggleCrossfade(), true
+
+	case config.ActionSpeedUp: // Speed up playback, for audiobooks and podcasts
+		return m.adjustSpeed(speedStep), true
+
+	case config.ActionSpeedDown: // Slow down playback
+		return m.adjustSpeed(-speedStep), true
+
+	case config.ActionSpeedReset: // Back to normal speed
+		return m.resetSpeed(), true
+
+	case config.ActionCycleLibrary:
 		return m.cycleLibrary(), true
 
-	case "r": // Refresh current panel
+	case config.ActionCycleTheme:
+		return m.cycleTheme(), true
+
+	case config.ActionRefresh: // Refresh current panel
 		return m.refreshCurrentPanel(), true
 
-	case "1": // Open artist browse
+	case config.ActionArtists:
 		return m.openArtistBrowser()
 
-	case "2": // Open album browse
+	case config.ActionAlbums:
 		return m.openAlbumBrowser()
 
-	case "3": // Open playlist browse
+	case config.ActionPlaylists:
 		return m.openPlaylistBrowser()
 
-	case "6": // Open server browse
+	case config.ActionHistory: // Open recently played
+		return m.openHistoryBrowser()
+
+	case config.ActionProfiles: // Open profile switcher
+		return m.openProfileBrowser()
+
+	case config.ActionQueue: // Open the play queue
+		return m.openQueueBrowser()
+
+	case config.ActionLyrics: // Show the playing track's lyrics
+		return m.openLyrics(), true
+
+	case config.ActionCompact: // Collapse to a single status line and back
+		m.toggleCompact()
+		return nil, true
+
+	case config.ActionCopyKey: // Copy a rating key for adding favorites by hand
+		m.copyRatingKey()
+		return nil, true
+
+	case config.ActionRandomAlbum: // Surprise me
+		return m.playRandomAlbum(), true
+
+	case config.ActionPanelLayout: // Show, hide or swap the right-hand panels
+		m.cyclePanelLayout()
+		return nil, true
+
+	case config.ActionPollInterval: // Change how often the timeline is polled
+		m.cyclePollInterval()
+		return nil, true
+
+	case config.ActionGoToAlbum: // Open the playing track's album
+		return m.goToCurrentAlbum(), true
+
+	case config.ActionGoToArtist: // Open the playing track's artist
+		return m.goToCurrentArtist(), true
+
+	case config.ActionQuit: // q only goes back, so quitting has its own key
+		return m.requestQuit(), true
+
+	case config.ActionHelp: // Replaces the lists' own help, which the overlay includes
+		m.helpVisible = true
+		return nil, true
+
+	case config.ActionServers:
 		return m.openServerBrowser()
 
-	case "7": // Open player browse
+	case config.ActionPlayers:
 		return m.openPlayerBrowser()
 
+	case config.ActionGenres:
+		return m.openGenreBrowser()
+
+	case config.ActionLibraries: // Pick the library to browse
+		return m.openLibraryBrowser()
+
 	default:
 		return nil, false
 	}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/atotto/clipboard"
+)
+
+// selectedRatingKey returns the name and rating key of the item selected in
+// the focused list, or of the playing track when the list item has none or
+// Now Playing has the focus
+func (m *model) selectedRatingKey() (name, ratingKey string) {
+	if m.focusedPane != focusPlayback {
+		if l := m.activeList(); l != nil {
+			switch it := l.SelectedItem().(type) {
+			case item:
+				name, ratingKey = it.Name, it.MetadataKey
+			case artistItem:
+				name, ratingKey = it.title, it.ratingKey
+			case albumItem:
+				name, ratingKey = it.title, it.ratingKey
+			case trackItem:
+				name, ratingKey = it.title, it.ratingKey
+			case playlistItem:
+				name, ratingKey = it.title, it.ratingKey
+			case historyItem:
+				name, ratingKey = it.entry.Name, it.entry.MetadataKey
+			case queueItem:
+				name, ratingKey = it.track.Title, it.track.RatingKey
+			}
+		}
+	}
+	if ratingKey == "" {
+		name, ratingKey = m.current.title, m.current.trackKey
+	}
+	return strings.TrimSuffix(name, " ★"), ratingKey
+}
+
+// copyRatingKey copies the rating key of the selected or playing item to
+// the clipboard, for adding favorites by hand. Without a clipboard, as over
+// SSH, the key is shown in the status line instead.
+func (m *model) copyRatingKey() {
+	name, ratingKey := m.selectedRatingKey()
+	if ratingKey == "" {
+		m.status = "Nothing selected or playing to copy"
+		return
+	}
+
+	// Writing also fails when no clipboard tool is installed or no display
+	// can be reached
+	if clipboard.Unsupported {
+		m.status = fmt.Sprintf("Rating key of %s: %s (no clipboard available)", name, ratingKey)
+		return
+	}
+	if err := clipboard.WriteAll(ratingKey); err != nil {
+		log.Debug("Failed to write to the clipboard: %v", err)
+		m.status = fmt.Sprintf("Rating key of %s: %s (no clipboard available)", name, ratingKey)
+		return
+	}
+	m.status = fmt.Sprintf("Copied to clipboard: rating key %s of %s", ratingKey, name)
+}
@@ -1,7 +1,9 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"plexamp-tui/internal/config"
 
@@ -17,6 +19,14 @@ func (i typeItem) Title() string       { return string(i) }
 func (i typeItem) Description() string { return "" }
 func (i typeItem) FilterValue() string { return string(i) }
 
+// editFieldError is a validation error for one field of the edit panel
+type editFieldError struct {
+	field int // Focus index of the field: 0 name, 1 type, 2 metadata key, 3 tags
+	msg   string
+}
+
+func (e editFieldError) Error() string { return e.msg }
+
 // =====================
 // Edit Mode Functions
 // =====================
@@ -28,6 +38,8 @@ func (m *model) initEditMode(editType string, index int) {
 	m.editMode = editType
 	m.editIndex = index
 	m.editFocusIndex = 0
+	m.editErrors = nil
+	m.editSearchActive = false
 
 	if editType == "playback" {
 		// Two inputs: name and URL
@@ -42,11 +54,17 @@ func (m *model) initEditMode(editType string, index int) {
 		metadataKeyInput.CharLimit = 1000
 		metadataKeyInput.Width = 50
 
+		tagsInput := textinput.New()
+		tagsInput.Placeholder = "workout, focus"
+		tagsInput.CharLimit = 200
+		tagsInput.Width = 50
+
 		// Create list for type selection
 		typeItems := []list.Item{
 			typeItem("Artist"),
 			typeItem("Album"),
 			typeItem("Playlist"),
+			typeItem("Station"),
 		}
 
 		// Initialize the list with default settings
@@ -64,6 +82,8 @@ func (m *model) initEditMode(editType string, index int) {
 				typeSelect.Select(1)
 			case "playlist":
 				typeSelect.Select(2)
+			case "station":
+				typeSelect.Select(3)
 			}
 		}
 
@@ -73,19 +93,30 @@ func (m *model) initEditMode(editType string, index int) {
 		if index >= 0 && m.playbackConfig != nil && index < len(m.playbackConfig.Items) {
 			nameInput.SetValue(m.playbackConfig.Items[index].Name)
 			metadataKeyInput.SetValue(m.playbackConfig.Items[index].MetadataKey)
+			tagsInput.SetValue(strings.Join(m.playbackConfig.Items[index].Tags, ", "))
 		}
 
-		m.editInputs = []textinput.Model{nameInput, metadataKeyInput}
+		m.editInputs = []textinput.Model{nameInput, metadataKeyInput, tagsInput}
+	} else if editType == "server" {
+		m.initServerEdit()
 	}
 }
 
 // handleEditUpdate processes updates in edit mode
 func (m *model) handleEditUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.editMode == "server" {
+		return m.handleServerEditUpdate(msg)
+	}
+	if m.editSearchActive {
+		return m.handleEditSearchUpdate(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch key := msg.String(); key {
-		case "ctrl+c":
-			return m, tea.Quit
+		case "ctrl+f":
+			// Look the item up in the library instead of typing its key
+			return m, m.openEditSearch()
 
 		case "esc":
 			// Cancel edit and return to previous mode
@@ -93,17 +124,24 @@ func (m *model) handleEditUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case "enter":
-			// Save changes
-			if err := m.savePlaybackEdit(); err != nil {
+			// Save changes; on a validation error stay here and point at the field
+			err := m.savePlaybackEdit()
+			var fieldErr editFieldError
+			switch {
+			case errors.As(err, &fieldErr):
+				m.editErrors = map[int]string{fieldErr.field: fieldErr.msg}
+				m.editFocusIndex = fieldErr.field
+				m.updateFocus()
+			case err != nil:
 				m.lastCommand = fmt.Sprintf("Save failed: %v", err)
-			} else {
+			default:
 				m.lastCommand = "Saved successfully"
 			}
 			return m, nil
 
 		case "tab":
 			// Move focus to next element
-			m.editFocusIndex = (m.editFocusIndex + 1) % 3 // We have 3 focusable elements now
+			m.editFocusIndex = (m.editFocusIndex + 1) % 4 // We have 4 focusable elements now
 			m.updateFocus()
 			return m, nil
 
@@ -111,7 +149,7 @@ func (m *model) handleEditUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Move focus to previous element
 			m.editFocusIndex--
 			if m.editFocusIndex < 0 {
-				m.editFocusIndex = 2 // Wrap around to the last element
+				m.editFocusIndex = 3 // Wrap around to the last element
 			}
 			m.updateFocus()
 			return m, nil
@@ -143,6 +181,11 @@ func (m *model) handleEditUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	var cmd tea.Cmd
 
+	// Editing a field clears its error
+	if _, ok := msg.(tea.KeyMsg); ok {
+		delete(m.editErrors, m.editFocusIndex)
+	}
+
 	// Handle input based on focus
 	switch m.editFocusIndex {
 	case 0: // Name input
@@ -153,6 +196,8 @@ func (m *model) handleEditUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmd = listCmd
 	case 2: // Metadata Key input
 		m.editInputs[1], cmd = m.editInputs[1].Update(msg)
+	case 3: // Tags input
+		m.editInputs[2], cmd = m.editInputs[2].Update(msg)
 	}
 
 	return m, cmd
@@ -174,27 +219,31 @@ func (m *model) updateFocus() {
 		}
 	case 2: // Metadata Key input
 		m.editInputs[1].Focus()
+	case 3: // Tags input
+		m.editInputs[2].Focus()
 	}
 }
 
 // cancelEdit returns to the previous panel mode
 func (m *model) cancelEdit() {
 	if m.editMode == "server" {
-		m.panelMode = "servers"
+		m.panelMode = "plex-servers"
 	} else {
 		m.panelMode = "playback"
 	}
 	m.editInputs = nil
+	m.editErrors = nil
 }
 
 // savePlaybackEdit saves changes to playback config
 func (m *model) savePlaybackEdit() error {
-	if len(m.editInputs) < 2 {
+	if len(m.editInputs) < 3 {
 		return fmt.Errorf("missing input fields")
 	}
 
-	newName := m.editInputs[0].Value()
-	newMetadataKey := m.editInputs[1].Value()
+	newName := strings.TrimSpace(m.editInputs[0].Value())
+	newMetadataKey := strings.TrimSpace(m.editInputs[1].Value())
+	newTags := config.ParseTags(m.editInputs[2].Value())
 
 	// Get the selected type from the dropdown
 	var selectedType string
@@ -206,45 +255,89 @@ func (m *model) savePlaybackEdit() error {
 			selectedType = "album"
 		case "Playlist":
 			selectedType = "playlist"
+		case "Station":
+			selectedType = "station"
 		}
 	}
 
 	if newName == "" {
-		return fmt.Errorf("name cannot be empty")
+		return editFieldError{field: 0, msg: "name cannot be empty"}
 	}
 	if selectedType == "" {
-		return fmt.Errorf("please select a valid type")
+		return editFieldError{field: 1, msg: "please select a valid type"}
 	}
 	if newMetadataKey == "" {
-		return fmt.Errorf("metadata key cannot be empty")
+		return editFieldError{field: 2, msg: "metadata key cannot be empty"}
 	}
 
-	config.FavsManager.Add(config.FavoriteItem{
+	// Add would quietly rename an existing favorite, so adding one twice,
+	// or editing one into another, is refused
+	var old *config.FavoriteItem
+	if m.editIndex >= 0 && m.playbackConfig != nil && m.editIndex < len(m.playbackConfig.Items) {
+		old = &m.playbackConfig.Items[m.editIndex]
+	}
+	if old == nil || old.Type != selectedType || old.MetadataKey != newMetadataKey {
+		exists, err := favsManager.Has(selectedType, newMetadataKey)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return editFieldError{field: 2, msg: "already in favorites"}
+		}
+	}
+
+	// Editing a favorite's type or key would otherwise leave the old entry
+	// behind, since Add only updates an entry with the same type and key
+	if old != nil && (old.Type != selectedType || old.MetadataKey != newMetadataKey) {
+		if err := favsManager.Remove(old.Type, old.MetadataKey); err != nil {
+			return err
+		}
+	}
+
+	if err := favsManager.Add(config.FavoriteItem{
 		Name:        newName,
 		Type:        selectedType,
 		MetadataKey: newMetadataKey,
-	})
+	}); err != nil {
+		return err
+	}
 
-	allFavs, err := config.FavsManager.List()
-	if err != nil {
+	if err := m.reloadFavorites(); err != nil {
 		return err
 	}
-	// Update the list
-	var items []list.Item
-	for _, pb := range allFavs {
-		items = append(items, item{pb.Name, pb.Type, pb.MetadataKey})
+
+	// Add keeps a favorite's tags when given none, so they are set by ID,
+	// which also lets them be cleared
+	for _, fav := range m.playbackConfig.Items {
+		if fav.Type != selectedType || fav.MetadataKey != newMetadataKey {
+			continue
+		}
+		if err := favsManager.SetTags(fav.ID, newTags); err != nil {
+			return err
+		}
+		if err := m.reloadFavorites(); err != nil {
+			return err
+		}
+		break
 	}
-	m.playbackList.SetItems(items)
 
 	// Return to playback panel
 	m.panelMode = "playback"
 	m.editInputs = nil
+	m.editErrors = nil
 
 	return nil
 }
 
 // editPanelView renders the edit panel
 func (m model) editPanelView() string {
+	if m.editMode == "server" {
+		return m.serverEditView()
+	}
+	if m.editSearchActive {
+		return m.editSearchView()
+	}
+
 	var content string
 	action := "Edit"
 	if m.editIndex == -1 {
@@ -263,8 +356,9 @@ func (m model) editPanelView() string {
 		}
 		content += nameLabel + "\n"
 		if len(m.editInputs) > 0 {
-			content += m.editInputs[0].View() + "\n\n"
+			content += m.editInputs[0].View() + "\n"
 		}
+		content += m.editErrorView(0) + "\n"
 
 		// Type selection
 		typeLabel := "Type:"
@@ -274,7 +368,7 @@ func (m model) editPanelView() string {
 		content += typeLabel + "\n"
 
 		// Custom type selection display
-		typeOptions := []string{"Artist", "Album", "Playlist"}
+		typeOptions := []string{"Artist", "Album", "Playlist", "Station"}
 		typeContent := ""
 		for i, option := range typeOptions {
 			itemStyle := lipgloss.NewStyle().PaddingLeft(2)
@@ -282,7 +376,7 @@ func (m model) editPanelView() string {
 
 			// Always show selected item with blue highlight
 			if isSelected {
-				itemStyle = itemStyle.Background(lipgloss.Color("62")).Bold(true)
+				itemStyle = itemStyle.Background(theme.Highlight).Bold(true)
 			}
 
 			if i > 0 {
@@ -290,7 +384,8 @@ func (m model) editPanelView() string {
 			}
 			typeContent += itemStyle.Render(option)
 		}
-		content += typeContent + "\n\n"
+		content += typeContent + "\n"
+		content += m.editErrorView(1) + "\n"
 
 		// Metadata key input
 		metadataLabel := "Metadata Key:"
@@ -299,44 +394,57 @@ func (m model) editPanelView() string {
 		}
 		content += metadataLabel + "\n"
 		if len(m.editInputs) > 1 {
-			content += m.editInputs[1].View() + "\n"
+			keyInput := m.editInputs[1]
+			counter := lipgloss.NewStyle().Foreground(theme.Muted).Render(
+				fmt.Sprintf("%d/%d", len(keyInput.Value()), keyInput.CharLimit))
+			content += keyInput.View() + "\n" + counter + "\n"
 		}
+		content += m.editErrorView(2) + "\n"
+
+		// Tags input
+		tagsLabel := "Tags (comma separated):"
+		if m.editFocusIndex == 3 {
+			tagsLabel = "→ " + tagsLabel
+		}
+		content += tagsLabel + "\n"
+		if len(m.editInputs) > 2 {
+			content += m.editInputs[2].View() + "\n"
+		}
+		content += m.editErrorView(3)
 	}
 
-	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render
-	content += "\n\n" + helpStyle("Enter: Save • Esc: Cancel • ↑/↓: Navigate • Tab: Switch fields")
+	helpStyle := lipgloss.NewStyle().Foreground(theme.Muted).Render
+	content += "\n\n" + helpStyle("Enter: Save • Esc: Cancel • ↑/↓: Navigate • Tab: Switch fields • Ctrl+F: Find in library")
 
 	return content
 }
 
-// deletePlaybackItem removes a playback item from the config
-func (m *model) deletePlaybackItem(index int) error {
-	favToRemove := m.playbackList.Items()[index].(item)
-	if index >= 0 && m.playbackConfig != nil && index < len(m.playbackConfig.Items) {
-		m.playbackConfig.Items = append(m.playbackConfig.Items[:index], m.playbackConfig.Items[index+1:]...)
+// editErrorView renders the validation error of a field, or an empty line when it has none
+func (m model) editErrorView(field int) string {
+	msg, ok := m.editErrors[field]
+	if !ok {
+		return ""
 	}
+	return lipgloss.NewStyle().Foreground(theme.Negative).Render("  ✗ "+msg) + "\n"
+}
 
-	// Update the list
-	var items []list.Item
-	for _, pb := range m.playbackConfig.Items {
-		items = append(items, item{Name: pb.Name, Type: pb.Type, MetadataKey: pb.MetadataKey})
+// deletePlaybackItem removes the selected favorite from the database and
+// reloads the list so the deletion matches what is stored
+func (m *model) deletePlaybackItem() error {
+	favToRemove, ok := m.playbackList.SelectedItem().(item)
+	if !ok {
+		return nil
 	}
-	m.playbackList.SetItems(items)
-
 	if err := favsManager.Remove(favToRemove.Type, favToRemove.MetadataKey); err != nil {
 		return err
 	}
-
-	return nil
+	return m.reloadFavorites()
 }
 
+// savePlaybackItem adds a favorite to the database and reloads the list
 func (m *model) savePlaybackItem(name string, k string, t string) error {
-	fav := config.FavoriteItem{Name: name, Type: t, MetadataKey: k}
-	m.playbackConfig.Items = append(m.playbackConfig.Items, fav)
-	m.playbackList.SetItems(append(m.playbackList.Items(), item{Name: name, MetadataKey: k, Type: t}))
-
-	if err := favsManager.Add(fav); err != nil {
+	if err := favsManager.Add(config.FavoriteItem{Name: name, Type: t, MetadataKey: k}); err != nil {
 		return err
 	}
-	return nil
+	return m.reloadFavorites()
 }
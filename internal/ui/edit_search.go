@@ -0,0 +1,217 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// editSearchResultsMsg carries the library items matching a search from the edit panel
+type editSearchResultsMsg struct {
+	query   string
+	results []list.Item
+	err     error
+}
+
+// editSearchItem is a library item that can fill in the favorite being edited
+type editSearchItem struct {
+	title     string
+	subtitle  string // Artist of an album
+	ratingKey string
+}
+
+// Title returns the item title, with the artist for albums
+func (i editSearchItem) Title() string {
+	if i.subtitle == "" {
+		return i.title
+	}
+	return fmt.Sprintf("%s - %s", i.title, i.subtitle)
+}
+
+// Description returns the item description (empty for now)
+func (i editSearchItem) Description() string { return "" }
+
+// FilterValue implements list.Item
+func (i editSearchItem) FilterValue() string { return i.title }
+
+// editSearchType returns the favorite type selected in the edit panel.
+// Stations are seeded from an artist, so they search artists.
+func (m *model) editSearchType() string {
+	selected, _ := m.typeSelect.SelectedItem().(typeItem)
+	switch selected {
+	case "Album":
+		return "album"
+	case "Playlist":
+		return "playlist"
+	default:
+		return "artist"
+	}
+}
+
+// openEditSearch opens the library search of the edit panel, starting from the name typed so far
+func (m *model) openEditSearch() tea.Cmd {
+	if !m.plexAuthenticated || m.config == nil {
+		m.editErrors = map[int]string{2: "Plex authentication required to search (run with --auth)"}
+		return nil
+	}
+
+	input := textinput.New()
+	input.Placeholder = "Search " + m.editSearchType() + "s"
+	input.CharLimit = 100
+	input.Width = 40
+	if len(m.editInputs) > 0 {
+		input.SetValue(strings.TrimSpace(m.editInputs[0].Value()))
+	}
+
+	delegate := list.NewDefaultDelegate()
+	delegate.ShowDescription = false
+	results := list.New(nil, delegate, 50, 10)
+	results.SetShowTitle(false)
+	results.SetShowStatusBar(false)
+	results.SetShowHelp(false)
+	results.SetFilteringEnabled(false)
+
+	m.editSearchInput = input
+	m.editSearchList = results
+	m.editSearchQuery = ""
+	m.editSearchActive = true
+	m.editSearchStatus = ""
+	return m.editSearchInput.Focus()
+}
+
+// editSearchCmd searches the current library, or the playlists, for items whose title contains query
+func (m *model) editSearchCmd(query string) tea.Cmd {
+	serverAddr := m.config.ServerURL()
+	libraryID := m.config.PlexLibraryID
+	types := m.libraryTypes()
+	token := plexClient.GetPlexToken()
+	searchType := m.editSearchType()
+
+	return func() tea.Msg {
+		var results []list.Item
+		switch searchType {
+		case "album":
+			albums, err := plexClient.FetchFilteredAlbums(serverAddr, libraryID, types, "title", query, token)
+			if err != nil {
+				return editSearchResultsMsg{query: query, err: err}
+			}
+			for _, album := range albums {
+				results = append(results, editSearchItem{title: album.Title, subtitle: album.ParentTitle, ratingKey: album.RatingKey})
+			}
+
+		case "playlist":
+			// Playlists can't be filtered by the server, so match them here
+			playlists, err := plexClient.FetchPlaylists(serverAddr, token)
+			if err != nil {
+				return editSearchResultsMsg{query: query, err: err}
+			}
+			for _, playlist := range playlists {
+				if strings.Contains(strings.ToLower(playlist.Title), strings.ToLower(query)) {
+					results = append(results, editSearchItem{title: playlist.Title, ratingKey: playlist.RatingKey})
+				}
+			}
+
+		default:
+			artists, err := plexClient.FetchFilteredArtists(serverAddr, libraryID, types, "title", query, token)
+			if err != nil {
+				return editSearchResultsMsg{query: query, err: err}
+			}
+			for _, artist := range artists {
+				results = append(results, editSearchItem{title: artist.Title, ratingKey: artist.RatingKey})
+			}
+		}
+		return editSearchResultsMsg{query: query, results: results}
+	}
+}
+
+// handleEditSearchUpdate processes messages while the edit panel search is open.
+// Enter searches for the typed text, or picks the highlighted result once
+// the results for that text are shown.
+func (m *model) handleEditSearchUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.editSearchActive = false
+			return m, nil
+
+		case "enter":
+			query := strings.TrimSpace(m.editSearchInput.Value())
+			if query == m.editSearchQuery && len(m.editSearchList.Items()) > 0 {
+				if selected, ok := m.editSearchList.SelectedItem().(editSearchItem); ok {
+					m.pickEditSearchResult(selected)
+				}
+				return m, nil
+			}
+			if query == "" {
+				return m, nil
+			}
+			m.editSearchQuery = query
+			m.editSearchStatus = "Searching..."
+			return m, m.editSearchCmd(query)
+
+		case "up", "down", "pgup", "pgdown":
+			var cmd tea.Cmd
+			m.editSearchList, cmd = m.editSearchList.Update(msg)
+			return m, cmd
+		}
+
+		var cmd tea.Cmd
+		m.editSearchInput, cmd = m.editSearchInput.Update(msg)
+		return m, cmd
+
+	case editSearchResultsMsg:
+		// Ignore results for a query that has since been replaced
+		if msg.query != m.editSearchQuery {
+			return m, nil
+		}
+		if msg.err != nil {
+			if m.handleAuthError(msg.err) {
+				return m, nil
+			}
+			m.editSearchStatus = fmt.Sprintf("Search failed: %v", msg.err)
+			return m, nil
+		}
+		m.editSearchList.SetItems(msg.results)
+		m.editSearchList.ResetSelected()
+		if len(msg.results) == 0 {
+			m.editSearchStatus = "No matches"
+		} else {
+			m.editSearchStatus = fmt.Sprintf("%d matches - Enter to use the highlighted one", len(msg.results))
+		}
+	}
+
+	return m, nil
+}
+
+// pickEditSearchResult fills the name and metadata key of the favorite being edited
+func (m *model) pickEditSearchResult(result editSearchItem) {
+	if len(m.editInputs) > 1 {
+		m.editInputs[0].SetValue(result.title)
+		m.editInputs[1].SetValue(result.ratingKey)
+	}
+	delete(m.editErrors, 0)
+	delete(m.editErrors, 2)
+	m.editSearchActive = false
+}
+
+// editSearchView renders the library search of the edit panel
+func (m model) editSearchView() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Underline(true)
+	content := titleStyle.Render(fmt.Sprintf("Find %s", m.editSearchType())) + "\n\n"
+	content += m.editSearchInput.View() + "\n\n"
+	if len(m.editSearchList.Items()) > 0 {
+		content += m.editSearchList.View() + "\n"
+	}
+	if m.editSearchStatus != "" {
+		content += lipgloss.NewStyle().Foreground(theme.Info).Render(m.editSearchStatus) + "\n"
+	}
+
+	helpStyle := lipgloss.NewStyle().Foreground(theme.Muted).Render
+	content += "\n" + helpStyle("Enter: Search/Use result • ↑/↓: Choose • Esc: Back")
+	return content
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"io"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// favoriteIcons are the badges that tell favorites of each type apart
+var favoriteIcons = map[string]string{
+	"artist":   "🎤",
+	"album":    "💿",
+	"track":    "🎵",
+	"playlist": "📃",
+	"station":  "📻",
+	"genre":    "🎼",
+	"decade":   "📅",
+}
+
+// favoriteDelegate draws the favorites like the default delegate, with a
+// badge for the type of each favorite. With plain set only the type is
+// written out, for terminals that can't show emoji.
+type favoriteDelegate struct {
+	list.DefaultDelegate
+	plain bool
+}
+
+// newFavoriteDelegate creates the delegate of the favorites list
+func newFavoriteDelegate(plain bool) favoriteDelegate {
+	return favoriteDelegate{DefaultDelegate: list.NewDefaultDelegate(), plain: plain}
+}
+
+// Render implements list.ItemDelegate. The badge goes in the description
+// rather than before the name, where it would shift the filter's match
+// highlighting.
+func (d favoriteDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	if fav, ok := listItem.(item); ok && !d.plain {
+		listItem = badgedItem{fav}
+	}
+	d.DefaultDelegate.Render(w, m, index, listItem)
+}
+
+// badgedItem is a favorite whose description carries its type's badge
+type badgedItem struct {
+	item
+}
+
+// Description returns the favorite's type behind its badge, followed by
+// its tags
+func (i badgedItem) Description() string {
+	desc := i.Type
+	if icon, ok := favoriteIcons[i.Type]; ok {
+		desc = icon + " " + i.Type
+	}
+	return desc + tagsSuffix(i.Tags)
+}
+
+// tagsSuffix lists a favorite's tags for its description, e.g. " #workout #focus"
+func tagsSuffix(tags []string) string {
+	var suffix string
+	for _, tag := range tags {
+		suffix += " #" + tag
+	}
+	return suffix
+}
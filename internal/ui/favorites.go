@@ -2,9 +2,12 @@ package ui
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"plexamp-tui/internal/config"
 
+	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -13,10 +16,10 @@ import (
 // =====================
 
 func (m *model) triggerFavoriteRadioPlayback(item config.FavoriteItem) tea.Cmd {
-	log.Debug(fmt.Sprintf("Triggering radio playback for %s", item.Name))
+	log.Debug("Triggering radio playback for %s", item.Name)
 	if m.selected == "" {
 		return func() tea.Msg {
-			return artistPlaybackMsg{success: false, err: fmt.Errorf("no server selected")}
+			return artistPlaybackMsg{success: false, err: errNoPlayer}
 		}
 	}
 
@@ -28,14 +31,14 @@ func (m *model) triggerFavoriteRadioPlayback(item config.FavoriteItem) tea.Cmd {
 
 	m.lastCommand = fmt.Sprintf("Playing radio for %s", item.Name)
 
-	return func() tea.Msg { return m.playArtistRadioCmd(item.MetadataKey)() }
+	return func() tea.Msg { return m.playArtistRadioCmd(item.Name, item.MetadataKey)() }
 }
 
 func (m *model) triggerFavoritePlayback(item config.FavoriteItem) tea.Cmd {
-	log.Debug(fmt.Sprintf("Triggering playback for %s", item.Name))
+	log.Debug("Triggering playback for %s", item.Name)
 	if m.selected == "" {
 		return func() tea.Msg {
-			return artistPlaybackMsg{success: false, err: fmt.Errorf("no server selected")}
+			return artistPlaybackMsg{success: false, err: errNoPlayer}
 		}
 	}
 
@@ -48,42 +51,309 @@ func (m *model) triggerFavoritePlayback(item config.FavoriteItem) tea.Cmd {
 	m.lastCommand = fmt.Sprintf("Playing %s", item.Name)
 	switch item.Type {
 	case "artist":
-		log.Debug(fmt.Sprintf("Playing artist: %s", item.Name))
-		return func() tea.Msg { return m.playArtistCmd(item.MetadataKey)() }
+		log.Debug("Playing artist: %s", item.Name)
+		return func() tea.Msg { return m.playArtistCmd(item.Name, item.MetadataKey)() }
 	case "album":
-		log.Debug(fmt.Sprintf("Playing album: %s", item.Name))
-		return func() tea.Msg { return m.playAlbumCmd(item.MetadataKey)() }
+		log.Debug("Playing album: %s", item.Name)
+		return func() tea.Msg { return m.playAlbumCmd(item.Name, item.MetadataKey)() }
 	case "playlist":
-		log.Debug(fmt.Sprintf("Playing playlist: %s", item.Name))
-		return func() tea.Msg { return m.playPlaylistCmd(item.MetadataKey)() }
+		log.Debug("Playing playlist: %s", item.Name)
+		return func() tea.Msg { return m.playPlaylistCmd(item.Name, item.MetadataKey)() }
+	case "station":
+		log.Debug("Playing station: %s", item.Name)
+		return func() tea.Msg { return m.playArtistRadioCmd(item.Name, item.MetadataKey)() }
+	case "track":
+		log.Debug("Playing track: %s", item.Name)
+		return func() tea.Msg { return m.playTrackCmd(item.Name, item.MetadataKey)() }
+	case "genre", "decade":
+		log.Debug("Playing %s: %s", item.Type, item.Name)
+		filter, value, ok := parseFilterFavoriteKey(item.MetadataKey)
+		if !ok || filter != item.Type {
+			filter, value = item.Type, item.MetadataKey
+		}
+		return m.playGenreCmd(filter, item.Name, value)
 	default:
-		log.Debug(fmt.Sprintf("Unknown type: %s", item.Type))
+		log.Debug("Unknown type: %s", item.Type)
 		return func() tea.Msg {
 			return artistPlaybackMsg{success: false, err: fmt.Errorf("unknown type: %s", item.Type)}
 		}
 	}
 }
 
+// favoritesPlaybackMsg is sent once every favorite has been sent to the player as one queue
+type favoritesPlaybackMsg struct {
+	queued  int
+	skipped int // Playlists, stations, genres and decades, which can't share a queue
+	err     error
+}
+
+// playAllFavoritesCmd plays every artist, album and track favorite in a
+// single play queue, shuffled when shuffle is on. Only the favorites of the
+// type and tag being shown are played.
+func (m *model) playAllFavoritesCmd() tea.Cmd {
+	if m.selected == "" || m.config == nil {
+		m.status = noPlayerStatus()
+		return nil
+	}
+
+	var keys []string
+	skipped := 0
+	for _, fav := range m.playbackConfig.Items {
+		if !m.favoriteShown(fav) {
+			continue
+		}
+		switch fav.Type {
+		case "artist", "album", "track":
+			keys = append(keys, fav.MetadataKey)
+		default:
+			skipped++
+		}
+	}
+	if len(keys) == 0 {
+		m.status = "No artist, album or track favorites to play"
+		return nil
+	}
+
+	serverIP := m.selected
+	serverID := m.config.ServerID
+	shuffle := m.shuffle
+	m.lastCommand = "Playing all favorites"
+
+	return func() tea.Msg {
+		err := PlayMetadataList(serverIP, serverID, keys, shuffle)
+		return favoritesPlaybackMsg{queued: len(keys), skipped: skipped, err: err}
+	}
+}
+
+// handleFavoritesPlayback reports how many favorites were queued
+func (m *model) handleFavoritesPlayback(msg favoritesPlaybackMsg) {
+	if msg.err != nil {
+		m.lastCommand = "Playback Failed"
+		m.status = fmt.Sprintf("Playback error: %v", msg.err)
+		return
+	}
+	m.lastCommand = "Favorites Playback Started"
+	m.status = fmt.Sprintf("Queued %d favorites", msg.queued)
+	if msg.skipped > 0 {
+		m.status += fmt.Sprintf(", skipped %d playlists, stations and genres", msg.skipped)
+	}
+}
+
+// addRemoveFavorite adds an item to the favorites, or removes it when it is
+// already there. The database is checked rather than the loaded favorites,
+// and by type as well as key, since genres and decades can share keys with
+// other items.
 func (m *model) addRemoveFavorite(name string, k string, t string) (tea.Model, tea.Cmd) {
-	log.Debug(fmt.Sprintf("Toggling favorite for %s", name))
-	favSet := m.getCurrentFavSet()
-	if _, exists := favSet[k]; exists {
-		log.Debug(fmt.Sprintf("Removing favorite: %s", name))
-		// Delete selected playback item
-		index := m.playbackList.Index()
-		m.deletePlaybackItem(index)
+	log.Debug("Toggling favorite for %s", name)
+	name = strings.TrimSuffix(name, " ★")
+	exists, err := favsManager.Has(t, k)
+	if err != nil {
+		m.status = fmt.Sprintf("Error checking favorites: %v", err)
 		return m, nil
 	}
-	log.Debug(fmt.Sprintf("Adding favorite: %s", name))
-	m.savePlaybackItem(name, k, t)
+	if exists {
+		log.Debug("Removing favorite: %s", name)
+		// Remove by key: the favorites panel selection may be a different item
+		if err := favsManager.Remove(t, k); err != nil {
+			m.status = fmt.Sprintf("Error removing favorite: %v", err)
+			return m, nil
+		}
+		if err := m.reloadFavorites(); err != nil {
+			m.status = fmt.Sprintf("Error loading favorites: %v", err)
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Already in favorites - removed %s", name)
+		return m, nil
+	}
+	log.Debug("Adding favorite: %s", name)
+	if err := m.savePlaybackItem(name, k, t); err != nil {
+		m.status = fmt.Sprintf("Error adding favorite: %v", err)
+		return m, nil
+	}
+	m.status = fmt.Sprintf("Added %s to favorites", name)
 	return m, nil
 }
 
+// getCurrentFavSet returns the metadata keys of every favorite, including
+// the ones the favorites panel's type filter hides
 func (m *model) getCurrentFavSet() map[string]struct{} {
 	favSet := make(map[string]struct{})
-	for _, pItem := range m.playbackList.Items() {
-		pItem := pItem.(item)
-		favSet[pItem.GetMetadataKey()] = struct{}{}
+	if m.playbackConfig == nil {
+		return favSet
+	}
+	for _, fav := range m.playbackConfig.Items {
+		favSet[fav.MetadataKey] = struct{}{}
 	}
 	return favSet
 }
+
+// reloadFavorites refreshes the favorites list from the database
+func (m *model) reloadFavorites() error {
+	allFavs, err := favsManager.List()
+	if err != nil {
+		return err
+	}
+	m.playbackConfig.Items = allFavs
+	m.showFavorites()
+	return nil
+}
+
+// favoriteFilters are the favorite types t steps the favorites panel
+// through; the empty type shows every favorite
+var favoriteFilters = []struct {
+	favType string
+	label   string
+}{
+	{"", "All"},
+	{"artist", "Artists"},
+	{"album", "Albums"},
+	{"track", "Tracks"},
+	{"playlist", "Playlists"},
+	{"station", "Stations"},
+}
+
+// showFavorites fills the favorites panel with the favorites of the type
+// and tag being shown, leaving the loaded favorites untouched
+func (m *model) showFavorites() {
+	var items []list.Item
+	for _, pb := range m.playbackConfig.Items {
+		if m.favoriteShown(pb) {
+			items = append(items, item{Name: pb.Name, Type: pb.Type, MetadataKey: pb.MetadataKey, Tags: pb.Tags})
+		}
+	}
+	m.playbackList.SetItems(items)
+
+	m.playbackList.Title = "Favorites"
+	for _, filter := range favoriteFilters {
+		if filter.favType == m.favFilter && filter.favType != "" {
+			m.playbackList.Title = "Favorites: " + filter.label
+		}
+	}
+	if m.favTag != "" {
+		m.playbackList.Title += " #" + m.favTag
+	}
+}
+
+// favoriteShown reports whether a favorite has the type and tag being shown
+func (m *model) favoriteShown(fav config.FavoriteItem) bool {
+	if m.favFilter != "" && fav.Type != m.favFilter {
+		return false
+	}
+	return m.favTag == "" || fav.HasTag(m.favTag)
+}
+
+// favoriteTags returns the tags of the loaded favorites, sorted
+func (m *model) favoriteTags() []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, fav := range m.playbackConfig.Items {
+		for _, tag := range fav.Tags {
+			if !seen[strings.ToLower(tag)] {
+				seen[strings.ToLower(tag)] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return strings.ToLower(tags[i]) < strings.ToLower(tags[j]) })
+	return tags
+}
+
+// cycleFavoriteTag shows the favorites with the next tag, then all of them
+// again after the last tag
+func (m *model) cycleFavoriteTag() {
+	tags := m.favoriteTags()
+	if len(tags) == 0 {
+		m.favTag = ""
+		m.status = "No tagged favorites: press e to tag one"
+		return
+	}
+	next := ""
+	for i, tag := range tags {
+		if m.favTag == "" {
+			next = tags[0]
+			break
+		}
+		if strings.EqualFold(tag, m.favTag) && i+1 < len(tags) {
+			next = tags[i+1]
+		}
+	}
+	m.favTag = next
+	m.showFavorites()
+	m.playbackList.ResetSelected()
+	if next == "" {
+		m.lastCommand = "Showing favorites with any tag"
+	} else {
+		m.lastCommand = "Showing favorites tagged " + next
+	}
+}
+
+// cycleFavoriteFilter shows the favorites of the next type in favoriteFilters
+func (m *model) cycleFavoriteFilter() {
+	next := 0
+	for i, filter := range favoriteFilters {
+		if filter.favType == m.favFilter {
+			next = (i + 1) % len(favoriteFilters)
+		}
+	}
+	m.favFilter = favoriteFilters[next].favType
+	m.showFavorites()
+	m.playbackList.ResetSelected()
+	m.lastCommand = "Showing " + strings.ToLower(favoriteFilters[next].label) + " favorites"
+}
+
+// selectedFavoriteIndex returns the position in the loaded favorites of the
+// selected item, which differs from its list index while a filter is applied
+func (m *model) selectedFavoriteIndex() int {
+	selected, ok := m.playbackList.SelectedItem().(item)
+	if !ok || m.playbackConfig == nil {
+		return -1
+	}
+	for i, fav := range m.playbackConfig.Items {
+		if fav.Type == selected.Type && fav.MetadataKey == selected.MetadataKey {
+			return i
+		}
+	}
+	return -1
+}
+
+// moveFavorite moves the selected favorite up (negative delta) or down the list
+// and keeps it selected
+func (m *model) moveFavorite(delta int) {
+	if m.playbackList.FilterState() != list.Unfiltered || m.favFilter != "" || m.favTag != "" {
+		m.status = "Clear the filter before reordering favorites"
+		return
+	}
+
+	selected, ok := m.playbackList.SelectedItem().(item)
+	if !ok {
+		return
+	}
+	index := m.playbackList.Index()
+	target := index + delta
+	if target < 0 || target >= len(m.playbackList.Items()) {
+		return
+	}
+
+	// Look the item up in the database so items added this session have an ID
+	allFavs, err := favsManager.List()
+	if err != nil {
+		m.status = fmt.Sprintf("Error reordering favorites: %v", err)
+		return
+	}
+	for _, fav := range allFavs {
+		if fav.Type == selected.Type && fav.MetadataKey == selected.MetadataKey {
+			if err := favsManager.Reorder(fav.ID, target); err != nil {
+				m.status = fmt.Sprintf("Error reordering favorites: %v", err)
+				return
+			}
+			break
+		}
+	}
+
+	if err := m.reloadFavorites(); err != nil {
+		m.status = fmt.Sprintf("Error loading favorites: %v", err)
+		return
+	}
+	m.playbackList.Select(target)
+	m.lastCommand = fmt.Sprintf("Moved %s", selected.Name)
+}
@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"path/filepath"
+	"testing"
+
+	"plexamp-tui/internal/config"
+	"plexamp-tui/internal/database"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// useTestFavorites points the package-level favorites manager at a new
+// database in a temporary directory, holding the given favorites, and returns
+// a model showing them along with the database's path
+func useTestFavorites(t *testing.T, favorites ...config.FavoriteItem) (*model, string) {
+	t.Helper()
+	useTestConfig(t)
+
+	path := filepath.Join(t.TempDir(), "favorites.db")
+	db, err := database.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if favsManager, err = config.NewFavoritesManager(db); err != nil {
+		t.Fatal(err)
+	}
+	for _, fav := range favorites {
+		if err := favsManager.Add(fav); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := &model{
+		playbackConfig: &config.Favorites{},
+		playbackList:   list.New(nil, list.NewDefaultDelegate(), 0, 0),
+	}
+	if err := m.reloadFavorites(); err != nil {
+		t.Fatal(err)
+	}
+	return m, path
+}
+
+// storedFavoriteNames lists the names of the favorites in the database
+func storedFavoriteNames(t *testing.T, manager *config.FavoritesManager) []string {
+	t.Helper()
+	favorites, err := manager.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, fav := range favorites {
+		names = append(names, fav.Name)
+	}
+	return names
+}
+
+func TestAddRemoveFavoriteRemovesToggledItem(t *testing.T) {
+	m, _ := useTestFavorites(t,
+		config.FavoriteItem{Name: "Air", Type: "artist", MetadataKey: "1"},
+		config.FavoriteItem{Name: "Björk", Type: "artist", MetadataKey: "2"},
+	)
+	// Another favorite is highlighted in the favorites panel
+	m.playbackList.Select(1)
+
+	m.addRemoveFavorite("Air ★", "1", "artist")
+
+	names := storedFavoriteNames(t, favsManager)
+	if len(names) != 1 || names[0] != "Björk" {
+		t.Errorf("favorites after removing Air: %v, want [Björk]", names)
+	}
+	if len(m.playbackList.Items()) != 1 {
+		t.Errorf("favorites panel shows %d items, want 1", len(m.playbackList.Items()))
+	}
+}
+
+func TestAddRemoveFavoriteAdds(t *testing.T) {
+	m, _ := useTestFavorites(t)
+
+	m.addRemoveFavorite("Air", "1", "artist")
+
+	names := storedFavoriteNames(t, favsManager)
+	if len(names) != 1 || names[0] != "Air" {
+		t.Errorf("favorites after adding Air: %v, want [Air]", names)
+	}
+}
+
+func TestDeletedFavoriteStaysDeleted(t *testing.T) {
+	m, path := useTestFavorites(t,
+		config.FavoriteItem{Name: "Air", Type: "artist", MetadataKey: "1"},
+		config.FavoriteItem{Name: "Björk", Type: "artist", MetadataKey: "2"},
+	)
+	m.playbackList.Select(0)
+
+	if err := m.deletePlaybackItem(); err != nil {
+		t.Fatalf("deletePlaybackItem: %v", err)
+	}
+
+	// Reopen the database, as the next launch would
+	db, err := database.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	reopened, err := config.NewFavoritesManager(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := storedFavoriteNames(t, reopened)
+	if len(names) != 1 || names[0] != "Björk" {
+		t.Errorf("favorites after reopening: %v, want [Björk]", names)
+	}
+}
+
+func TestAddThenEditFavorite(t *testing.T) {
+	m, _ := useTestFavorites(t)
+
+	if err := m.savePlaybackItem("Air", "1", "artist"); err != nil {
+		t.Fatalf("savePlaybackItem: %v", err)
+	}
+
+	m.initEditMode("playback", 0)
+	m.editInputs[0].SetValue("Air (French band)")
+	m.editInputs[2].SetValue("chill, electronic")
+	if err := m.savePlaybackEdit(); err != nil {
+		t.Fatalf("savePlaybackEdit: %v", err)
+	}
+
+	favorites, err := favsManager.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(favorites) != 1 {
+		t.Fatalf("got %d favorites after editing, want 1", len(favorites))
+	}
+	fav := favorites[0]
+	if fav.Name != "Air (French band)" || fav.Type != "artist" || fav.MetadataKey != "1" {
+		t.Errorf("edited favorite is %+v, want Air (French band), artist 1", fav)
+	}
+	if !fav.HasTag("chill") || !fav.HasTag("electronic") {
+		t.Errorf("edited favorite has tags %v, want chill and electronic", fav.Tags)
+	}
+	if m.panelMode != "playback" {
+		t.Errorf("panel after saving is %q, want playback", m.panelMode)
+	}
+}
+
+func TestEditFavoriteKey(t *testing.T) {
+	m, _ := useTestFavorites(t, config.FavoriteItem{Name: "Air", Type: "artist", MetadataKey: "1"})
+
+	m.initEditMode("playback", 0)
+	m.editInputs[1].SetValue("2")
+	if err := m.savePlaybackEdit(); err != nil {
+		t.Fatalf("savePlaybackEdit: %v", err)
+	}
+
+	favorites, err := favsManager.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(favorites) != 1 || favorites[0].MetadataKey != "2" {
+		t.Errorf("favorites after changing the key: %+v, want only key 2", favorites)
+	}
+}
@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Panes that can hold keyboard focus
+const (
+	focusList     = "" // The left-hand list, focused by default
+	focusPlayback = "playback"
+)
+
+// activeList returns the list shown in the left panel for the current panel mode,
+// or nil when the panel doesn't show a list
+func (m *model) activeList() *list.Model {
+	switch m.panelMode {
+	case "playback":
+		return &m.playbackList
+	case "plex-artists":
+		return &m.artistList
+	case "plex-albums":
+		return &m.albumList
+	case "plex-tracks":
+		return &m.trackList
+	case "plex-playlists":
+		return &m.playlistList
+	case "plex-genres":
+		return &m.genreList
+	case "plex-servers":
+		return &m.serverList
+	case "plex-players":
+		return &m.playerList
+	case "history":
+		return &m.historyList
+	case "plex-profiles":
+		return &m.profileList
+	case "queue":
+		return &m.queueList
+	case "libraries":
+		return &m.libraryList
+	default:
+		return nil
+	}
+}
+
+// isFiltering reports whether the active list is taking filter input
+func (m *model) isFiltering() bool {
+	l := m.activeList()
+	return l != nil && l.FilterState() == list.Filtering
+}
+
+// toggleFocus moves keyboard focus between the left list and the Now Playing pane
+func (m *model) toggleFocus() {
+	if m.focusedPane == focusPlayback || !m.layout.status {
+		m.focusedPane = focusList
+	} else {
+		m.focusedPane = focusPlayback
+	}
+}
+
+// handlePlaybackPaneKey handles keys while the Now Playing pane has focus, where the
+// arrows and digits control playback instead of navigating the list.
+// Returns false for keys the pane doesn't use so they reach the normal handlers.
+func (m *model) handlePlaybackPaneKey(key string) (tea.Cmd, bool) {
+	switch key {
+	case "esc":
+		m.focusedPane = focusList
+		return nil, true
+
+	case "left":
+		return m.seek(-10), true
+
+	case "right":
+		return m.seek(10), true
+
+	case "up":
+		return m.adjustVolume(m.volumeStep), true
+
+	case "down":
+		return m.adjustVolume(-m.volumeStep), true
+
+	case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		// Jump to 0-90% of the track
+		return m.seekPercent(int(key[0]-'0') * 10), true
+
+	case "enter", "home":
+		return m.restartTrack(), true
+
+	default:
+		return nil, false
+	}
+}
+
+// paneBorder returns the panel border style, highlighted when the pane has focus
+func (m model) paneBorder(pane string) lipgloss.Style {
+	color := theme.Muted
+	if m.focusedPane == pane {
+		color = theme.Title
+	}
+	return lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(color).Padding(0, 1)
+}
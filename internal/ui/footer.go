@@ -4,31 +4,47 @@ import (
 	"fmt"
 	"strings"
 
+	"plexamp-tui/internal/config"
+
 	"github.com/charmbracelet/lipgloss"
 )
 
 // footerView renders the application footer
 func (m model) footerView() string {
-	header := lipgloss.NewStyle().Foreground(lipgloss.Color("#ffaa00"))
-	value := lipgloss.NewStyle().Foreground(lipgloss.Color("#00ffcc")).Bold(true)
-	info := lipgloss.NewStyle().Foreground(lipgloss.Color("#8888ff"))
+	header := lipgloss.NewStyle().Foreground(theme.Accent)
+	value := lipgloss.NewStyle().Foreground(theme.Value).Bold(true)
+	info := lipgloss.NewStyle().Foreground(theme.Info)
 	footerStyle := lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
 		BorderTop(true).
-		BorderForeground(lipgloss.Color("#00ffff")).
+		BorderForeground(theme.Title).
 		Padding(0, 1)
 
 	var shuffleValue string
 	if m.shuffle {
-		shuffleValue = lipgloss.NewStyle().Foreground(lipgloss.Color("#00ff00")).Bold(true).Render("ON")
+		shuffleValue = lipgloss.NewStyle().Foreground(theme.Positive).Bold(true).Render("ON")
 	} else {
-		shuffleValue = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5555")).Bold(true).Render("OFF")
+		shuffleValue = lipgloss.NewStyle().Foreground(theme.Negative).Bold(true).Render("OFF")
+	}
+	repeatValue := lipgloss.NewStyle().Foreground(theme.Negative).Bold(true).Render(repeatLabel(m.repeat))
+	if m.repeat != 0 {
+		repeatValue = lipgloss.NewStyle().Foreground(theme.Positive).Bold(true).Render(repeatLabel(m.repeat))
+	}
+	crossfadeValue := lipgloss.NewStyle().Foreground(theme.Negative).Bold(true).Render(crossfadeLabel(m.crossfade))
+	if m.crossfade > 0 {
+		crossfadeValue = lipgloss.NewStyle().Foreground(theme.Positive).Bold(true).Render(crossfadeLabel(m.crossfade))
 	}
 	// --- Left side (your existing info)
 	left := ""
-	left += fmt.Sprintf("%s %s: %s \n", header.Render("Shuffle"), info.Render("(h)"), shuffleValue)
+	left += fmt.Sprintf("%s%s: %s | ", header.Render("Shuffle"), info.Render(keyHint(config.ActionShuffle)), shuffleValue)
+	left += fmt.Sprintf("%s%s: %s | ", header.Render("Repeat"), info.Render(keyHint(config.ActionRepeat)), repeatValue)
+	left += fmt.Sprintf("%s%s: %s ", header.Render("Crossfade"), info.Render(keyHint(config.ActionCrossfade)), crossfadeValue)
+	if !m.sleepDeadline.IsZero() {
+		left += fmt.Sprintf("| %s%s: %s ", header.Render("Sleep"), info.Render(keyHint(config.ActionSleepTimer)), value.Render(formatTime(int(m.sleepRemaining().Milliseconds()))))
+	}
+	left += "\n"
 	if len(m.config.PlexLibraries) > 0 {
-		left += fmt.Sprintf("%s %s: ", header.Render("Library"), info.Render("(Tab)"))
+		left += fmt.Sprintf("%s%s: ", header.Render("Library"), info.Render(keyHint(config.ActionCycleLibrary)))
 		for _, library := range m.config.PlexLibraries {
 			if library.Key == m.config.PlexLibraryID {
 				left += fmt.Sprintf("%s | ", value.Render(library.Title))
@@ -40,8 +56,8 @@ func (m model) footerView() string {
 		left += "\n"
 	}
 
-	left += fmt.Sprintf("%s %s: %s | ", header.Render("Server"), info.Render("(6)"), value.Render(m.config.PlexServerName))
-	left += fmt.Sprintf("%s %s: %s", header.Render("Player"), info.Render("(7)"), value.Render(m.config.SelectedPlayerName))
+	left += fmt.Sprintf("%s%s: %s | ", header.Render("Server"), info.Render(keyHint(config.ActionServers)), value.Render(m.config.PlexServerName))
+	left += fmt.Sprintf("%s%s: %s %s", header.Render("Player"), info.Render(keyHint(config.ActionPlayers)), m.playerHealthDot(), value.Render(m.config.SelectedPlayerName))
 
 	// --- Right side (new)
 	// Example: replace with whatever info you want (track, status, etc.)
@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"plexamp-tui/internal/config"
+)
+
+func TestKeyHintsFollowKeymap(t *testing.T) {
+	cfg := useTestConfig(t)
+	saved := globalKeys
+	t.Cleanup(func() { globalKeys = saved })
+
+	globalKeys = config.DefaultKeyMap()
+	globalKeys[config.ActionPlayPause] = []string{"ctrl+space"}
+	globalKeys[config.ActionShuffle] = []string{"H"}
+	globalKeys[config.ActionPlayers] = nil
+
+	m := model{config: cfg, width: 200, plexAuthenticated: true}
+
+	controls := m.appControlsView()
+	if !strings.Contains(controls, "ctrl+space Play/Pause") {
+		t.Errorf("controls don't show the rebound play/pause key:\n%s", controls)
+	}
+	if strings.Contains(controls, "p Play/Pause") {
+		t.Errorf("controls still show the default play/pause keys:\n%s", controls)
+	}
+
+	footer := m.footerView()
+	if !strings.Contains(footer, "(H)") || strings.Contains(footer, "(h)") {
+		t.Errorf("footer doesn't show the rebound shuffle key:\n%s", footer)
+	}
+	if strings.Contains(footer, "(7)") {
+		t.Errorf("footer shows a key for the unbound player list:\n%s", footer)
+	}
+}
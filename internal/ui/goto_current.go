@@ -0,0 +1,48 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// currentItems are the artist and album of the playing track, as reported by
+// the timeline. The keys are empty for tracks that aren't from the library.
+type currentItems struct {
+	trackKey  string
+	title     string
+	artist    string
+	artistKey string
+	album     string
+	albumKey  string
+}
+
+// goToCurrentAlbum opens the track list of the playing track's album
+func (m *model) goToCurrentAlbum() tea.Cmd {
+	if !m.canGoToCurrent(m.current.albumKey) {
+		return nil
+	}
+	m.initTrackBrowse(albumItem{title: m.current.album, artist: m.current.artist, ratingKey: m.current.albumKey})
+	return m.fetchTracksCmd()
+}
+
+// goToCurrentArtist opens the albums of the playing track's artist
+func (m *model) goToCurrentArtist() tea.Cmd {
+	if !m.canGoToCurrent(m.current.artistKey) {
+		return nil
+	}
+	m.initArtistAlbumBrowse(artistItem{title: m.current.artist, ratingKey: m.current.artistKey})
+	return m.fetchAlbumsCmd()
+}
+
+// canGoToCurrent reports whether a library item of the playing track can be
+// opened, explaining in the status line when it can't
+func (m *model) canGoToCurrent(ratingKey string) bool {
+	if !m.plexAuthenticated || m.config == nil {
+		m.status = "Plex authentication required (run with --auth)"
+		return false
+	}
+	if ratingKey == "" {
+		m.status = "Nothing from the library is playing"
+		return false
+	}
+	return true
+}
@@ -0,0 +1,212 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"plexamp-tui/internal/config"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpEntry describes a global action, or a fixed key when keys is set
+type helpEntry struct {
+	action      string
+	keys        string // Fixed keys that aren't in the keymap
+	description string
+}
+
+// helpSections lists everything the help overlay shows apart from the keys
+// of the open panel. The keys of global actions come from the keymap, so
+// rebinding an action is reflected here.
+var helpSections = []struct {
+	title   string
+	entries []helpEntry
+}{
+	{"Playback", []helpEntry{
+		{action: config.ActionPlayPause, description: "Play/Pause"},
+		{action: config.ActionStop, description: "Stop"},
+		{action: config.ActionNext, description: "Next track"},
+		{action: config.ActionPrevious, description: "Previous track"},
+		{action: config.ActionSeekBack, description: "Seek back 1m"},
+		{action: config.ActionSeekForward, description: "Seek forward 1m"},
+		{keys: "alt+0-9", description: "Jump to 0-90%"},
+		{action: config.ActionShuffle, description: "Toggle shuffle"},
+		{action: config.ActionRepeat, description: "Cycle repeat"},
+		{action: config.ActionCrossfade, description: "Cycle crossfade"},
+		{action: config.ActionSleepTimer, description: "Sleep timer"},
+		{action: config.ActionRandomAlbum, description: "Random album"},
+		{action: config.ActionSpeedUp, description: "Faster"},
+		{action: config.ActionSpeedDown, description: "Slower"},
+		{action: config.ActionSpeedReset, description: "Normal speed"},
+	}},
+	{"Volume", []helpEntry{
+		{action: config.ActionVolumeUp, description: "Volume up"},
+		{action: config.ActionVolumeDown, description: "Volume down"},
+		{action: config.ActionVolumeUpBig, description: "Volume up 10%"},
+		{action: config.ActionVolumeDownBig, description: "Volume down 10%"},
+		{action: config.ActionSetVolume, description: "Set volume"},
+		{action: config.ActionMute, description: "Mute/Unmute"},
+	}},
+	{"Panels", []helpEntry{
+		{action: config.ActionArtists, description: "Artists"},
+		{action: config.ActionAlbums, description: "Albums"},
+		{action: config.ActionPlaylists, description: "Playlists"},
+		{action: config.ActionHistory, description: "Recently played"},
+		{action: config.ActionProfiles, description: "Profiles"},
+		{action: config.ActionServers, description: "Servers"},
+		{action: config.ActionPlayers, description: "Players"},
+		{action: config.ActionGenres, description: "Genres"},
+		{action: config.ActionLibraries, description: "Libraries"},
+		{action: config.ActionQueue, description: "Play queue"},
+		{action: config.ActionLyrics, description: "Lyrics"},
+		{action: config.ActionGoToAlbum, description: "Playing album"},
+		{action: config.ActionGoToArtist, description: "Playing artist"},
+	}},
+	{"Navigation", []helpEntry{
+		{keys: "↑/↓", description: "Move"},
+		{keys: "enter", description: "Select/Play"},
+		{keys: "tab", description: "Focus list/Now Playing"},
+		{keys: "/", description: "Filter"},
+		{keys: jumpKey + " <letter>", description: "Jump to letter"},
+		{keys: "q/esc", description: "Back"},
+		{action: config.ActionCycleLibrary, description: "Next library"},
+		{action: config.ActionRefresh, description: "Refresh panel"},
+		{action: config.ActionCycleTheme, description: "Next theme"},
+		{action: config.ActionPanelLayout, description: "Next panel layout"},
+		{action: config.ActionCompact, description: "Compact view"},
+		{action: config.ActionCopyKey, description: "Copy rating key"},
+		{action: config.ActionPollInterval, description: "Next poll interval"},
+		{action: config.ActionHelp, description: "This help"},
+		{action: config.ActionQuit, description: "Quit"},
+		{keys: "ctrl+c", description: "Quit now"},
+	}},
+}
+
+// boundKeys returns the entry's fixed keys, or else the keys its action is
+// bound to
+func (e helpEntry) boundKeys() string {
+	if e.keys != "" {
+		return e.keys
+	}
+	return boundKeys(e.action)
+}
+
+// handleHelpKey dismisses the help overlay on ?, esc or q; other keys are ignored
+func (m *model) handleHelpKey(key string) {
+	if key == "esc" || key == "q" || keyBindings[key] == config.ActionHelp {
+		m.helpVisible = false
+	}
+}
+
+// helpView renders the keybinding reference, its sections laid out in as
+// many columns as fit the terminal
+func (m model) helpView() string {
+	var blocks []string
+	for _, section := range helpSections {
+		var rows [][2]string
+		for _, entry := range section.entries {
+			if keys := entry.boundKeys(); keys != "" {
+				rows = append(rows, [2]string{keys, entry.description})
+			}
+		}
+		blocks = append(blocks, m.helpBlock(section.title, rows))
+	}
+	if panelRows := m.panelHelpRows(); len(panelRows) > 0 {
+		blocks = append(blocks, m.helpBlock("This Panel", panelRows))
+	}
+
+	width := max(m.width-8, 1)
+	var lines []string
+	var row []string
+	rowWidth := 0
+	for _, block := range blocks {
+		blockWidth := lipgloss.Width(block) + 2
+		if len(row) > 0 && rowWidth+blockWidth > width {
+			lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+			row, rowWidth = nil, 0
+		}
+		row = append(row, lipgloss.NewStyle().PaddingRight(2).Render(block))
+		rowWidth += blockWidth
+	}
+	if len(row) > 0 {
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+	}
+
+	heading := lipgloss.NewStyle().Bold(true).Foreground(theme.Accent).Render("Keys")
+	footer := lipgloss.NewStyle().Foreground(theme.Muted).Render(fmt.Sprintf("Press %s or esc to close", boundKeys(config.ActionHelp)))
+	return heading + "\n\n" + strings.Join(lines, "\n\n") + "\n\n" + footer
+}
+
+// helpBlock renders one section of the help overlay
+func (m model) helpBlock(title string, rows [][2]string) string {
+	keyWidth := 0
+	for _, row := range rows {
+		keyWidth = max(keyWidth, lipgloss.Width(row[0]))
+	}
+	keyStyle := lipgloss.NewStyle().Foreground(theme.Value).Width(keyWidth + 2)
+	descStyle := lipgloss.NewStyle().Foreground(theme.Label)
+
+	lines := []string{lipgloss.NewStyle().Bold(true).Foreground(theme.Title).Render(title)}
+	for _, row := range rows {
+		lines = append(lines, keyStyle.Render(row[0])+descStyle.Render(row[1]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// panelHelpRows lists the keys of the open panel's list, including its own
+// additions such as favorites and sorting, skipping the ones the global
+// sections already cover
+func (m *model) panelHelpRows() [][2]string {
+	l := m.activeList()
+	if l == nil {
+		return nil
+	}
+	covered := map[string]bool{"up": true, "down": true, "q": true, "esc": true, "/": true, "?": true}
+	var rows [][2]string
+	for _, column := range l.FullHelp() {
+		for _, binding := range column {
+			if !binding.Enabled() || isCovered(binding, covered) {
+				continue
+			}
+			help := binding.Help()
+			rows = append(rows, [2]string{help.Key, help.Desc})
+			for _, k := range binding.Keys() {
+				covered[k] = true
+			}
+		}
+	}
+	return rows
+}
+
+// isCovered reports whether every key of a binding is already described
+func isCovered(binding key.Binding, covered map[string]bool) bool {
+	for _, k := range binding.Keys() {
+		if !covered[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// boundKeys lists the keys bound to a global action, as shown to the user
+func boundKeys(action string) string {
+	var keys []string
+	for _, k := range globalKeys[action] {
+		if k == " " {
+			k = "space"
+		}
+		keys = append(keys, k)
+	}
+	return strings.Join(keys, "/")
+}
+
+// keyHint renders the keys of a global action in parentheses after a footer
+// label, or nothing when the action is unbound
+func keyHint(action string) string {
+	if keys := boundKeys(action); keys != "" {
+		return " (" + keys + ")"
+	}
+	return ""
+}
@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"fmt"
+
+	"plexamp-tui/internal/config"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// historyLimit is the number of recent plays shown in the history panel
+const historyLimit = 50
+
+// historyFetchedMsg is a message containing the recent play history
+type historyFetchedMsg struct {
+	items []config.HistoryItem
+	err   error
+}
+
+// historyItem represents a play history entry in the list
+type historyItem struct {
+	entry config.HistoryItem
+}
+
+// Title returns the name of the played item
+func (i historyItem) Title() string { return i.entry.Name }
+
+// Description returns the item type and when it was played
+func (i historyItem) Description() string {
+	if i.entry.PlayedAt.IsZero() {
+		return i.entry.Type
+	}
+	return fmt.Sprintf("%s • %s", i.entry.Type, i.entry.PlayedAt.Local().Format("Jan 2 15:04"))
+}
+
+// FilterValue implements list.Item
+func (i historyItem) FilterValue() string { return i.entry.Name }
+
+// fetchHistoryCmd loads the most recent plays from the database
+func (m *model) fetchHistoryCmd() tea.Cmd {
+	if historyManager == nil {
+		return func() tea.Msg {
+			return historyFetchedMsg{err: fmt.Errorf("play history is not available")}
+		}
+	}
+
+	return tea.Batch(m.startLoading("history"), func() tea.Msg {
+		items, err := historyManager.Recent(historyLimit)
+		return historyFetchedMsg{items: items, err: err}
+	})
+}
+
+// recordPlayback stores a successfully played item in the play history
+func (m *model) recordPlayback(played config.HistoryItem) {
+	if historyManager == nil || played.MetadataKey == "" {
+		return
+	}
+	if err := historyManager.Record(played); err != nil {
+		log.Error("Failed to record play history: %v", err)
+	}
+}
+
+// initHistoryBrowse creates a new play history browser
+func (m *model) initHistoryBrowse() {
+	m.panelMode = "history"
+	m.status = "Loading history..."
+
+	m.historyList = list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	m.historyList.Title = "Recently Played"
+	m.historyList.SetShowFilter(true)
+	m.historyList.SetFilteringEnabled(true)
+	m.historyList.Styles.Title = titleStyle
+	m.historyList.Styles.PaginationStyle = paginationStyle
+	m.historyList.Styles.HelpStyle = helpStyle
+	m.historyList.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(
+				key.WithKeys("enter"),
+				key.WithHelp("enter", "Play Again"),
+			),
+			key.NewBinding(
+				key.WithKeys("R"),
+				key.WithHelp("R", "Refresh History"),
+			),
+		}
+	}
+	if m.width > 0 && m.height > 0 {
+		m.sizeList(&m.historyList)
+	}
+}
+
+func (m *model) openHistoryBrowser() (tea.Cmd, bool) {
+	m.initHistoryBrowse()
+	return m.fetchHistoryCmd(), true
+}
+
+func (m *model) handleHistoryBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// If we're in filtering mode, let the list handle the input
+	if m.historyList.FilterState() == list.Filtering {
+		var cmd tea.Cmd
+		m.historyList, cmd = m.historyList.Update(msg)
+		return m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		key := msg.String()
+
+		switch key {
+		case "esc", "q":
+			// Return to playback panel
+			m.panelMode = "playback"
+			m.status = ""
+			return m, nil
+
+		case "enter":
+			// Replay the selected entry
+			if selected, ok := m.historyList.SelectedItem().(historyItem); ok && selected.entry.MetadataKey != "" {
+				return m, m.triggerFavoritePlayback(config.FavoriteItem{
+					Name:        selected.entry.Name,
+					Type:        selected.entry.Type,
+					MetadataKey: selected.entry.MetadataKey,
+				})
+			}
+			return m, nil
+
+		case "R":
+			m.status = "Refreshing history..."
+			return m, m.fetchHistoryCmd()
+
+		default:
+
+			// Otherwise try the common controls
+			if cmd, handled := m.handleControl(key); handled {
+				return m, cmd
+			}
+		}
+
+	case historyFetchedMsg:
+		if msg.err != nil {
+			errMsg := fmt.Sprintf("Error loading history: %v", msg.err)
+			m.status = errMsg
+			log.Debug("%s", errMsg)
+			return m, nil
+		}
+
+		var items []list.Item
+		for _, entry := range msg.items {
+			items = append(items, historyItem{entry: entry})
+		}
+		m.historyList.SetItems(items)
+		m.historyList.ResetSelected()
+		m.restoreSelection(&m.historyList)
+		m.status = fmt.Sprintf("Loaded %d recent plays", len(msg.items))
+		return m, nil
+	}
+
+	// Update the history list and get the command
+	var listCmd tea.Cmd
+	m.historyList, listCmd = m.historyList.Update(msg)
+	return m, listCmd
+}
@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"fmt"
+
+	"plexamp-tui/internal/config"
+	"plexamp-tui/internal/httpapi"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// apiCommandMsg carries a playback command from the HTTP control API
+type apiCommandMsg httpapi.Command
+
+// startHTTPAPI starts the HTTP control API when it is enabled. Like the
+// desktop integration it is optional, so failing to start it is only logged.
+func startHTTPAPI(c *config.Config) *httpapi.Server {
+	if c == nil || !c.HTTPAPIEnabled {
+		return nil
+	}
+	addr := c.HTTPAPIAddress()
+	server, err := httpapi.Start(addr)
+	if err != nil {
+		log.Warn("HTTP control API not started: %v", err)
+		return nil
+	}
+	log.Info("HTTP control API listening on %s", addr)
+	return server
+}
+
+// waitForAPICmd waits for the next command from the HTTP control API
+func waitForAPICmd() tea.Cmd {
+	if apiServer == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		cmd, ok := <-apiServer.Commands()
+		if !ok {
+			return nil
+		}
+		return apiCommandMsg(cmd)
+	}
+}
+
+// handleAPICommand carries out a command from the HTTP control API with the
+// same controls as the keys
+func (m *model) handleAPICommand(msg apiCommandMsg) tea.Cmd {
+	switch msg.Action {
+	case httpapi.ActionPlayPause:
+		return m.togglePlayback()
+	case httpapi.ActionPlay:
+		if !m.isPlaying {
+			return m.togglePlayback()
+		}
+	case httpapi.ActionPause:
+		if m.isPlaying {
+			return m.togglePlayback()
+		}
+	case httpapi.ActionStop:
+		return m.stopPlayback()
+	case httpapi.ActionNext:
+		return m.nextTrack()
+	case httpapi.ActionPrevious:
+		return m.previousTrack()
+	case httpapi.ActionVolume:
+		cmd := m.setVolume(msg.Volume)
+		if cmd != nil {
+			m.forgetMute()
+			m.lastCommand = fmt.Sprintf("Volume %d%%", msg.Volume)
+		}
+		return cmd
+	}
+	return nil
+}
+
+// publishAPIState returns a command updating the state the HTTP control API
+// reports with a timeline update
+func (m *model) publishAPIState(msg trackMsgWithState) tea.Cmd {
+	if apiServer == nil {
+		return nil
+	}
+
+	state := httpapi.State{
+		State:      "paused",
+		Title:      msg.Title,
+		Artist:     msg.Artist,
+		Album:      msg.Album,
+		PositionMs: msg.Position,
+		DurationMs: msg.Duration,
+		Volume:     msg.Volume,
+	}
+	switch {
+	case msg.Stopped || msg.Title == "":
+		state = httpapi.State{State: "stopped", Volume: msg.Volume}
+	case msg.IsPlaying:
+		state.State = "playing"
+	}
+	if m.config != nil {
+		state.Player = m.config.SelectedPlayerName
+	}
+	return func() tea.Msg {
+		apiServer.SetState(state)
+		return nil
+	}
+}
+
+// closeHTTPAPI stops the HTTP control API on quit
+func closeHTTPAPI() {
+	if apiServer == nil {
+		return
+	}
+	apiServer.Close()
+}
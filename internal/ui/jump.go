@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// jumpKey starts a jump to letter. Only the one letter typed after it is
+// taken: the selection moves to the first item starting with that letter,
+// pressing the same letter again moves to the next one, and any other key ends
+// the jump. Single letters are mostly taken by the panel and player controls,
+// hence the prefix.
+const jumpKey = "'"
+
+// handleJump moves the selection of l for a jump to letter. Pressing the same
+// letter again advances to the next item starting with it; any other key ends
+// the jump and is handled as usual. more reports whether l has items left to
+// load: a letter that isn't among the loaded ones then leaves jumpPending set
+// for the caller to load the next page and call resumeJump. It reports whether
+// the key was used.
+func (m *model) handleJump(l *list.Model, key string, more bool) bool {
+	if key == jumpKey {
+		m.endJump()
+		m.jumpActive = true
+		m.status = "Jump to: type a letter"
+		return true
+	}
+	if !m.jumpActive {
+		return false
+	}
+	if key == "esc" {
+		m.endJump()
+		m.status = ""
+		return true
+	}
+	letter := strings.ToLower(key)
+	if utf8.RuneCountInString(key) != 1 || key == " " || (m.jumpLetter != "" && letter != m.jumpLetter) {
+		m.endJump()
+		return false
+	}
+
+	m.jumpStart = 0
+	if letter == m.jumpLetter {
+		m.jumpStart = l.Index() + 1
+	}
+	m.jumpLetter = letter
+	m.jumpFrom(l, more)
+	return true
+}
+
+// resumeJump carries on a pending jump once more items of l have loaded
+func (m *model) resumeJump(l *list.Model, more bool) {
+	if m.jumpPending {
+		m.jumpFrom(l, more)
+	}
+}
+
+// jumpFrom selects the first item of l from jumpStart on that starts with the
+// jump letter. The search wraps around to the top only once l is fully
+// loaded, since the next match may be on a page still to come.
+func (m *model) jumpFrom(l *list.Model, more bool) {
+	m.jumpPending = false
+	items := l.VisibleItems()
+	count := len(items)
+	if more {
+		count -= min(m.jumpStart, count)
+	}
+	for i := range count {
+		index := (m.jumpStart + i) % len(items)
+		if strings.HasPrefix(sortTitleKey(items[index].FilterValue()), m.jumpLetter) {
+			l.Select(index)
+			m.status = fmt.Sprintf("Jump to %s - press it again for the next match", strings.ToUpper(m.jumpLetter))
+			return
+		}
+	}
+	if more {
+		m.jumpPending = true
+		m.status = fmt.Sprintf("Looking for %s...", strings.ToUpper(m.jumpLetter))
+		return
+	}
+	m.status = fmt.Sprintf("Nothing starts with %s", strings.ToUpper(m.jumpLetter))
+}
+
+// endJump forgets the jump to letter in progress
+func (m *model) endJump() {
+	m.jumpActive = false
+	m.jumpLetter = ""
+	m.jumpPending = false
+}
+
+// jumpHelpKey describes the jump to letter key shared by the large lists
+func jumpHelpKey() key.Binding {
+	return key.NewBinding(
+		key.WithKeys(jumpKey),
+		key.WithHelp(jumpKey+" <letter>", "Jump to Letter"),
+	)
+}
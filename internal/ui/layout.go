@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// minWidth and minHeight are the smallest terminal the layout is drawn in
+	minWidth  = 40
+	minHeight = 12
+
+	// stackedBreakpoint is the width below which the panels are stacked in a
+	// single column instead of side by side
+	stackedBreakpoint = 80
+)
+
+// tooSmall reports whether the terminal is too small to draw the layout.
+// Before the first WindowSizeMsg the size is unknown and the layout is drawn.
+func (m model) tooSmall() bool {
+	if m.width == 0 && m.height == 0 {
+		return false
+	}
+	return m.width < minWidth || m.height < minHeight
+}
+
+// stacked reports whether the panels are drawn in a single column
+func (m model) stacked() bool {
+	return m.width < stackedBreakpoint
+}
+
+// panelWidth returns the width passed to the bordered panels. The left panel
+// takes the whole width when it is alone in its row.
+func (m model) panelWidth() int {
+	if m.stacked() || !m.layout.sidePanels() {
+		return max(m.width-2, 1)
+	}
+	return max(m.width/2-2, 1)
+}
+
+// listWidth returns the width of the list inside the left panel
+func (m model) listWidth() int {
+	return max(m.panelWidth()-2, 1)
+}
+
+// titleView renders the app title above the panels, followed by the
+// breadcrumb of the left panel
+func (m model) titleView() string {
+	title := lipgloss.NewStyle().Bold(true).Foreground(theme.Title).Render("🎧 Plexamp Control")
+	return title + "\n" + m.breadcrumbView()
+}
+
+// listHeight returns the height of the list inside the left panel. The title
+// and footer are measured as rendered, since the footer spans several lines
+// that depend on the libraries and the terminal width.
+func (m model) listHeight() int {
+	space := m.height - lipgloss.Height(m.titleView()) - lipgloss.Height("\n"+m.footerView())
+	if m.stacked() && m.layout.status {
+		// The Now Playing panel sits below the list
+		space /= 2
+	}
+	return max(space-m.paneBorder(focusList).GetVerticalFrameSize(), 1)
+}
+
+// sizeList fits a browse list to the left panel, once the terminal size is known
+func (m *model) sizeList(l *list.Model) {
+	if m.width == 0 && m.height == 0 {
+		return
+	}
+	l.SetSize(m.listWidth(), m.listHeight())
+}
+
+// resizeLists fits every browse list to the left panel, after the terminal
+// or the footer changed size
+func (m *model) resizeLists() {
+	for _, l := range []*list.Model{
+		&m.playbackList, &m.artistList, &m.albumList, &m.trackList, &m.playlistList, &m.genreList,
+		&m.serverList, &m.playerList, &m.historyList, &m.profileList, &m.queueList, &m.libraryList,
+	} {
+		m.sizeList(l)
+	}
+}
+
+// tooSmallView replaces the layout when the terminal is below the minimum size
+func (m model) tooSmallView() string {
+	msg := fmt.Sprintf("Terminal too small\n%dx%d, need at least %dx%d", m.width, m.height, minWidth, minHeight)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center,
+		lipgloss.NewStyle().Foreground(theme.Negative).Render(msg))
+}
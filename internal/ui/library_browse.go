@@ -0,0 +1,298 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+
+	"plexamp-tui/internal/config"
+	"plexamp-tui/internal/plex"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// errNotMusicLibrary is returned instead of browsing a library of another type,
+// which would otherwise come back empty without explanation
+var errNotMusicLibrary = errors.New("selected library is not a music library")
+
+// checkMusicLibrary reports an error when the selected library is known not to hold music
+func (m *model) checkMusicLibrary() error {
+	for _, library := range m.config.PlexLibraries {
+		if library.Key == m.config.PlexLibraryID {
+			if library.Type != "" && library.Type != "artist" {
+				return errNotMusicLibrary
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// libraryKind returns what the selected library holds, one of the plex.Kind
+// constants
+func (m *model) libraryKind() string {
+	if m.config == nil {
+		return plex.KindMusic
+	}
+	for _, library := range m.config.PlexLibraries {
+		if library.Key == m.config.PlexLibraryID && library.Kind != "" {
+			return library.Kind
+		}
+	}
+	return plex.KindMusic
+}
+
+// libraryTypes returns the levels of the selected library, which name the
+// artist, album and track lists and give the metadata types listed in them
+func (m *model) libraryTypes() plex.LibraryTypes {
+	types := plex.TypesForKind(m.libraryKind())
+	if m.config == nil {
+		return types
+	}
+	for _, library := range m.config.PlexLibraries {
+		if library.Key == m.config.PlexLibraryID {
+			return types.WithTypes(library.ArtistType, library.AlbumType)
+		}
+	}
+	return types
+}
+
+// libraryFetchStatus returns the status line for a failed fetch of a library's contents
+func libraryFetchStatus(what string, err error) string {
+	if errors.Is(err, errNotMusicLibrary) {
+		return "Selected library is not a music library - press 9 to choose another"
+	}
+	return fmt.Sprintf("Error fetching %s: %v", what, err)
+}
+
+// libraryItem represents a music library of the selected server in the list
+type libraryItem struct {
+	library config.PlexLibrary
+	active  bool
+}
+
+// Title returns the library title, marking the library in use and what
+// libraries other than music hold
+func (i libraryItem) Title() string {
+	title := i.library.Title
+	if i.library.Kind != "" && i.library.Kind != plex.KindMusic {
+		title = fmt.Sprintf("%s [%s]", title, i.library.Kind)
+	}
+	if i.active {
+		return fmt.Sprintf("%s (current)", title)
+	}
+	return title
+}
+
+// Description returns the library description (empty for now)
+func (i libraryItem) Description() string { return "" }
+
+// FilterValue implements list.Item
+func (i libraryItem) FilterValue() string { return i.library.Title }
+
+// initLibraryBrowse creates a new library picker
+func (m *model) initLibraryBrowse() {
+	m.panelMode = "libraries"
+
+	// Create a new default delegate with custom styling
+	delegate := list.NewDefaultDelegate()
+	delegate.ShowDescription = false
+
+	m.libraryList = list.New(nil, delegate, 0, 0)
+	m.libraryList.Title = "Plex Libraries"
+	m.libraryList.SetShowFilter(true)
+	m.libraryList.SetFilteringEnabled(true)
+	m.libraryList.Styles.Title = titleStyle
+	m.libraryList.Styles.PaginationStyle = paginationStyle
+	m.libraryList.Styles.HelpStyle = helpStyle
+	m.libraryList.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(
+				key.WithKeys("enter"),
+				key.WithHelp("enter", "Use Library"),
+			),
+			key.NewBinding(
+				key.WithKeys("R"),
+				key.WithHelp("R", "Refresh Libraries"),
+			),
+		}
+	}
+	if m.width > 0 && m.height > 0 {
+		m.sizeList(&m.libraryList)
+	}
+	m.loadLibraryItems()
+}
+
+// librariesFetchedMsg carries the music libraries reloaded from the selected server
+type librariesFetchedMsg struct {
+	libraries []config.PlexLibrary
+	err       error
+}
+
+// fetchLibrariesCmd reloads the music libraries of the selected server
+func (m *model) fetchLibrariesCmd() tea.Cmd {
+	serverAddr := m.config.ServerURL()
+	token := plexClient.GetPlexToken()
+	return func() tea.Msg {
+		libraries, err := plexClient.FetchLibraries(serverAddr, token)
+		return librariesFetchedMsg{libraries: libraries, err: err}
+	}
+}
+
+// applyLibraries replaces the configured libraries with a freshly fetched
+// list and keeps the selected library if it still exists. On the same server
+// the library is matched by key, so a renamed library stays selected under
+// its new name; on another server it is matched by title. Failing that the
+// first library is used.
+func (m *model) applyLibraries(libraries []config.PlexLibrary, sameServer bool) {
+	// The footer lists the libraries, so its height may change
+	defer m.resizeLists()
+
+	if sameServer {
+		keepLibraryTypes(libraries, m.config.PlexLibraries)
+	}
+	m.config.PlexLibraries = libraries
+	if len(libraries) == 0 {
+		return
+	}
+
+	for _, library := range libraries {
+		if (sameServer && library.Key == m.config.PlexLibraryID) ||
+			(!sameServer && library.Title == m.config.PlexLibraryName) {
+			m.config.PlexLibraryID = library.Key
+			m.config.PlexLibraryName = library.Title
+			return
+		}
+	}
+
+	log.Debug("Current library not found on this server, using first library")
+	m.libraryCache.invalidate()
+	m.config.PlexLibraryID = libraries[0].Key
+	m.config.PlexLibraryName = libraries[0].Title
+}
+
+// loadLibraryItems fills the library list from the config, highlighting the
+// library in use
+func (m *model) loadLibraryItems() {
+	var items []list.Item
+	selected := 0
+	for i, library := range m.config.PlexLibraries {
+		active := library.Key == m.config.PlexLibraryID
+		if active {
+			selected = i
+		}
+		items = append(items, libraryItem{library: library, active: active})
+	}
+	m.libraryList.SetItems(items)
+	m.libraryList.Select(selected)
+
+	if len(items) == 0 {
+		m.status = "No libraries - select a server with 6"
+	} else {
+		m.status = fmt.Sprintf("%d libraries", len(items))
+	}
+}
+
+// openLibraryBrowser opens the library picker
+func (m *model) openLibraryBrowser() (tea.Cmd, bool) {
+	if m.config == nil {
+		return nil, false
+	}
+	m.initLibraryBrowse()
+	return nil, true
+}
+
+// selectLibrary switches browsing to a library and saves the choice
+func (m *model) selectLibrary(library config.PlexLibrary) {
+	if library.Key != m.config.PlexLibraryID {
+		// The cached lists belong to the old library
+		m.libraryCache.invalidate()
+	}
+	m.config.PlexLibraryID = library.Key
+	m.config.PlexLibraryName = library.Title
+	if err := cfgManager.Save(m.config); err != nil {
+		m.status = fmt.Sprintf("Error saving config: %v", err)
+		return
+	}
+	m.lastCommand = fmt.Sprintf("Library %s", library.Title)
+	m.status = ""
+	m.panelMode = "playback"
+}
+
+// handleLibraryBrowseUpdate handles updates when in library browse mode
+func (m *model) handleLibraryBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// If we're in filtering mode, let the list handle the input
+	if m.libraryList.FilterState() == list.Filtering {
+		var cmd tea.Cmd
+		m.libraryList, cmd = m.libraryList.Update(msg)
+		return m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		key := msg.String()
+
+		switch key {
+		case "esc", "q":
+			// Return to playback panel
+			m.panelMode = "playback"
+			m.status = ""
+			return m, nil
+
+		case "enter":
+			if selected, ok := m.libraryList.SelectedItem().(libraryItem); ok {
+				log.Debug("Selecting library: %s (key: %s)", selected.library.Title, selected.library.Key)
+				m.selectLibrary(selected.library)
+			}
+			return m, nil
+
+		case "R":
+			m.status = "Refreshing libraries..."
+			return m, m.fetchLibrariesCmd()
+
+		default:
+
+			// Otherwise try the common controls
+			if cmd, handled := m.handleControl(key); handled {
+				return m, cmd
+			}
+		}
+
+	case librariesFetchedMsg:
+		if msg.err != nil {
+			if m.handleAuthError(msg.err) {
+				return m, nil
+			}
+			m.status = fmt.Sprintf("Error refreshing libraries: %v", msg.err)
+			return m, nil
+		}
+
+		m.applyLibraries(msg.libraries, true)
+		if err := cfgManager.Save(m.config); err != nil {
+			m.status = fmt.Sprintf("Error saving config: %v", err)
+			return m, nil
+		}
+		m.loadLibraryItems()
+		return m, nil
+	}
+
+	// Update the library list and get the command
+	var listCmd tea.Cmd
+	m.libraryList, listCmd = m.libraryList.Update(msg)
+	return m, listCmd
+}
+
+// keepLibraryTypes carries the metadata types configured on the old libraries
+// over to the same libraries in a freshly fetched list, which Plex doesn't
+// report them in
+func keepLibraryTypes(libraries, old []config.PlexLibrary) {
+	for i := range libraries {
+		for _, o := range old {
+			if o.Key == libraries[i].Key {
+				libraries[i].ArtistType = o.ArtistType
+				libraries[i].AlbumType = o.AlbumType
+			}
+		}
+	}
+}
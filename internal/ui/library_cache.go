@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"time"
+
+	"plexamp-tui/internal/plex"
+)
+
+// libraryCacheTTL is how long fetched artist and album lists are reused
+// before reopening a panel downloads them again
+const libraryCacheTTL = 5 * time.Minute
+
+// libraryCache keeps the artist and album lists of the selected library so
+// reopening a browse panel doesn't refetch the whole library. It only holds
+// one library at a time: a lookup for any other library evicts everything.
+// It is only touched from Update, so it needs no locking.
+type libraryCache struct {
+	library string // Server and library the cached lists belong to
+
+	artists      []plex.PlexArtist
+	artistsTotal int
+	artistsAt    time.Time
+
+	artistsSort string // Order the artists were fetched in, one of artistSortModes
+
+	albums   []plex.PlexAlbum
+	albumsAt time.Time
+}
+
+func newLibraryCache() *libraryCache {
+	return &libraryCache{}
+}
+
+// libraryCacheKey identifies a library on a particular server
+func libraryCacheKey(serverAddr, libraryID string) string {
+	return serverAddr + "|" + libraryID
+}
+
+// use switches the cache to a library, evicting whatever was cached for the previous one
+func (c *libraryCache) use(library string) {
+	if c.library == library {
+		return
+	}
+	log.Debug("Library changed, evicting cached lists for %q", c.library)
+	*c = libraryCache{library: library}
+}
+
+// fresh reports whether something cached at t is still within the TTL
+func (c *libraryCache) fresh(t time.Time) bool {
+	return !t.IsZero() && time.Since(t) < libraryCacheTTL
+}
+
+// cachedArtists returns the artists loaded so far for a library in the given
+// order and the library's total artist count, if they haven't expired
+func (c *libraryCache) cachedArtists(library, order string) ([]plex.PlexArtist, int, bool) {
+	c.use(library)
+	if !c.fresh(c.artistsAt) || len(c.artists) == 0 || c.artistsSort != order {
+		return nil, 0, false
+	}
+	return c.artists, c.artistsTotal, true
+}
+
+// storeArtists records a page of artists fetched in the given order. The first
+// page replaces the cached list and restarts the TTL, later pages are appended
+// to it.
+func (c *libraryCache) storeArtists(library, order string, start, total int, artists []plex.PlexArtist) {
+	c.use(library)
+	if start == 0 {
+		c.artists = append([]plex.PlexArtist(nil), artists...)
+		c.artistsAt = time.Now()
+		c.artistsSort = order
+	} else if start == len(c.artists) && order == c.artistsSort {
+		c.artists = append(c.artists, artists...)
+	}
+	c.artistsTotal = total
+}
+
+// cachedAlbums returns the albums of a library if they haven't expired
+func (c *libraryCache) cachedAlbums(library string) ([]plex.PlexAlbum, bool) {
+	c.use(library)
+	if !c.fresh(c.albumsAt) || len(c.albums) == 0 {
+		return nil, false
+	}
+	return c.albums, true
+}
+
+// storeAlbums records the albums of a library and restarts the TTL
+func (c *libraryCache) storeAlbums(library string, albums []plex.PlexAlbum) {
+	c.use(library)
+	c.albums = albums
+	c.albumsAt = time.Now()
+}
+
+// setAlbumRating updates the rating of a cached album after it was rated
+func (c *libraryCache) setAlbumRating(ratingKey string, rating float64) {
+	for i := range c.albums {
+		if c.albums[i].RatingKey == ratingKey {
+			c.albums[i].UserRating = rating
+		}
+	}
+}
+
+// invalidate drops the cached lists so the next open refetches them
+func (c *libraryCache) invalidate() {
+	*c = libraryCache{library: c.library}
+}
@@ -7,9 +7,9 @@ import (
 )
 
 func (m model) libraryControlsView() string {
-	value := lipgloss.NewStyle().Foreground(lipgloss.Color("#00ffcc")).Bold(true)
+	value := lipgloss.NewStyle().Foreground(theme.Value).Bold(true)
 
-	body := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#ffaa00")).Render("Library Selection") + "\n\n"
+	body := lipgloss.NewStyle().Bold(true).Foreground(theme.Accent).Render("Library Selection") + "\n\n"
 
 	for _, library := range m.config.PlexLibraries {
 		if library.Key == m.config.PlexLibraryID {
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// startLoading marks a panel as waiting for a fetch and starts the spinner
+// if no other panel is already keeping it ticking
+func (m *model) startLoading(panel string) tea.Cmd {
+	spinning := len(m.loading) > 0
+	m.loading[panel] = true
+	if spinning {
+		return nil
+	}
+	return m.spinner.Tick
+}
+
+// stopLoading marks a panel's fetch as done
+func (m *model) stopLoading(panel string) {
+	delete(m.loading, panel)
+}
+
+// handleSpinnerTick advances the spinner, letting it stop once nothing is loading
+func (m *model) handleSpinnerTick(msg spinner.TickMsg) tea.Cmd {
+	if len(m.loading) == 0 {
+		return nil
+	}
+	var cmd tea.Cmd
+	m.spinner, cmd = m.spinner.Update(msg)
+	return cmd
+}
+
+// emptyMessage describes a browse panel whose fetch found nothing
+func (m model) emptyMessage(panel string) string {
+	switch panel {
+	case "plex-artists":
+		return "No artists in this library"
+	case "plex-albums":
+		if m.albumArtistKey != "" || m.albumFilter != "" {
+			return "No matching albums"
+		}
+		return "No albums in this library"
+	case "plex-tracks":
+		return "No tracks on this album"
+	case "plex-playlists":
+		return "No playlists on this server"
+	case "plex-genres":
+		return fmt.Sprintf("No %ss in this library", m.genreFilter)
+	case "plex-servers":
+		return "No servers found for this account"
+	case "plex-players":
+		return "No players found - is Plexamp running?"
+	case "plex-profiles":
+		return "No profiles found"
+	case "history":
+		return "Nothing played yet"
+	case "queue":
+		return "The play queue is empty"
+	}
+	return "Nothing here"
+}
+
+// browseView renders a browse list. While the list is empty it shows a spinner
+// if the panel is still loading, or a message saying there is nothing to show.
+func (m model) browseView(l list.Model) string {
+	if len(l.Items()) > 0 {
+		return l.View()
+	}
+
+	var body string
+	if m.loading[m.panelMode] {
+		body = lipgloss.NewStyle().Foreground(theme.Accent).Render(m.spinner.View()) + " " + lipgloss.NewStyle().Foreground(theme.Info).Render("Loading...")
+	} else {
+		body = lipgloss.NewStyle().Foreground(theme.Muted).Render(m.emptyMessage(m.panelMode))
+	}
+	return l.Styles.TitleBar.Render(l.Styles.Title.Render(l.Title)) + "\n\n  " + body
+}
@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"plexamp-tui/internal/plex"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// lyricsMsg carries the lyrics fetched for a track
+type lyricsMsg struct {
+	trackKey string
+	lyrics   *plex.Lyrics
+	err      error
+}
+
+// openLyrics shows the lyrics of the playing track, fetching them unless
+// they are already loaded
+func (m *model) openLyrics() tea.Cmd {
+	if !m.plexAuthenticated || m.config == nil {
+		m.status = "Plex authentication required (run with --auth)"
+		return nil
+	}
+	m.panelMode = "lyrics"
+	m.lyricsScroll = 0
+	if m.lyricsKey == m.current.trackKey && m.lyricsErr == nil {
+		return nil
+	}
+	return m.fetchLyricsCmd()
+}
+
+// fetchLyricsCmd fetches the lyrics of the playing track
+func (m *model) fetchLyricsCmd() tea.Cmd {
+	trackKey := m.current.trackKey
+	m.lyricsKey = trackKey
+	m.lyrics = nil
+	m.lyricsErr = nil
+	m.lyricsScroll = 0
+	if trackKey == "" || m.config == nil {
+		return nil
+	}
+
+	token := plexClient.GetPlexToken()
+	serverAddr := m.config.ServerURL()
+
+	return tea.Batch(m.startLoading("lyrics"), func() tea.Msg {
+		lyrics, err := plexClient.FetchLyrics(serverAddr, trackKey, token)
+		return lyricsMsg{trackKey: trackKey, lyrics: lyrics, err: err}
+	})
+}
+
+// handleLyrics stores fetched lyrics, unless the player has moved on to
+// another track since they were asked for
+func (m *model) handleLyrics(msg lyricsMsg) {
+	if msg.trackKey != m.lyricsKey {
+		return
+	}
+	m.stopLoading("lyrics")
+	if msg.err != nil {
+		if m.handleAuthError(msg.err) {
+			return
+		}
+		m.lyricsErr = msg.err
+		return
+	}
+	m.lyrics = msg.lyrics
+}
+
+// handleLyricsKey handles a key press in the lyrics view. Plain lyrics are
+// scrolled by hand; synced ones follow the track.
+func (m *model) handleLyricsKey(key string) tea.Cmd {
+	switch key {
+	case "esc", "q":
+		m.panelMode = "playback"
+		m.status = ""
+		return nil
+
+	case "up", "k":
+		if m.lyricsScroll > 0 {
+			m.lyricsScroll--
+		}
+		return nil
+
+	case "down", "j":
+		if m.lyrics != nil && !m.lyrics.Synced && m.lyricsScroll < len(m.lyrics.Lines)-m.lyricsHeight() {
+			m.lyricsScroll++
+		}
+		return nil
+	}
+
+	cmd, _ := m.handleControl(key)
+	return cmd
+}
+
+// lyricsHeight returns how many lines of lyrics fit below the view's title
+func (m model) lyricsHeight() int {
+	return max(m.listHeight()-3, 1)
+}
+
+// lyricsView renders the lyrics of the playing track. Synced lyrics keep the
+// line being sung highlighted in the middle of the view.
+func (m model) lyricsView() string {
+	header := titleStyle.Render(lipgloss.NewStyle().Bold(true).Foreground(theme.Title).Render("Lyrics"))
+	if m.currentTrack != "" {
+		header += "\n" + lipgloss.NewStyle().Foreground(theme.Muted).MaxWidth(m.listWidth()).Render("  "+m.currentTrack)
+	}
+	header += "\n\n"
+
+	muted := lipgloss.NewStyle().Foreground(theme.Muted)
+	switch {
+	case m.current.trackKey == "":
+		return header + muted.Render("  Nothing is playing")
+	case m.loading["lyrics"]:
+		return header + "  " + lipgloss.NewStyle().Foreground(theme.Accent).Render(m.spinner.View()) + " " + lipgloss.NewStyle().Foreground(theme.Info).Render("Loading...")
+	case m.lyricsErr != nil:
+		return header + muted.Render(fmt.Sprintf("  Couldn't load lyrics: %v", m.lyricsErr))
+	case m.lyrics == nil:
+		return header + muted.Render("  No lyrics available")
+	}
+
+	lines := m.lyrics.Lines
+	height := m.lyricsHeight()
+	first, current := m.lyricsScroll, -1
+	if m.lyrics.Synced {
+		pos := m.currentPosition()
+		for i, line := range lines {
+			if line.TimeMs > pos {
+				break
+			}
+			current = i
+		}
+		first = max(current-height/2, 0)
+	}
+	first = max(min(first, len(lines)-height), 0)
+
+	lineStyle := lipgloss.NewStyle().MaxWidth(m.listWidth())
+	var b strings.Builder
+	for i := first; i < len(lines) && i < first+height; i++ {
+		style := lineStyle
+		switch {
+		case i == current:
+			style = style.Bold(true).Foreground(theme.Accent)
+		case m.lyrics.Synced && i < current:
+			style = style.Foreground(theme.Muted)
+		}
+		b.WriteString(style.Render("  " + lines[i].Text))
+		b.WriteString("\n")
+	}
+	return header + strings.TrimSuffix(b.String(), "\n")
+}
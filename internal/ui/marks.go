@@ -0,0 +1,185 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"plexamp-tui/internal/config"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// markKey marks the selected row of the artist and album lists, so several
+// can be favorited or queued at once
+const markKey = " "
+
+// markDelegate draws a list like the default delegate, with a check after
+// the rows whose rating keys are in marked. The check goes after the title,
+// where it doesn't shift the filter's match highlighting.
+type markDelegate struct {
+	list.DefaultDelegate
+	marked map[string]bool
+}
+
+// newMarkDelegate creates a delegate drawing the marks in marked, which the
+// panel keeps updating
+func newMarkDelegate(marked map[string]bool) markDelegate {
+	return markDelegate{DefaultDelegate: list.NewDefaultDelegate(), marked: marked}
+}
+
+// Render implements list.ItemDelegate
+func (d markDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	if _, ratingKey := markTarget(listItem); ratingKey != "" && d.marked[ratingKey] {
+		listItem = markedItem{listItem.(list.DefaultItem)}
+	}
+	d.DefaultDelegate.Render(w, m, index, listItem)
+}
+
+// markedItem is a marked row, its title followed by a check
+type markedItem struct {
+	list.DefaultItem
+}
+
+// Title returns the row's title with the check
+func (i markedItem) Title() string { return i.DefaultItem.Title() + "  ✓" }
+
+// markTarget returns the name and rating key of an artist or album row
+func markTarget(listItem list.Item) (name, ratingKey string) {
+	switch it := listItem.(type) {
+	case artistItem:
+		return strings.TrimSuffix(it.title, " ★"), it.ratingKey
+	case albumItem:
+		return strings.TrimSuffix(it.title, " ★"), it.ratingKey
+	}
+	return "", ""
+}
+
+// toggleMark marks or unmarks the selected row and moves down to the next
+func (m *model) toggleMark(l *list.Model, marked map[string]bool) {
+	_, ratingKey := markTarget(l.SelectedItem())
+	if ratingKey == "" {
+		return
+	}
+	if marked[ratingKey] {
+		delete(marked, ratingKey)
+	} else {
+		marked[ratingKey] = true
+	}
+	l.CursorDown()
+	m.status = fmt.Sprintf("%d marked - f to favorite, Enter to queue, esc to clear", len(marked))
+	if len(marked) == 0 {
+		m.status = ""
+	}
+}
+
+// markedRows returns the indexes of the marked rows, in list order
+func markedRows(l *list.Model, marked map[string]bool) []int {
+	var rows []int
+	for i, listItem := range l.Items() {
+		if _, ratingKey := markTarget(listItem); ratingKey != "" && marked[ratingKey] {
+			rows = append(rows, i)
+		}
+	}
+	return rows
+}
+
+// favoriteMarked adds the marked rows to the favorites, starring them in the
+// list, and clears the marks. Rows that are already favorites are left as
+// they are rather than removed.
+func (m *model) favoriteMarked(l *list.Model, marked map[string]bool, favType string) {
+	added, skipped := 0, 0
+	for _, i := range markedRows(l, marked) {
+		listItem := l.Items()[i]
+		name, ratingKey := markTarget(listItem)
+		exists, err := favsManager.Has(favType, ratingKey)
+		if err != nil {
+			m.status = fmt.Sprintf("Error checking favorites: %v", err)
+			return
+		}
+		if exists {
+			skipped++
+			continue
+		}
+		if err := favsManager.Add(config.FavoriteItem{Name: name, Type: favType, MetadataKey: ratingKey}); err != nil {
+			m.status = fmt.Sprintf("Error adding favorite: %v", err)
+			return
+		}
+		added++
+
+		switch it := listItem.(type) {
+		case artistItem:
+			it.ToggleFavorite()
+			l.SetItem(i, it)
+		case albumItem:
+			it.ToggleFavorite()
+			l.SetItem(i, it)
+		}
+	}
+	clear(marked)
+
+	if err := m.reloadFavorites(); err != nil {
+		m.status = fmt.Sprintf("Error loading favorites: %v", err)
+		return
+	}
+	m.lastCommand = fmt.Sprintf("Added %d to favorites", added)
+	m.status = m.lastCommand
+	if skipped > 0 {
+		m.status += fmt.Sprintf(", %d already there", skipped)
+	}
+}
+
+// enqueueMarkedCmd adds the marked rows to the end of the play queue, in
+// list order, and clears the marks
+func (m *model) enqueueMarkedCmd(l *list.Model, marked map[string]bool, noun string) tea.Cmd {
+	if m.selected == "" || m.config == nil {
+		m.status = noPlayerStatus()
+		return nil
+	}
+	if m.playQueueID == "" {
+		m.status = "Nothing is queued on the player - start playing before queueing marked items"
+		return nil
+	}
+
+	var ratingKeys []string
+	for _, i := range markedRows(l, marked) {
+		_, ratingKey := markTarget(l.Items()[i])
+		ratingKeys = append(ratingKeys, ratingKey)
+	}
+	clear(marked)
+
+	serverIP := m.selected
+	serverAddr := m.config.ServerURL()
+	serverID := m.config.ServerID
+	playQueueID := m.playQueueID
+	title := fmt.Sprintf("%d %s", len(ratingKeys), noun)
+
+	return func() tea.Msg {
+		for _, ratingKey := range ratingKeys {
+			if err := AddToPlayQueue(serverIP, serverAddr, serverID, playQueueID, ratingKey, false); err != nil {
+				return enqueuedMsg{title: title, err: err}
+			}
+		}
+		return enqueuedMsg{title: title}
+	}
+}
+
+// clearMarks drops the marks of a panel that has been left
+func (m *model) clearMarks(panel string) {
+	switch panel {
+	case "plex-artists":
+		clear(m.artistMarks)
+	case "plex-albums":
+		clear(m.albumMarks)
+	}
+}
+
+// markHelpKey describes the marking key of the artist and album lists
+func markHelpKey() key.Binding {
+	return key.NewBinding(
+		key.WithKeys(markKey),
+		key.WithHelp("space", "Mark for Favorites/Queue"),
+	)
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"plexamp-tui/internal/plex"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// mediaInfoMsg carries the audio quality fetched for a track
+type mediaInfoMsg struct {
+	trackKey string
+	info     *plex.MediaInfo
+	err      error
+}
+
+// fetchMediaInfoCmd fetches the audio quality of the playing track. It is
+// called when the track changes, not on every poll.
+func (m *model) fetchMediaInfoCmd() tea.Cmd {
+	trackKey := m.current.trackKey
+	m.mediaInfoKey = trackKey
+	m.mediaInfo = nil
+	if trackKey == "" || m.config == nil || !m.plexAuthenticated {
+		return nil
+	}
+
+	token := plexClient.GetPlexToken()
+	serverAddr := m.config.ServerURL()
+
+	return func() tea.Msg {
+		info, err := plexClient.FetchMediaInfo(serverAddr, trackKey, token)
+		return mediaInfoMsg{trackKey: trackKey, info: info, err: err}
+	}
+}
+
+// handleMediaInfo stores the fetched audio quality, unless the player has
+// moved on since. The quality line is simply left out when it can't be had.
+func (m *model) handleMediaInfo(msg mediaInfoMsg) {
+	if msg.trackKey != m.mediaInfoKey {
+		return
+	}
+	if msg.err != nil {
+		log.Debug("No media info for track %s: %v", msg.trackKey, msg.err)
+		return
+	}
+	m.mediaInfo = msg.info
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"time"
+
+	"plexamp-tui/internal/mpris"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// mprisCommandMsg carries a playback command from the desktop media controls
+type mprisCommandMsg mpris.Command
+
+// startMPRIS registers the player on the session bus
+// Desktop integration is optional, so failures are only logged
+func startMPRIS() *mpris.Server {
+	server, err := mpris.Start()
+	if err != nil {
+		log.Debug("MPRIS not available: %v", err)
+		return nil
+	}
+	return server
+}
+
+// waitForMPRISCmd waits for the next desktop playback command
+func waitForMPRISCmd() tea.Cmd {
+	if mprisServer == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		cmd, ok := <-mprisServer.Commands()
+		if !ok {
+			return nil
+		}
+		return mprisCommandMsg(cmd)
+	}
+}
+
+// handleMPRISCommand maps a desktop playback command onto the player controls
+func (m *model) handleMPRISCommand(msg mprisCommandMsg) tea.Cmd {
+	switch mpris.Command(msg) {
+	case mpris.CommandPlayPause:
+		return m.togglePlayback()
+	case mpris.CommandPlay:
+		if !m.isPlaying {
+			return m.togglePlayback()
+		}
+	case mpris.CommandPause:
+		if m.isPlaying {
+			return m.togglePlayback()
+		}
+	case mpris.CommandStop:
+		return m.stopPlayback()
+	case mpris.CommandNext:
+		return m.nextTrack()
+	case mpris.CommandPrevious:
+		return m.previousTrack()
+	}
+	return nil
+}
+
+// publishMPRIS returns a command publishing a timeline update to the desktop
+func (m *model) publishMPRIS(msg trackMsgWithState) tea.Cmd {
+	if mprisServer == nil {
+		return nil
+	}
+
+	state := mpris.State{
+		Playing:  msg.IsPlaying,
+		Stopped:  msg.Title == "",
+		Title:    msg.Title,
+		Artist:   msg.Artist,
+		Album:    msg.Album,
+		ArtURL:   m.buildAlbumArtURL(msg.Thumb),
+		Length:   time.Duration(msg.Duration) * time.Millisecond,
+		Position: time.Duration(msg.Position) * time.Millisecond,
+		Volume:   float64(msg.Volume) / 100,
+	}
+	return func() tea.Msg {
+		mprisServer.SetState(state)
+		return nil
+	}
+}
+
+// closeMPRIS releases the MPRIS bus name on quit
+func closeMPRIS() {
+	if mprisServer == nil {
+		return
+	}
+	mprisServer.Close()
+}
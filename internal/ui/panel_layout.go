@@ -0,0 +1,62 @@
+package ui
+
+import "plexamp-tui/internal/config"
+
+// panelLayout describes which of the right-hand panels are shown and in what
+// order. Narrow terminals never show Controls, whatever the layout.
+type panelLayout struct {
+	status        bool // Now Playing
+	controls      bool
+	controlsFirst bool // Controls above Now Playing
+	name          string
+}
+
+// panelLayouts are the layouts the panel layout key steps through
+var panelLayouts = []panelLayout{
+	{status: true, controls: true, name: "Now Playing and Controls"},
+	{status: true, name: "Now Playing only"},
+	{controls: true, name: "Controls only"},
+	{status: true, controls: true, controlsFirst: true, name: "Controls and Now Playing"},
+	{name: "List only"},
+}
+
+// layoutFromConfig returns the panel layout set in the config file
+func layoutFromConfig(cfg *config.Config) panelLayout {
+	status, controls := cfg.StatusPanelShown(), cfg.ControlsPanelShown()
+	for _, layout := range panelLayouts {
+		if layout.status == status && layout.controls == controls && layout.controlsFirst == (cfg.ControlsFirst && status && controls) {
+			return layout
+		}
+	}
+	return panelLayouts[0]
+}
+
+// sidePanels reports whether anything is shown beside the left panel
+func (l panelLayout) sidePanels() bool {
+	return l.status || l.controls
+}
+
+// cyclePanelLayout switches to the next layout in panelLayouts, resizing the
+// lists to the room left for them, and saves it as the layout to start with
+func (m *model) cyclePanelLayout() {
+	next := 0
+	for i, layout := range panelLayouts {
+		if layout == m.layout {
+			next = (i + 1) % len(panelLayouts)
+		}
+	}
+	m.layout = panelLayouts[next]
+	if !m.layout.status {
+		m.focusedPane = focusList
+	}
+	m.resizeLists()
+	m.lastCommand = "Layout: " + m.layout.name
+
+	if m.config != nil {
+		status, controls := m.layout.status, m.layout.controls
+		m.config.ShowStatusPanel = &status
+		m.config.ShowControlsPanel = &controls
+		m.config.ControlsFirst = m.layout.controlsFirst
+		cfgManager.Save(m.config)
+	}
+}
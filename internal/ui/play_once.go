@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+
+	"plexamp-tui/internal/config"
+	"plexamp-tui/internal/logger"
+	"plexamp-tui/internal/plex"
+)
+
+// PlayOnce plays a favorite or library item on the configured player without
+// starting the TUI, for the --play-favorite and --play-key flags. The shuffle
+// state is the one the TUI would start with.
+func PlayOnce(logger *logger.Logger, appConfig *config.Config, client *plex.PlexClient,
+	historyMgr *config.HistoryManager, item config.FavoriteItem,
+) error {
+	log = logger
+	cfg = appConfig
+	plexClient = client
+	historyManager = historyMgr
+
+	if cfg.SelectedPlayer == "" {
+		return errors.New("select a player first (press 7 in the TUI)")
+	}
+	if item.MetadataKey == "" {
+		return errors.New("no metadata key to play")
+	}
+
+	serverIP, serverID := cfg.SelectedPlayer, cfg.ServerID
+	shuffle := cfg.ShuffleOnLaunch()
+	played := config.HistoryItem{Name: item.Name, Type: item.Type, MetadataKey: item.MetadataKey}
+
+	var err error
+	switch item.Type {
+	case "artist", "album":
+		err = PlayMetadata(serverIP, serverID, item.MetadataKey, shuffle)
+	case "track":
+		err = PlayMetadata(serverIP, serverID, item.MetadataKey, false)
+	case "playlist":
+		err = PlayPlaylist(serverIP, serverID, item.MetadataKey, shuffle)
+	case "station":
+		err = PlayArtistRadio(serverIP, serverID, item.MetadataKey, shuffle)
+	case "genre", "decade":
+		filter, value, ok := parseFilterFavoriteKey(item.MetadataKey)
+		if !ok || filter != item.Type {
+			filter, value = item.Type, item.MetadataKey
+		}
+		played.MetadataKey = filterFavoriteKey(filter, value)
+		err = PlayLibraryFilter(serverIP, serverID, cfg.PlexLibraryID, filter, value, shuffle)
+	default:
+		return fmt.Errorf("unknown type %q (use artist, album, track, playlist, station, genre or decade)", item.Type)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Items played by key have no name to show in the history
+	if historyManager != nil && played.Name != "" {
+		if err := historyManager.Record(played); err != nil {
+			log.Error("Failed to record play history: %v", err)
+		}
+	}
+	return nil
+}
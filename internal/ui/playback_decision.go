@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"errors"
+	"time"
+
+	"plexamp-tui/internal/plex"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	decisionRetries    = 3               // Extra looks for a session that hasn't shown up yet
+	decisionRetryDelay = 3 * time.Second // Wait between those looks
+)
+
+// decisionMsg carries how the server is sending a track to the player
+type decisionMsg struct {
+	trackKey string
+	decision string
+	err      error
+}
+
+// decisionRetryMsg asks for another look at the sessions of a track
+type decisionRetryMsg struct {
+	trackKey string
+}
+
+// fetchDecisionCmd finds out whether the playing track is direct played or
+// transcoded. It is called when the track changes, and not at all once the
+// server has refused to list its sessions.
+func (m *model) fetchDecisionCmd() tea.Cmd {
+	m.decisionKey = m.current.trackKey
+	m.decision = ""
+	m.decisionTries = 0
+	return m.lookUpDecisionCmd()
+}
+
+// lookUpDecisionCmd asks the server's sessions for the playing track's decision
+func (m *model) lookUpDecisionCmd() tea.Cmd {
+	trackKey := m.decisionKey
+	if trackKey == "" || m.sessionsDenied || m.config == nil || !m.plexAuthenticated {
+		return nil
+	}
+
+	token := plexClient.GetPlexToken()
+	serverAddr := m.config.ServerURL()
+	playerAddr := m.config.SelectedPlayer
+
+	return func() tea.Msg {
+		decision, err := plexClient.PlaybackDecision(serverAddr, trackKey, playerAddr, token)
+		return decisionMsg{trackKey: trackKey, decision: decision, err: err}
+	}
+}
+
+// handleDecision stores the fetched decision, unless the player has moved on
+// since. A session can take a moment to appear after a track starts, so a
+// missing one is looked for again a few times. The indicator is simply left
+// out when the sessions can't be read.
+func (m *model) handleDecision(msg decisionMsg) tea.Cmd {
+	if msg.trackKey != m.decisionKey {
+		return nil
+	}
+	if msg.err != nil {
+		if errors.Is(msg.err, plex.ErrSessionsDenied) {
+			m.sessionsDenied = true
+		}
+		log.Debug("No playback decision for track %s: %v", msg.trackKey, msg.err)
+		return nil
+	}
+	m.decision = msg.decision
+	if m.decision != "" || m.decisionTries >= decisionRetries {
+		return nil
+	}
+	m.decisionTries++
+	trackKey := msg.trackKey
+	return tea.Tick(decisionRetryDelay, func(time.Time) tea.Msg {
+		return decisionRetryMsg{trackKey: trackKey}
+	})
+}
+
+// decisionBadge renders a decision for the Now Playing panel: green when
+// the audio reaches the player untouched, the accent color, a yellow in the
+// color themes, when it is transcoded
+func decisionBadge(decision string) string {
+	switch decision {
+	case plex.DecisionDirectPlay:
+		return lipgloss.NewStyle().Foreground(theme.Positive).Render("● Direct Play")
+	case plex.DecisionDirectStream:
+		return lipgloss.NewStyle().Foreground(theme.Positive).Render("● Direct Stream")
+	case plex.DecisionTranscode:
+		return lipgloss.NewStyle().Foreground(theme.Accent).Render("● Transcode")
+	}
+	return ""
+}
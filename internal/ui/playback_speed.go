@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	speedStep = 0.1 // Change of each speed key press
+	minSpeed  = 0.5
+	maxSpeed  = 2.0
+)
+
+// speedSetMsg reports whether the player accepted a playback speed change
+type speedSetMsg struct {
+	rate float64
+	err  error
+}
+
+// errSpeedUnsupported is returned when the player rejects the playbackRate parameter
+var errSpeedUnsupported = errors.New("speed control not supported by this player")
+
+// adjustSpeed changes the playback speed by delta, within minSpeed and maxSpeed
+func (m *model) adjustSpeed(delta float64) tea.Cmd {
+	rate := math.Round((m.playbackRate+delta)*10) / 10
+	rate = math.Max(minSpeed, math.Min(maxSpeed, rate))
+	if rate == m.playbackRate {
+		m.status = "Speed is already " + speedLabel(rate)
+		return nil
+	}
+	return m.setSpeedCmd(rate)
+}
+
+// resetSpeed returns playback to normal speed
+func (m *model) resetSpeed() tea.Cmd {
+	if m.playbackRate == 1 {
+		return nil
+	}
+	return m.setSpeedCmd(1)
+}
+
+// setSpeedCmd sends a playback speed to the player. Like setCrossfadeCmd it
+// checks the response, since only some Plexamp builds support it. Once the
+// player has rejected one change, the keys do nothing until another player
+// is selected.
+func (m *model) setSpeedCmd(rate float64) tea.Cmd {
+	if m.speedUnsupported {
+		return nil
+	}
+	if m.selected == "" {
+		m.status = noPlayerStatus()
+		return nil
+	}
+	url := fmt.Sprintf("http://%s:32500/player/playback/setParameters?playbackRate=%s&commandID=1&type=music",
+		m.selected, strconv.FormatFloat(rate, 'f', -1, 64))
+	return func() tea.Msg {
+		resp, err := plexClient.GetWithRetry(url)
+		if err != nil {
+			return speedSetMsg{rate: rate, err: err}
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return speedSetMsg{rate: rate, err: errSpeedUnsupported}
+		}
+		return speedSetMsg{rate: rate}
+	}
+}
+
+// handleSpeedSet records a speed change the player answered
+func (m *model) handleSpeedSet(msg speedSetMsg) {
+	if errors.Is(msg.err, errSpeedUnsupported) {
+		m.speedUnsupported = true
+		m.status = "Speed control not supported by this player"
+		return
+	}
+	if msg.err != nil {
+		m.status = fmt.Sprintf("Error setting speed: %v", msg.err)
+		return
+	}
+	m.playbackRate = msg.rate
+	m.lastCommand = "Speed " + speedLabel(msg.rate)
+}
+
+// speedLabel returns the display form of a playback speed, e.g. "1.1x"
+func speedLabel(rate float64) string {
+	return fmt.Sprintf("%.1fx", rate)
+}
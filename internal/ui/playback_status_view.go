@@ -1,20 +1,28 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
+	"plexamp-tui/internal/plexhttp"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
 func (m model) playbackStatusView() string {
-	info := lipgloss.NewStyle().Foreground(lipgloss.Color("#aaaaaa"))
-	value := lipgloss.NewStyle().Foreground(lipgloss.Color("#00ffcc")).Bold(true)
+	info := lipgloss.NewStyle().Foreground(theme.Label)
+	value := lipgloss.NewStyle().Foreground(theme.Value).Bold(true)
 
 	state := "⏸️ Paused"
-	if m.isPlaying {
+	switch {
+	case m.isPlaying:
 		state = "▶️ Playing"
+	case m.isStopped:
+		state = "⏹️ Stopped"
 	}
 
 	current := "None"
@@ -24,16 +32,64 @@ func (m model) playbackStatusView() string {
 
 	elapsed := m.currentPosition()
 	progress := formatTime(elapsed) + " / " + formatTime(m.durationMs)
-	bar := progressBar(elapsed, m.durationMs, 20)
+	if remaining := remainingTime(elapsed, m.durationMs); remaining != "" {
+		progress += "  " + remaining
+	}
+	bar := m.progressBar(elapsed, m.durationMs, 20)
 
-	body := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#ffaa00")).Render("Now Playing") + "\n\n"
+	volume := fmt.Sprintf("%d", m.volume)
+	if m.muted {
+		volume += " 🔇 Muted"
+	}
+
+	body := lipgloss.NewStyle().Bold(true).Foreground(theme.Accent).Render("Now Playing") + "\n\n"
 	body += fmt.Sprintf(
-		"%s: %s\n%s: %s\n%s: %s\n%s: %d\n",
+		"%s: %s\n%s: %s\n",
 		info.Render("State"), value.Render(state),
 		info.Render("Track"), value.Render(current),
-		info.Render("Progress"), value.Render(bar+"  "+progress),
-		info.Render("Volume"), m.volume,
 	)
+	if upNext := m.upNextLabel(); upNext != "" {
+		body += fmt.Sprintf("%s: %s\n", info.Render("Up next"), lipgloss.NewStyle().Foreground(theme.Value).Render(upNext))
+	}
+	body += fmt.Sprintf(
+		"%s: %s\n%s: %s\n",
+		info.Render("Progress"), bar+"  "+value.Render(progress),
+		info.Render("Volume"), volume,
+	)
+
+	if m.mediaInfo != nil || m.decision != "" {
+		var quality []string
+		if m.mediaInfo != nil {
+			quality = append(quality, value.Render(m.mediaInfo.Label()))
+		}
+		if m.decision != "" {
+			quality = append(quality, decisionBadge(m.decision))
+		}
+		body += fmt.Sprintf("%s: %s\n", info.Render("Quality"), strings.Join(quality, "  "))
+	}
+
+	if m.playbackRate != 1 {
+		body += fmt.Sprintf("%s: %s\n", info.Render("Speed"), value.Render(speedLabel(m.playbackRate)))
+	}
+
+	if m.timelineUnexpected {
+		body += lipgloss.NewStyle().Foreground(theme.Muted).Render("⚠ player returned unexpected data") + "\n"
+	}
+
+	if m.volumeInputActive {
+		body += m.volumeInputView() + "\n"
+	}
+	if m.sleepInputActive {
+		body += m.sleepInputView() + "\n"
+	}
+	if m.topArtistsInputActive {
+		body += m.topArtistsInputView() + "\n"
+	}
+
+	// Album art is exposed as a URL until an image-capable renderer exists
+	if m.albumArtURL != "" {
+		body += fmt.Sprintf("%s: %s\n", info.Render("Art"), info.Render(plexhttp.RedactToken(m.albumArtURL)))
+	}
 
 	return body
 }
@@ -45,33 +101,59 @@ func (m model) playbackStatusView() string {
 // togglePlayback toggles between play and pause
 func (m *model) togglePlayback() tea.Cmd {
 	if m.isPlaying {
-		m.sendCommand("playback/pause")
+		cmd := m.sendCommand("playback/pause")
+		if cmd == nil {
+			return nil
+		}
 		m.isPlaying = false
 		m.lastCommand = "Pause"
-	} else {
-		m.sendCommand("playback/play")
-		m.isPlaying = true
-		m.lastCommand = "Play"
+		return cmd
+	}
+	cmd := m.sendCommand("playback/play")
+	if cmd == nil {
+		return nil
+	}
+	m.isPlaying = true
+	m.lastCommand = "Play"
+	return cmd
+}
+
+// stopPlayback stops the player, unloading the current track
+func (m *model) stopPlayback() tea.Cmd {
+	cmd := m.sendCommand("playback/stop")
+	if cmd == nil {
+		return nil
 	}
-	return m.pollTimeline()
+	m.isPlaying = false
+	m.isStopped = true
+	m.lastCommand = "Stop"
+	return cmd
 }
 
 // nextTrack skips to the next track
 func (m *model) nextTrack() tea.Cmd {
-	m.sendCommand("playback/skipNext")
-	m.lastCommand = "Next"
-	return m.pollTimeline()
+	cmd := m.sendCommand("playback/skipNext")
+	if cmd != nil {
+		m.lastCommand = "Next"
+	}
+	return cmd
 }
 
 // previousTrack goes to the previous track
 func (m *model) previousTrack() tea.Cmd {
-	m.sendCommand("playback/skipPrevious")
-	m.lastCommand = "Previous"
-	return m.pollTimeline()
+	cmd := m.sendCommand("playback/skipPrevious")
+	if cmd != nil {
+		m.lastCommand = "Previous"
+	}
+	return cmd
 }
 
+// bigVolumeStep is the jump of the shifted volume keys
+const bigVolumeStep = 10
+
 // adjustVolume changes the volume by the specified delta (range: -100 to +100)
 func (m *model) adjustVolume(delta int) tea.Cmd {
+	m.forgetMute()
 	newVol := m.volume + delta
 	if newVol < 0 {
 		newVol = 0
@@ -80,20 +162,62 @@ func (m *model) adjustVolume(delta int) tea.Cmd {
 	}
 
 	// Use setVolume to handle the actual volume change
-	m.setVolume(newVol)
+	cmd := m.setVolume(newVol)
+	if cmd != nil {
+		m.lastCommand = fmt.Sprintf("Volume %d%%", newVol)
+	}
+	return cmd
+}
 
-	// Update the status message
-	m.lastCommand = fmt.Sprintf("Volume %d%%", newVol)
+// toggleMute sets the volume to 0, remembering the volume it had, or puts
+// that volume back when already muted
+func (m *model) toggleMute() tea.Cmd {
+	if m.selected == "" {
+		m.status = noPlayerStatus()
+		return nil
+	}
+
+	if m.muted {
+		volume := m.preMuteVolume
+		cmd := m.setVolume(volume)
+		if cmd == nil {
+			return nil
+		}
+		m.forgetMute()
+		m.lastCommand = fmt.Sprintf("Unmuted, volume %d%%", volume)
+		return cmd
+	}
+
+	if m.volume == 0 {
+		m.status = "Volume is already at 0"
+		return nil
+	}
+	volume := m.volume
+	cmd := m.setVolume(0)
+	if cmd == nil {
+		return nil
+	}
+	m.preMuteVolume = volume
+	m.muted = true
+	m.lastCommand = "Muted"
+	return cmd
+}
 
-	// Return a command to update the timeline
-	return m.pollTimeline()
+// forgetMute drops the volume saved by muting, so unmuting doesn't undo a
+// volume the user chose since
+func (m *model) forgetMute() {
+	m.muted = false
+	m.preMuteVolume = 0
 }
 
 // seek seeks the current track by the specified number of seconds
 func (m *model) seek(seconds int) tea.Cmd {
 	// Calculate the new position in milliseconds
-	newPos := m.positionMs + (seconds * 1000)
+	return m.seekTo(m.currentPosition() + (seconds * 1000))
+}
 
+// seekTo seeks the current track to an absolute position in milliseconds
+func (m *model) seekTo(newPos int) tea.Cmd {
 	// Ensure the position is within bounds
 	if newPos < 0 {
 		newPos = 0
@@ -102,27 +226,160 @@ func (m *model) seek(seconds int) tea.Cmd {
 	}
 
 	// Send the seek command with absolute position
-	m.sendCommand(fmt.Sprintf("playback/seekTo?time=%d", newPos))
+	cmd := m.sendCommand(fmt.Sprintf("playback/seekTo?time=%d", newPos))
+	if cmd == nil {
+		return nil
+	}
 	m.lastCommand = fmt.Sprintf("Seek to %s", formatTime(newPos))
 
 	// Update the position immediately for better UX
 	m.positionMs = newPos
 	m.lastUpdate = time.Now()
 
-	return m.pollTimeline()
+	return cmd
+}
+
+// seekPercent jumps to a percentage (0-100) of the current track
+func (m *model) seekPercent(percent int) tea.Cmd {
+	if m.durationMs <= 0 {
+		return nil
+	}
+	return m.seekTo(m.durationMs * percent / 100)
 }
 
 // toggleShuffle toggles shuffle mode
 func (m *model) toggleShuffle() tea.Cmd {
+	path, label := "playback/shuffle/on", "Shuffle ON"
+	if m.shuffle {
+		path, label = "playback/shuffle/off", "Shuffle OFF"
+	}
+	cmd := m.sendCommand(path)
+	if cmd == nil {
+		return nil
+	}
 	m.shuffle = !m.shuffle
+	m.lastCommand = label
+
+	// Start with the same shuffle state next time
+	shuffle := m.shuffle
+	m.config.DefaultShuffle = &shuffle
+	if err := cfgManager.Save(m.config); err != nil {
+		m.status = fmt.Sprintf("Error saving config: %v", err)
+	}
+	return cmd
+}
+
+// shuffleSyncedMsg is sent once the player has been told the shuffle state
+type shuffleSyncedMsg struct{}
+
+// syncShuffleCmd sends the shuffle state to the player, which otherwise keeps
+// its own until shuffle is toggled. Until this is done the shuffle state in
+// the timeline is ignored, so an early poll doesn't undo it.
+func (m *model) syncShuffleCmd() tea.Cmd {
+	if m.selected == "" {
+		return nil
+	}
+	m.shuffleSynced = false
+	state := "off"
 	if m.shuffle {
-		m.sendCommand("playback/shuffle/on")
-		m.lastCommand = "Shuffle ON"
-	} else {
-		m.sendCommand("playback/shuffle/off")
-		m.lastCommand = "Shuffle OFF"
+		state = "on"
 	}
-	return nil
+	url := fmt.Sprintf("http://%s:32500/player/playback/shuffle/%s", m.selected, state)
+	return func() tea.Msg {
+		resp, err := plexClient.GetWithRetry(url)
+		if err != nil {
+			// The player health indicator already shows an unreachable player
+			log.Debug("Error sending shuffle state: %v", err)
+		} else {
+			resp.Body.Close()
+		}
+		return shuffleSyncedMsg{}
+	}
+}
+
+// toggleRepeat cycles the repeat mode through off, repeat one and repeat all
+func (m *model) toggleRepeat() tea.Cmd {
+	repeat := (m.repeat + 1) % 3
+	cmd := m.sendCommand(fmt.Sprintf("playback/setParameters?repeat=%d&commandID=1&type=music", repeat))
+	if cmd == nil {
+		return nil
+	}
+	m.repeat = repeat
+	m.lastCommand = "Repeat " + repeatLabel(m.repeat)
+	return cmd
+}
+
+// repeatLabel returns the display name for a repeat mode
+func repeatLabel(repeat int) string {
+	switch repeat {
+	case 1:
+		return "ONE"
+	case 2:
+		return "ALL"
+	default:
+		return "OFF"
+	}
+}
+
+// crossfadeSteps are the crossfade durations, in seconds, cycled through by toggleCrossfade
+var crossfadeSteps = []int{0, 3, 6, 12}
+
+// crossfadeSetMsg reports whether the player accepted a crossfade change
+type crossfadeSetMsg struct {
+	seconds int
+	err     error
+}
+
+// errCrossfadeUnsupported is returned when the player rejects the crossfade parameter
+var errCrossfadeUnsupported = errors.New("crossfade not supported by this player")
+
+// toggleCrossfade cycles the crossfade duration through crossfadeSteps
+func (m *model) toggleCrossfade() tea.Cmd {
+	next := crossfadeSteps[0]
+	for i, step := range crossfadeSteps {
+		if step == m.crossfade && i+1 < len(crossfadeSteps) {
+			next = crossfadeSteps[i+1]
+			break
+		}
+	}
+	return m.setCrossfadeCmd(next)
+}
+
+// setCrossfadeCmd sends a crossfade duration to the player. Unlike sendCommand
+// it checks the response, since not every player supports crossfading.
+func (m *model) setCrossfadeCmd(seconds int) tea.Cmd {
+	if m.selected == "" {
+		m.status = noPlayerStatus()
+		return nil
+	}
+	url := fmt.Sprintf("http://%s:32500/player/playback/setParameters?crossfade=%d&commandID=1&type=music", m.selected, seconds)
+	return func() tea.Msg {
+		resp, err := plexClient.GetWithRetry(url)
+		if err != nil {
+			return crossfadeSetMsg{seconds: seconds, err: err}
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return crossfadeSetMsg{seconds: seconds, err: errCrossfadeUnsupported}
+		}
+		return crossfadeSetMsg{seconds: seconds}
+	}
+}
+
+// restoreCrossfadeCmd reapplies the saved crossfade duration to the player at startup
+func (m *model) restoreCrossfadeCmd() tea.Cmd {
+	if m.crossfade <= 0 || m.selected == "" {
+		return nil
+	}
+	return m.setCrossfadeCmd(m.crossfade)
+}
+
+// crossfadeLabel returns the display name for a crossfade duration
+func crossfadeLabel(seconds int) string {
+	if seconds <= 0 {
+		return "OFF"
+	}
+	return fmt.Sprintf("%ds", seconds)
 }
 
 // will use the config to cycle through the library options, it will check the current selected library and increment to the next one, if it is the last one it will go back to the first one
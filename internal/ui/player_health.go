@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// maxPollInterval caps the backoff while the player is unreachable
+	maxPollInterval = 30 * time.Second
+
+	// playerDownThreshold is the number of consecutive failed polls after which
+	// the player is treated as down and commands are no longer sent
+	playerDownThreshold = 2
+
+	// timelineWarnInterval limits how often the status line repeats that the
+	// player's timeline can't be read
+	timelineWarnInterval = time.Minute
+)
+
+// pollDelay returns how long to wait before the next timeline poll, doubling
+// with each consecutive failure so an unreachable player isn't hammered
+func (m model) pollDelay() time.Duration {
+	delay := m.pollInterval
+	for i := 0; i < m.playerFailures && delay < maxPollInterval; i++ {
+		delay *= 2
+	}
+	return min(delay, maxPollInterval)
+}
+
+// playerDown reports whether the selected player has stopped answering
+func (m model) playerDown() bool {
+	return m.playerFailures >= playerDownThreshold
+}
+
+// recordPlayerPoll tracks the result of a timeline poll. The status line is
+// only updated when the player goes down or comes back, not on every poll.
+func (m *model) recordPlayerPoll(err error) {
+	if err == nil {
+		if m.playerDown() {
+			m.status = "Player reachable again"
+			log.Info("Player %s is reachable again", m.selected)
+		}
+		m.playerFailures = 0
+		m.playerChecked = true
+		return
+	}
+
+	m.playerFailures++
+	m.playerChecked = true
+	if m.playerFailures == playerDownThreshold {
+		m.status = "Player unreachable: " + err.Error()
+		log.Warn("Player %s is unreachable: %v", m.selected, err)
+	}
+}
+
+// recordTimelineData tracks whether the player's timeline could be read. The
+// Now Playing panel shows the problem for as long as it lasts, while the
+// status line only repeats it once a minute so it doesn't flicker.
+func (m *model) recordTimelineData(err error) {
+	m.timelineUnexpected = err != nil
+	if err == nil || time.Since(m.timelineWarnedAt) < timelineWarnInterval {
+		return
+	}
+	m.timelineWarnedAt = time.Now()
+	m.status = "Player returned unexpected data"
+	log.Warn("Player %s returned an unexpected timeline: %v", m.selected, err)
+}
+
+// playerHealthDot renders the player reachability indicator for the footer
+func (m model) playerHealthDot() string {
+	color := theme.Muted // Not polled yet
+	if m.playerChecked {
+		color = theme.Positive
+		if m.playerDown() {
+			color = theme.Negative
+		}
+	}
+	return lipgloss.NewStyle().Foreground(color).Render("●")
+}
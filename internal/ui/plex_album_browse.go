@@ -1,9 +1,11 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
+	"plexamp-tui/internal/config"
 	"plexamp-tui/internal/plex"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -14,26 +16,37 @@ import (
 type albumPlaybackMsg struct {
 	success bool
 	err     error
+	played  config.HistoryItem // The item that was played, recorded in the history on success
 }
 
 // albumItem represents an album in the list
 type albumItem struct {
-	title     string
-	artist    string
-	year      string
-	ratingKey string
+	title      string
+	artist     string
+	year       string
+	ratingKey  string
+	userRating float64 // Plex rating out of 10
+	viewCount  int
+	lastViewed int64 // Unix time of the last play
+	addedAt    int64
+	viewOffset int // Resume position in milliseconds
 }
 
 // Title returns the album title
 func (i albumItem) Title() string {
 	if strings.HasSuffix(i.title, " ★") {
-		return fmt.Sprintf("%s - %s (%s) ★", strings.TrimSuffix(i.title, " ★"), i.artist, i.year)
+		return fmt.Sprintf("%s - %s (%s) ★%s", strings.TrimSuffix(i.title, " ★"), i.artist, i.year, ratingMark(i.userRating))
 	}
-	return fmt.Sprintf("%s - %s (%s)", i.title, i.artist, i.year)
+	return fmt.Sprintf("%s - %s (%s)%s", i.title, i.artist, i.year, ratingMark(i.userRating))
 }
 
-// Description returns the album description (empty for now)
-func (i albumItem) Description() string { return "" }
+// Description returns how often the album was played
+func (i albumItem) Description() string {
+	if i.ratingKey == "" {
+		return ""
+	}
+	return playCountLabel(i.viewCount, i.lastViewed)
+}
 
 // FilterValue implements list.Item
 func (i albumItem) FilterValue() string {
@@ -52,10 +65,7 @@ func (a *albumItem) ToggleFavorite() {
 // fetchAlbumsCmd fetches albums from the Plex server
 func (m *model) fetchAlbumsCmd() tea.Cmd {
 	log.Debug("Fetching albums...")
-	// ✅ Reapply sizing
-	footerHeight := 3 // or dynamically measure your footer
-	availableHeight := m.height - footerHeight - 5
-	m.albumList.SetSize(m.width/2-4, availableHeight)
+	m.sizeList(&m.albumList)
 	if m.config == nil {
 		return func() tea.Msg {
 			return albumsFetchedMsg{err: fmt.Errorf("no config available")}
@@ -69,32 +79,65 @@ func (m *model) fetchAlbumsCmd() tea.Cmd {
 		}
 	}
 
-	serverAddr := m.config.PlexServerAddr
+	serverAddr := m.config.ServerURL()
 	libraryID := m.config.PlexLibraryID
+	types := m.libraryTypes()
+	artistKey := m.albumArtistKey
+	filter, filterKey := m.albumFilter, m.albumFilterKey
+
+	if artistKey == "" {
+		if err := m.checkMusicLibrary(); err != nil {
+			return func() tea.Msg {
+				return albumsFetchedMsg{err: err}
+			}
+		}
+	}
 
-	return func() tea.Msg {
-		albums, err := plexClient.FetchAlbums(serverAddr, libraryID, token)
-		return albumsFetchedMsg{albums: albums, err: err}
+	library := ""
+	if artistKey == "" && filter == "" {
+		library = libraryCacheKey(serverAddr, libraryID)
+		if albums, ok := m.libraryCache.cachedAlbums(library); ok {
+			log.Debug("Using %d cached albums", len(albums))
+			return func() tea.Msg {
+				return albumsFetchedMsg{albums: albums, library: library, cached: true}
+			}
+		}
 	}
+
+	return tea.Batch(m.startLoading("plex-albums"), func() tea.Msg {
+		if filter != "" {
+			albums, err := plexClient.FetchFilteredAlbums(serverAddr, libraryID, types, filter, filterKey, token)
+			return albumsFetchedMsg{albums: albums, err: err}
+		}
+		if artistKey != "" {
+			albums, err := plexClient.FetchArtistAlbums(serverAddr, artistKey, token)
+			return albumsFetchedMsg{albums: albums, err: err}
+		}
+		albums, err := plexClient.FetchAlbums(serverAddr, libraryID, types, token)
+		return albumsFetchedMsg{albums: albums, library: library, err: err}
+	})
 }
 
 // initAlbumBrowse creates a new album browser
 func (m *model) initAlbumBrowse() {
 	m.panelMode = "plex-albums"
 	m.status = "Loading albums..."
+	m.albumArtistKey = ""
+	m.albumFilter = ""
+	m.albumScopeName = ""
+	m.albumFilterKey = ""
 
-	// Create a new default delegate with custom styling
-	delegate := list.NewDefaultDelegate()
-	delegate.ShowDescription = false
-
-	items := []list.Item{albumItem{title: "Loading albums..."}}
+	// Create a new delegate that checks the marked albums; the description holds the play count
+	clear(m.albumMarks)
+	delegate := newMarkDelegate(m.albumMarks)
 
 	// Create the list with empty items for now
-	m.albumList = list.New(items, delegate, 0, 0)
-	m.albumList.Title = "Plex Albums"
+	m.albumList = list.New(nil, delegate, 0, 0)
+	m.albumList.Title = "Plex " + m.libraryTypes().Albums
 	m.albumList.SetShowFilter(true)
 	m.albumList.SetFilteringEnabled(true)
 	m.albumList.Styles.Title = titleStyle
+	m.albumList.SetStatusBarItemName("album", "albums")
 	m.albumList.Styles.PaginationStyle = paginationStyle
 	m.albumList.Styles.HelpStyle = helpStyle
 	m.albumList.AdditionalShortHelpKeys = func() []key.Binding {
@@ -103,29 +146,62 @@ func (m *model) initAlbumBrowse() {
 				key.WithKeys("f"),
 				key.WithHelp("f", "favs"),
 			),
+			key.NewBinding(
+				key.WithKeys("d"),
+				key.WithHelp("d", "tracks"),
+			),
 		}
 	}
 	m.albumList.AdditionalFullHelpKeys = func() []key.Binding {
-		return []key.Binding{
+		return append([]key.Binding{
 			key.NewBinding(
 				key.WithKeys("f"),
 				key.WithHelp("f", "Add/Remove from Favorites"),
 			),
+			key.NewBinding(
+				key.WithKeys("d"),
+				key.WithHelp("d", "Browse Album Tracks"),
+			),
 			key.NewBinding(
 				key.WithKeys("R"),
 				key.WithHelp("R", "Refresh Albums"),
 			),
-		}
+			key.NewBinding(
+				key.WithKeys("r"),
+				key.WithHelp("r", "Play Radio"),
+			),
+			rateHelpKey(),
+			sortHelpKey(),
+			jumpHelpKey(),
+			markHelpKey(),
+		}, enqueueHelpKeys()...)
 	}
 	if m.width > 0 && m.height > 0 {
-		m.albumList.SetSize(m.width/2-4, m.height-4)
+		m.sizeList(&m.albumList)
 	}
 }
 
-func (m *model) playAlbumCmd(ratingKey string) tea.Cmd {
+// initArtistAlbumBrowse creates an album browser scoped to a single artist
+func (m *model) initArtistAlbumBrowse(artist artistItem) {
+	m.initAlbumBrowse()
+	m.albumArtistKey = artist.ratingKey
+	m.albumScopeName = strings.TrimSuffix(artist.title, " ★")
+	m.albumList.Title = fmt.Sprintf("%s by %s", m.libraryTypes().Albums, strings.TrimSuffix(artist.title, " ★"))
+}
+
+// initFilterAlbumBrowse creates an album browser scoped to a genre or decade
+func (m *model) initFilterAlbumBrowse(filter string, value genreItem) {
+	m.initAlbumBrowse()
+	m.albumFilter = filter
+	m.albumScopeName = strings.TrimSuffix(value.title, " ★")
+	m.albumFilterKey = value.key
+	m.albumList.Title = fmt.Sprintf("%s %s", strings.TrimSuffix(value.title, " ★"), m.libraryTypes().Albums)
+}
+
+func (m *model) playAlbumCmd(name, ratingKey string) tea.Cmd {
 	if m.selected == "" {
 		return func() tea.Msg {
-			return albumPlaybackMsg{success: false, err: fmt.Errorf("no server selected")}
+			return albumPlaybackMsg{success: false, err: errNoPlayer}
 		}
 	}
 
@@ -144,12 +220,39 @@ func (m *model) playAlbumCmd(ratingKey string) tea.Cmd {
 		if err != nil {
 			return albumPlaybackMsg{success: false, err: err}
 		}
-		return albumPlaybackMsg{success: true}
+		return albumPlaybackMsg{success: true, played: config.HistoryItem{Name: name, Type: "album", MetadataKey: ratingKey}}
+	}
+}
+
+// playAlbumRadioCmd starts a station of music similar to an album
+func (m *model) playAlbumRadioCmd(name, ratingKey string) tea.Cmd {
+	if m.selected == "" {
+		return func() tea.Msg {
+			return albumPlaybackMsg{success: false, err: errNoPlayer}
+		}
+	}
+
+	if m.config == nil {
+		return func() tea.Msg {
+			return albumPlaybackMsg{success: false, err: fmt.Errorf("no config available")}
+		}
+	}
+
+	serverIP := m.selected
+	serverID := m.config.ServerID
+	shuffle := m.shuffle
+
+	return func() tea.Msg {
+		err := PlayAlbumRadio(serverIP, serverID, ratingKey, shuffle)
+		if err != nil {
+			return albumPlaybackMsg{success: false, err: err}
+		}
+		return albumPlaybackMsg{success: true, played: config.HistoryItem{Name: name, Type: "station", MetadataKey: ratingKey}}
 	}
 }
 
 func (m *model) handleAlbumBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
-	log.Debug(fmt.Sprintf("handleAlbumBrowseUpdate received message: %T", msg))
+	log.Debug("handleAlbumBrowseUpdate received message: %T", msg)
 
 	// If we're in filtering mode, let the list handle the input
 	if m.albumList.FilterState() == list.Filtering {
@@ -161,18 +264,42 @@ func (m *model) handleAlbumBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		key := msg.String()
+		if m.handleJump(&m.albumList, key, false) {
+			return m, nil
+		}
 
 		switch key {
 		case "esc", "q":
-			// Return to playback panel
-			m.panelMode = "playback"
+			// Clear the marks first, if there are any
+			if len(m.albumMarks) > 0 {
+				clear(m.albumMarks)
+				m.status = ""
+				return m, nil
+			}
+			// Return to the list the album browser was scoped from, otherwise to the playback panel
+			if m.albumFilter != "" {
+				m.panelMode = "plex-genres"
+			} else if m.albumArtistKey != "" {
+				m.panelMode = "plex-artists"
+			} else {
+				m.panelMode = "playback"
+			}
 			m.status = ""
 			return m, nil
 
+		case markKey:
+			m.toggleMark(&m.albumList, m.albumMarks)
+			return m, nil
+
 		case "f":
+			// Add the marked albums to the favorites
+			if len(m.albumMarks) > 0 {
+				m.favoriteMarked(&m.albumList, m.albumMarks, "album")
+				return m, nil
+			}
 			// add or remove selected artist from favorites (playback list)
 			if selected, ok := m.albumList.SelectedItem().(albumItem); ok {
-				log.Debug(fmt.Sprintf("Toggling favorite for album: %s (ratingKey: %s)", selected.title, selected.ratingKey))
+				log.Debug("Toggling favorite for album: %s (ratingKey: %s)", selected.title, selected.ratingKey)
 				m.lastCommand = fmt.Sprintf("Toggling favorite for %s", selected.title)
 
 				_, cmd := m.addRemoveFavorite(selected.title, selected.ratingKey, "album")
@@ -185,11 +312,50 @@ func (m *model) handleAlbumBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case "enter":
-			// Play selected album's tracks
+			// Queue the marked albums, otherwise play the selected album's tracks
+			if len(m.albumMarks) > 0 {
+				return m, m.enqueueMarkedCmd(&m.albumList, m.albumMarks, "albums")
+			}
 			if selected, ok := m.albumList.SelectedItem().(albumItem); ok {
-				log.Debug(fmt.Sprintf("Playing album: %s (ratingKey: %s)", selected.title, selected.ratingKey))
+				log.Debug("Playing album: %s (ratingKey: %s)", selected.title, selected.ratingKey)
 				m.lastCommand = fmt.Sprintf("Playing %s", selected.title)
-				return m, m.playAlbumCmd(selected.ratingKey)
+				name := strings.TrimSuffix(selected.title, " ★")
+				return m, m.offerResume(name, selected.viewOffset, m.playAlbumCmd(name, selected.ratingKey))
+			}
+			return m, nil
+
+		case "L":
+			// Love the selected album on Plex, or clear its rating
+			if selected, ok := m.albumList.SelectedItem().(albumItem); ok {
+				return m, m.rateCmd(selected.title, selected.ratingKey, selected.userRating)
+			}
+			return m, nil
+
+		case "s":
+			m.cycleAlbumSort()
+			return m, nil
+
+		case "N", "A":
+			// Queue the selected album after the current track or at the end, staying in this panel
+			if selected, ok := m.albumList.SelectedItem().(albumItem); ok && selected.ratingKey != "" {
+				return m, m.enqueueCmd(selected.title, selected.ratingKey, false, key == "N")
+			}
+			return m, nil
+
+		case "r":
+			// Play a station seeded from the selected album
+			if selected, ok := m.albumList.SelectedItem().(albumItem); ok && selected.ratingKey != "" {
+				m.lastCommand = fmt.Sprintf("Playing %s Radio", selected.title)
+				return m, m.playAlbumRadioCmd(strings.TrimSuffix(selected.title, " ★"), selected.ratingKey)
+			}
+			return m, nil
+
+		case "d":
+			// Drill down into the selected album's tracks
+			if selected, ok := m.albumList.SelectedItem().(albumItem); ok && selected.ratingKey != "" {
+				log.Debug("Browsing tracks for album: %s (ratingKey: %s)", selected.title, selected.ratingKey)
+				m.initTrackBrowse(selected)
+				return m, m.fetchTracksCmd()
 			}
 			return m, nil
 
@@ -197,6 +363,7 @@ func (m *model) handleAlbumBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Refresh album list
 			m.status = "Refreshing albums..."
 			m.lastCommand = "Refreshing album list"
+			m.libraryCache.invalidate()
 			return m, m.fetchAlbumsCmd()
 
 		default:
@@ -208,24 +375,29 @@ func (m *model) handleAlbumBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case albumsFetchedMsg:
-		log.Debug(fmt.Sprintf("albumsFetchedMsg received with %d albums, error: %v", len(msg.albums), msg.err))
+		log.Debug("albumsFetchedMsg received with %d albums, error: %v", len(msg.albums), msg.err)
 		if msg.err != nil {
-			errMsg := fmt.Sprintf("Error fetching albums: %v", msg.err)
+			if m.handleAuthError(msg.err) {
+				return m, nil
+			}
+			if errors.Is(msg.err, errNotMusicLibrary) {
+				m.albumList.SetItems(nil)
+			}
+			errMsg := libraryFetchStatus("albums", msg.err)
 			m.status = errMsg
-			log.Debug(errMsg)
+			log.Debug("%s", errMsg)
 			return m, nil
 		}
-
-		favSet := make(map[string]struct{})
-		for _, pItem := range m.playbackList.Items() {
-			pItem := pItem.(item)
-			favSet[pItem.GetMetadataKey()] = struct{}{}
+		if msg.library != "" && !msg.cached {
+			m.libraryCache.storeAlbums(msg.library, msg.albums)
 		}
+
+		favSet := m.getCurrentFavSet()
 		// Convert albums to list items
 		var items []list.Item
 		for i, album := range msg.albums {
 			if i < 5 { // Only log first 5 albums to avoid log spam
-				log.Debug(fmt.Sprintf("Adding album %d: %s (ratingKey: %s)", i+1, album.Title, album.RatingKey))
+				log.Debug("Adding album %d: %s (ratingKey: %s)", i+1, album.Title, album.RatingKey)
 			}
 
 			fav := false
@@ -238,14 +410,19 @@ func (m *model) handleAlbumBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			items = append(items, albumItem{
-				title:     title,
-				artist:    album.ParentTitle,
-				year:      album.Year,
-				ratingKey: album.RatingKey,
+				title:      title,
+				artist:     album.ParentTitle,
+				year:       album.Year,
+				ratingKey:  album.RatingKey,
+				userRating: album.UserRating,
+				viewCount:  album.ViewCount,
+				lastViewed: album.LastViewedAt,
+				addedAt:    album.AddedAt,
+				viewOffset: album.ViewOffset,
 			})
 		}
 
-		log.Debug(fmt.Sprintf("Creating new list with %d items", len(items)))
+		log.Debug("Creating new list with %d items", len(items))
 		// Create a new list with the fetched items
 		// Preserve the current filter state
 		filterState := m.albumList.FilterState()
@@ -256,35 +433,30 @@ func (m *model) handleAlbumBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 		delegate.ShowDescription = false // Don't show description
 
 		// Create new list with existing items
+		sortAlbumItems(items, m.albumSort)
 		m.albumList.SetItems(items)
 		m.albumList.ResetSelected()
+		m.restoreSelection(&m.albumList)
 
 		// Restore filter state if there was one
 		if filterState == list.Filtering {
 			m.albumList.ResetFilter()
 			m.albumList.FilterInput.SetValue(filterValue)
 		}
-		m.status = fmt.Sprintf("Loaded %d albums", len(msg.albums))
-		log.Debug(fmt.Sprintf("Updated model with new album list. List has %d items", m.albumList.VisibleItems()))
+		switch {
+		case len(msg.albums) > 0:
+			m.status = fmt.Sprintf("Loaded %d albums", len(msg.albums))
+		case m.albumArtistKey != "" || m.albumFilter != "":
+			m.status = "No matching albums"
+		default:
+			m.status = "No albums in this library"
+		}
+		log.Debug("Updated model with new album list. List has %d items", m.albumList.VisibleItems())
 
-		// ✅ Reapply sizing
-		footerHeight := 3 // or dynamically measure your footer
-		availableHeight := m.height - footerHeight - 5
-		m.albumList.SetSize(m.width/2-4, availableHeight)
+		m.sizeList(&m.albumList)
 
 		// Force a redraw
 		return m, tea.Batch(tea.ClearScreen, func() tea.Msg { return nil })
-
-	case albumPlaybackMsg:
-		if msg.success {
-			m.lastCommand = "Album Playback Started"
-			m.status = "Playback triggered successfully"
-		} else {
-			m.lastCommand = "Playback Failed"
-			m.status = fmt.Sprintf("Playback error: %v", msg.err)
-		}
-		// Return the updated model and no command
-		return m, nil
 	}
 
 	// Update the artist list and get the command
@@ -301,6 +473,8 @@ func (m *model) ViewAlbum() string {
 
 // albumsFetchedMsg is a message containing fetched albums
 type albumsFetchedMsg struct {
-	albums []plex.PlexAlbum
-	err    error
+	albums  []plex.PlexAlbum
+	library string // Cache key of the library, empty for scoped album lists which aren't cached
+	cached  bool   // Whether the albums came from the library cache
+	err     error
 }
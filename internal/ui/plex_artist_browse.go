@@ -1,9 +1,11 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
+	"plexamp-tui/internal/config"
 	"plexamp-tui/internal/plex"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -18,12 +20,29 @@ import (
 
 type artistsFetchedMsg struct {
 	artists []plex.PlexArtist
+	start   int    // Offset of this page within the library
+	total   int    // Total number of artists in the library
+	library string // Cache key of the library the artists belong to
+	order   string // Sort mode the artists were fetched in
+	cached  bool   // Whether the artists came from the library cache
 	err     error
 }
 
+// artistPageSize is the number of artists requested per page
+const artistPageSize = 200
+
+// artistFullFetchLimit is the largest library whose artists are all fetched
+// at once; only larger ones load a page at a time as the list scrolls
+const artistFullFetchLimit = 2000
+
+// artistPrefetchThreshold is how close to the end of the loaded artists the
+// selection has to get before the next page is requested
+const artistPrefetchThreshold = 20
+
 type artistPlaybackMsg struct {
 	success bool
 	err     error
+	played  config.HistoryItem // The item that was played, recorded in the history on success
 }
 
 // =====================
@@ -33,10 +52,7 @@ type artistPlaybackMsg struct {
 // fetchArtistsCmd fetches artists from the Plex server
 func (m *model) fetchArtistsCmd() tea.Cmd {
 	log.Debug("Fetching artists...")
-	// ✅ Reapply sizing
-	footerHeight := 3 // or dynamically measure your footer
-	availableHeight := m.height - footerHeight - 5
-	m.artistList.SetSize(m.width/2-4, availableHeight)
+	m.sizeList(&m.artistList)
 	if m.config == nil {
 		return func() tea.Msg {
 			return artistsFetchedMsg{err: fmt.Errorf("no config available")}
@@ -50,20 +66,83 @@ func (m *model) fetchArtistsCmd() tea.Cmd {
 		}
 	}
 
-	serverAddr := m.config.PlexServerAddr
+	if err := m.checkMusicLibrary(); err != nil {
+		return func() tea.Msg {
+			return artistsFetchedMsg{err: err}
+		}
+	}
+
+	library := libraryCacheKey(m.config.ServerURL(), m.config.PlexLibraryID)
+	order := m.artistSort
+	if artists, total, ok := m.libraryCache.cachedArtists(library, order); ok {
+		log.Debug("Using %d cached artists", len(artists))
+		return func() tea.Msg {
+			return artistsFetchedMsg{artists: artists, total: total, library: library, order: order, cached: true}
+		}
+	}
+
+	return m.fetchArtistsPageCmd(0)
+}
+
+// fetchArtistsPageCmd fetches a single page of artists starting at the given
+// offset. The first page of a small library comes with the rest of it.
+func (m *model) fetchArtistsPageCmd(start int) tea.Cmd {
+	token := plexClient.GetPlexToken()
+	serverAddr := m.config.ServerURL()
 	libraryID := m.config.PlexLibraryID
+	types := m.libraryTypes()
+	library := libraryCacheKey(serverAddr, libraryID)
+	order := m.artistSort
+	m.artistsLoading = true
+
+	return tea.Batch(m.startLoading("plex-artists"), func() tea.Msg {
+		artists, total, err := plexClient.FetchArtistsPage(serverAddr, libraryID, types, token, artistServerSorts[order], start, artistPageSize)
+		if err == nil && start == 0 && len(artists) < total && total <= artistFullFetchLimit {
+			artists, err = plexClient.FetchArtists(serverAddr, libraryID, types, token)
+			total = len(artists)
+		}
+		return artistsFetchedMsg{artists: artists, start: start, total: total, library: library, order: order, err: err}
+	})
+}
 
-	return func() tea.Msg {
-		artists, err := plexClient.FetchArtists(serverAddr, libraryID, token)
-		return artistsFetchedMsg{artists: artists, err: err}
+// fetchMoreArtistsCmd requests the next page of artists once the selection
+// nears the end of what has been loaded so far. A filter, a jump to letter and
+// the selection restored from the last session can all need artists past the
+// loaded ones, so they keep loading pages until they have them.
+func (m *model) fetchMoreArtistsCmd() tea.Cmd {
+	loaded := len(m.artistList.Items())
+	if m.artistsLoading || m.config == nil || loaded >= m.artistsTotal {
+		return nil
+	}
+	filtered := m.artistList.FilterState() != list.Unfiltered
+	restoring := m.restoreIndex >= loaded
+	if !filtered && !m.jumpPending && !restoring && m.artistList.Index() < loaded-artistPrefetchThreshold {
+		return nil
+	}
+	if !m.jumpPending {
+		m.status = fmt.Sprintf("Loading more artists... (%d of %d)", loaded, m.artistsTotal)
+	}
+	return m.fetchArtistsPageCmd(loaded)
+}
+
+// restoreArtistSelection selects the artist restored from the last session
+// once the page holding it has loaded
+func (m *model) restoreArtistSelection() {
+	if m.restoreIndex < len(m.artistList.Items()) || len(m.artistList.Items()) >= m.artistsTotal {
+		m.restoreSelection(&m.artistList)
 	}
 }
 
+// moreArtists reports whether artists are left to load into the unfiltered list
+func (m *model) moreArtists() bool {
+	return len(m.artistList.Items()) < m.artistsTotal && m.artistList.FilterState() == list.Unfiltered
+}
+
 // playArtistCmd starts playback for an artist (using artist's tracks)
-func (m *model) playArtistCmd(ratingKey string) tea.Cmd {
+func (m *model) playArtistCmd(name, ratingKey string) tea.Cmd {
 	if m.selected == "" {
 		return func() tea.Msg {
-			return artistPlaybackMsg{success: false, err: fmt.Errorf("no server selected")}
+			return artistPlaybackMsg{success: false, err: errNoPlayer}
 		}
 	}
 
@@ -82,15 +161,15 @@ func (m *model) playArtistCmd(ratingKey string) tea.Cmd {
 		if err != nil {
 			return artistPlaybackMsg{success: false, err: err}
 		}
-		return artistPlaybackMsg{success: true}
+		return artistPlaybackMsg{success: true, played: config.HistoryItem{Name: name, Type: "artist", MetadataKey: ratingKey}}
 	}
 }
 
 // playArtistRadioCmd starts playback for an artist's radio station
-func (m *model) playArtistRadioCmd(ratingKey string) tea.Cmd {
+func (m *model) playArtistRadioCmd(name, ratingKey string) tea.Cmd {
 	if m.selected == "" {
 		return func() tea.Msg {
-			return artistPlaybackMsg{success: false, err: fmt.Errorf("no server selected")}
+			return artistPlaybackMsg{success: false, err: errNoPlayer}
 		}
 	}
 
@@ -109,7 +188,7 @@ func (m *model) playArtistRadioCmd(ratingKey string) tea.Cmd {
 		if err != nil {
 			return artistPlaybackMsg{success: false, err: err}
 		}
-		return artistPlaybackMsg{success: true}
+		return artistPlaybackMsg{success: true, played: config.HistoryItem{Name: name, Type: "station", MetadataKey: ratingKey}}
 	}
 }
 
@@ -119,18 +198,19 @@ func (m *model) initArtistBrowse() {
 	m.panelMode = "plex-artists"
 	m.status = "Loading artists..."
 	// Log the current model state
-	log.Debug(fmt.Sprintf("initArtistBrowse - panelMode: %s, status: %s", m.panelMode, m.status))
+	log.Debug("initArtistBrowse - panelMode: %s, status: %s", m.panelMode, m.status)
 
-	items := []list.Item{artistItem{title: "Loading artists..."}}
-	// Create a new default delegate with custom styling
-	delegate := list.NewDefaultDelegate()
+	// Create a new delegate that checks the marked artists
+	clear(m.artistMarks)
+	delegate := newMarkDelegate(m.artistMarks)
 	delegate.ShowDescription = false // Don't show description
 
-	m.artistList = list.New(items, delegate, 0, 0)
-	m.artistList.Title = "Plex Artists"
+	m.artistList = list.New(nil, delegate, 0, 0)
+	m.artistList.Title = "Plex " + m.libraryTypes().Artists
 	m.artistList.SetShowFilter(true)
 	m.artistList.SetFilteringEnabled(true)
 	m.artistList.Styles.Title = titleStyle
+	m.artistList.SetStatusBarItemName("artist", "artists")
 	m.artistList.Styles.PaginationStyle = paginationStyle
 	m.artistList.Styles.HelpStyle = helpStyle
 	m.artistList.AdditionalShortHelpKeys = func() []key.Binding {
@@ -139,10 +219,14 @@ func (m *model) initArtistBrowse() {
 				key.WithKeys("f"),
 				key.WithHelp("f", "favs"),
 			),
+			key.NewBinding(
+				key.WithKeys("d"),
+				key.WithHelp("d", "albums"),
+			),
 		}
 	}
 	m.artistList.AdditionalFullHelpKeys = func() []key.Binding {
-		return []key.Binding{
+		return append([]key.Binding{
 			key.NewBinding(
 				key.WithKeys("f"),
 				key.WithHelp("f", "Add/Remove from Favorites"),
@@ -151,55 +235,92 @@ func (m *model) initArtistBrowse() {
 				key.WithKeys("r"),
 				key.WithHelp("r", "Play Radio"),
 			),
+			key.NewBinding(
+				key.WithKeys("d"),
+				key.WithHelp("d", "Browse Artist Albums"),
+			),
+			key.NewBinding(
+				key.WithKeys("i"),
+				key.WithHelp("i", "Artist Info"),
+			),
 			key.NewBinding(
 				key.WithKeys("R"),
 				key.WithHelp("R", "Refresh Artists"),
 			),
-		}
+			key.NewBinding(
+				key.WithKeys("F"),
+				key.WithHelp("F", "Add Top Artists to Favorites"),
+			),
+			sortHelpKey(),
+			jumpHelpKey(),
+			markHelpKey(),
+		}, enqueueHelpKeys()...)
 	}
 
 	if m.width > 0 && m.height > 0 {
-		m.artistList.SetSize(m.width/2-4, m.height-4)
+		m.sizeList(&m.artistList)
 	}
-	log.Debug(fmt.Sprintf("Initialized artist list with size: %dx%d", m.width/2-4, m.height-4))
+	log.Debug("Initialized artist list with size: %dx%d", m.listWidth(), m.height-4)
 }
 
 // handleArtistBrowseUpdate handles updates when in artist browse mode
 // It updates the model in place and returns the updated model and a command
 func (m *model) handleArtistBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
-	log.Debug(fmt.Sprintf("handleArtistBrowseUpdate received message: %T", msg))
+	log.Debug("handleArtistBrowseUpdate received message: %T", msg)
 
 	// If we're in filtering mode, let the list handle the input
-	if m.artistList.FilterState() == list.Filtering {
+	if _, isKey := msg.(tea.KeyMsg); isKey && m.artistList.FilterState() == list.Filtering {
 		var cmd tea.Cmd
 		m.artistList, cmd = m.artistList.Update(msg)
-		return m, cmd
+		// The filter only matches loaded artists, so load the rest
+		return m, tea.Batch(cmd, m.fetchMoreArtistsCmd())
 	}
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		key := msg.String()
+		if m.handleJump(&m.artistList, key, m.moreArtists()) {
+			return m, m.fetchMoreArtistsCmd()
+		}
 
 		switch key {
 		case "esc", "q":
+			// Clear the marks first, if there are any
+			if len(m.artistMarks) > 0 {
+				clear(m.artistMarks)
+				m.status = ""
+				return m, nil
+			}
 			// Return to playback panel
 			m.panelMode = "playback"
 			m.status = ""
 			return m, nil
 
+		case markKey:
+			m.toggleMark(&m.artistList, m.artistMarks)
+			return m, nil
+
 		case "enter":
-			// Play selected artist's tracks
+			// Queue the marked artists, otherwise play the selected artist's tracks
+			if len(m.artistMarks) > 0 {
+				return m, m.enqueueMarkedCmd(&m.artistList, m.artistMarks, "artists")
+			}
 			if selected, ok := m.artistList.SelectedItem().(artistItem); ok {
-				log.Debug(fmt.Sprintf("Playing artist: %s (ratingKey: %s)", selected.title, selected.ratingKey))
+				log.Debug("Playing artist: %s (ratingKey: %s)", selected.title, selected.ratingKey)
 				m.lastCommand = fmt.Sprintf("Playing %s", selected.title)
-				return m, m.playArtistCmd(selected.ratingKey)
+				return m, m.playArtistCmd(strings.TrimSuffix(selected.title, " ★"), selected.ratingKey)
 			}
 			return m, nil
 
 		case "f":
+			// Add the marked artists to the favorites
+			if len(m.artistMarks) > 0 {
+				m.favoriteMarked(&m.artistList, m.artistMarks, "artist")
+				return m, nil
+			}
 			// add or remove selected artist from favorites (playback list)
 			if selected, ok := m.artistList.SelectedItem().(artistItem); ok {
-				log.Debug(fmt.Sprintf("Toggling favorite for artist: %s (ratingKey: %s)", selected.title, selected.ratingKey))
+				log.Debug("Toggling favorite for artist: %s (ratingKey: %s)", selected.title, selected.ratingKey)
 				m.lastCommand = fmt.Sprintf("Toggling favorite for %s", selected.title)
 				_, cmd := m.addRemoveFavorite(selected.title, selected.ratingKey, "artist")
 				selected.ToggleFavorite()
@@ -211,15 +332,43 @@ func (m *model) handleArtistBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "r": // Shift+R for artist radio
 			// Play selected artist's radio station
 			if selected, ok := m.artistList.SelectedItem().(artistItem); ok {
-				log.Debug(fmt.Sprintf("Playing artist radio: %s (ratingKey: %s)", selected.title, selected.ratingKey))
+				log.Debug("Playing artist radio: %s (ratingKey: %s)", selected.title, selected.ratingKey)
 				m.lastCommand = fmt.Sprintf("Playing %s Radio", selected.title)
-				return m, m.playArtistRadioCmd(selected.ratingKey)
+				return m, m.playArtistRadioCmd(strings.TrimSuffix(selected.title, " ★"), selected.ratingKey)
+			}
+			return m, nil
+
+		case "s":
+			return m, m.cycleArtistSort()
+
+		case "F":
+			// Add the library's most played artists to the favorites
+			return m, m.openTopArtistsInput()
+
+		case "N", "A":
+			// Queue the selected artist after the current track or at the end, staying in this panel
+			if selected, ok := m.artistList.SelectedItem().(artistItem); ok && selected.ratingKey != "" {
+				return m, m.enqueueCmd(selected.title, selected.ratingKey, false, key == "N")
+			}
+			return m, nil
+
+		case "d":
+			// Drill down into the selected artist's albums
+			if selected, ok := m.artistList.SelectedItem().(artistItem); ok && selected.ratingKey != "" {
+				log.Debug("Browsing albums for artist: %s (ratingKey: %s)", selected.title, selected.ratingKey)
+				m.initArtistAlbumBrowse(selected)
+				return m, m.fetchAlbumsCmd()
 			}
 			return m, nil
 
+		case "i":
+			// Show the selected artist's biography
+			return m, m.openArtistInfo()
+
 		case "R":
 			// Refresh artist list
 			m.status = "Refreshing artists..."
+			m.libraryCache.invalidate()
 			return m, m.fetchArtistsCmd()
 
 		default:
@@ -231,25 +380,36 @@ func (m *model) handleArtistBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case artistsFetchedMsg:
-		log.Debug(fmt.Sprintf("artistsFetchedMsg received with %d artists, error: %v", len(msg.artists), msg.err))
+		log.Debug("artistsFetchedMsg received with %d artists, error: %v", len(msg.artists), msg.err)
+		if msg.err == nil && msg.order != m.artistSort {
+			// Fetched before the order changed; the refetch in the new order is on its way
+			return m, nil
+		}
+		m.artistsLoading = false
 		if msg.err != nil {
-			errMsg := fmt.Sprintf("Error fetching artists: %v", msg.err)
+			m.jumpPending = false
+			if m.handleAuthError(msg.err) {
+				return m, nil
+			}
+			if errors.Is(msg.err, errNotMusicLibrary) {
+				m.artistList.SetItems(nil)
+			}
+			errMsg := libraryFetchStatus("artists", msg.err)
 			m.status = errMsg
-			log.Debug(errMsg)
+			log.Debug("%s", errMsg)
 			return m, nil
 		}
-
-		favSet := make(map[string]struct{})
-		for _, pItem := range m.playbackList.Items() {
-			pItem := pItem.(item)
-			favSet[pItem.GetMetadataKey()] = struct{}{}
+		if !msg.cached {
+			m.libraryCache.storeArtists(msg.library, msg.order, msg.start, msg.total, msg.artists)
 		}
 
+		favSet := m.getCurrentFavSet()
+
 		// Convert artists to list items
 		var items []list.Item
 		for i, artist := range msg.artists {
 			if i < 5 { // Only log first 5 artists to avoid log spam
-				log.Debug(fmt.Sprintf("Adding artist %d: %s (ratingKey: %s)", i+1, artist.Title, artist.RatingKey))
+				log.Debug("Adding artist %d: %s (ratingKey: %s)", i+1, artist.Title, artist.RatingKey)
 			}
 
 			fav := false
@@ -261,12 +421,15 @@ func (m *model) handleArtistBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 				title = fmt.Sprintf("%s ★", artist.Title)
 			}
 			items = append(items, artistItem{
-				title:     title,
-				ratingKey: artist.RatingKey,
+				title:      title,
+				ratingKey:  artist.RatingKey,
+				userRating: artist.UserRating,
+				viewCount:  artist.ViewCount,
+				addedAt:    artist.AddedAt,
 			})
 		}
 
-		log.Debug(fmt.Sprintf("Creating new list with %d items", len(items)))
+		log.Debug("Creating new list with %d items", len(items))
 		// Create a new list with the fetched items
 		// Preserve the current filter state
 		filterState := m.artistList.FilterState()
@@ -276,36 +439,53 @@ func (m *model) handleArtistBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 		delegate := list.NewDefaultDelegate()
 		delegate.ShowDescription = false // Don't show description
 
-		// Create new list with existing items
+		m.artistsTotal = msg.total
+
+		// Later pages are appended so the selection stays where the user scrolled to.
+		// The server sorted them, so they already follow the loaded ones.
+		if msg.start > 0 {
+			items = append(append([]list.Item(nil), m.artistList.Items()...), items...)
+			filterCmd := m.artistList.SetItems(items)
+			m.status = fmt.Sprintf("Loaded %d of %d artists", len(m.artistList.Items()), m.artistsTotal)
+			m.resumeJump(&m.artistList, m.moreArtists())
+			m.restoreArtistSelection()
+			return m, tea.Batch(filterCmd, m.fetchMoreArtistsCmd())
+		}
+
+		// Create new list with existing items. A partly loaded library keeps
+		// the server's order, which the pages still to come continue.
+		if len(items) >= m.artistsTotal {
+			sortArtistItems(items, m.artistSort)
+		}
 		m.artistList.SetItems(items)
 		m.artistList.ResetSelected()
+		m.restoreArtistSelection()
 
 		// Restore filter state if there was one
 		if filterState == list.Filtering {
 			m.artistList.ResetFilter()
 			m.artistList.FilterInput.SetValue(filterValue)
 		}
-		m.status = fmt.Sprintf("Loaded %d artists", len(msg.artists))
-		log.Debug(fmt.Sprintf("Updated model with new artist list. List has %d items", m.artistList.VisibleItems()))
-
-		// Force a redraw
-		return m, tea.Batch(tea.ClearScreen, func() tea.Msg { return nil })
-
-	case artistPlaybackMsg:
-		if msg.success {
-			m.lastCommand = "Artist Playback Started"
-			m.status = "Playback triggered successfully"
+		if len(msg.artists) == 0 {
+			m.status = "No artists in this library"
+		} else if len(msg.artists) < m.artistsTotal {
+			m.status = fmt.Sprintf("Loaded %d of %d artists", len(msg.artists), m.artistsTotal)
 		} else {
-			m.lastCommand = "Playback Failed"
-			m.status = fmt.Sprintf("Playback error: %v", msg.err)
+			m.status = fmt.Sprintf("Loaded %d artists", len(msg.artists))
 		}
-		// Return the updated model and no command
-		return m, nil
+		log.Debug("Updated model with new artist list. List has %d items", m.artistList.VisibleItems())
+
+		// Force a redraw
+		return m, tea.Batch(tea.ClearScreen, func() tea.Msg { return nil }, m.fetchMoreArtistsCmd())
 	}
 
 	// Update the artist list and get the command
 	var listCmd tea.Cmd
 	m.artistList, listCmd = m.artistList.Update(msg)
+	// Load the next page if the user scrolled near the end of the loaded artists
+	if moreCmd := m.fetchMoreArtistsCmd(); moreCmd != nil {
+		listCmd = tea.Batch(listCmd, moreCmd)
+	}
 	// Return the current model (as a pointer) and the command
 	return m, listCmd
 }
@@ -315,11 +495,14 @@ func (m *model) handleArtistBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 // =====================
 
 type artistItem struct {
-	title     string
-	ratingKey string
+	title      string
+	ratingKey  string
+	userRating float64 // Plex rating out of 10
+	viewCount  int
+	addedAt    int64
 }
 
-func (i artistItem) Title() string       { return i.title }
+func (i artistItem) Title() string       { return i.title + ratingMark(i.userRating) }
 func (i artistItem) Description() string { return "" } // No description needed
 // FilterValue implements list.Item
 func (i artistItem) FilterValue() string {
@@ -340,6 +523,6 @@ func (a *artistItem) ToggleFavorite() {
 var (
 	titleStyle      = lipgloss.NewStyle().MarginLeft(2)
 	itemStyle       = lipgloss.NewStyle().PaddingLeft(4)
-	helpStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Margin(1, 0, 0, 2)
+	helpStyle       = lipgloss.NewStyle().Foreground(theme.Muted).Margin(1, 0, 0, 2)
 	paginationStyle = list.DefaultStyles().PaginationStyle.PaddingLeft(4)
 )
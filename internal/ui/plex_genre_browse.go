@@ -0,0 +1,354 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"plexamp-tui/internal/config"
+	"plexamp-tui/internal/plex"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// =====================
+// Genre Browse Messages
+// =====================
+
+type genresFetchedMsg struct {
+	filter string // "genre" or "decade"
+	values []plex.PlexFilterValue
+	err    error
+}
+
+type genrePlaybackMsg struct {
+	success bool
+	err     error
+	played  config.HistoryItem // The item that was played, recorded in the history on success
+}
+
+// =====================
+// Genre Browse Functions
+// =====================
+
+// filterFavoriteKey builds the favorites key for a genre or decade. Filter
+// values are not metadata, so the key is prefixed to keep it from colliding
+// with rating keys.
+func filterFavoriteKey(filter, value string) string {
+	return filter + ":" + value
+}
+
+// parseFilterFavoriteKey splits a favorites key built by filterFavoriteKey
+func parseFilterFavoriteKey(key string) (string, string, bool) {
+	return strings.Cut(key, ":")
+}
+
+// fetchGenresCmd fetches the genres or decades of the current library
+func (m *model) fetchGenresCmd() tea.Cmd {
+	log.Debug("Fetching %ss...", m.genreFilter)
+	if m.config == nil {
+		return func() tea.Msg {
+			return genresFetchedMsg{err: fmt.Errorf("no config available")}
+		}
+	}
+
+	token := plexClient.GetPlexToken()
+	if token == "" {
+		return func() tea.Msg {
+			return genresFetchedMsg{err: fmt.Errorf("no Plex token found - run with --auth flag")}
+		}
+	}
+
+	if err := m.checkMusicLibrary(); err != nil {
+		filter := m.genreFilter
+		return func() tea.Msg {
+			return genresFetchedMsg{filter: filter, err: err}
+		}
+	}
+
+	serverAddr := m.config.ServerURL()
+	libraryID := m.config.PlexLibraryID
+	types := m.libraryTypes()
+	filter := m.genreFilter
+
+	return tea.Batch(m.startLoading("plex-genres"), func() tea.Msg {
+		var values []plex.PlexFilterValue
+		var err error
+		if filter == "decade" {
+			values, err = plexClient.FetchDecades(serverAddr, libraryID, types, token)
+		} else {
+			values, err = plexClient.FetchGenres(serverAddr, libraryID, types, token)
+		}
+		return genresFetchedMsg{filter: filter, values: values, err: err}
+	})
+}
+
+// playGenreCmd plays every track in a genre or decade
+func (m *model) playGenreCmd(filter, name, value string) tea.Cmd {
+	if m.selected == "" {
+		return func() tea.Msg {
+			return genrePlaybackMsg{success: false, err: errNoPlayer}
+		}
+	}
+
+	if m.config == nil {
+		return func() tea.Msg {
+			return genrePlaybackMsg{success: false, err: fmt.Errorf("no config available")}
+		}
+	}
+
+	serverIP := m.selected
+	serverID := m.config.ServerID
+	libraryID := m.config.PlexLibraryID
+	shuffle := m.shuffle
+
+	return func() tea.Msg {
+		err := PlayLibraryFilter(serverIP, serverID, libraryID, filter, value, shuffle)
+		if err != nil {
+			return genrePlaybackMsg{success: false, err: err}
+		}
+		return genrePlaybackMsg{success: true, played: config.HistoryItem{Name: name, Type: filter, MetadataKey: filterFavoriteKey(filter, value)}}
+	}
+}
+
+// initGenreBrowse initializes the genre browse panel
+func (m *model) initGenreBrowse() {
+	log.Debug("Initializing genre browse")
+	m.panelMode = "plex-genres"
+	if m.genreFilter == "" {
+		m.genreFilter = "genre"
+	}
+	m.status = fmt.Sprintf("Loading %ss...", m.genreFilter)
+
+	items := []list.Item{genreItem{title: m.status}}
+	// Create a new default delegate with custom styling
+	delegate := list.NewDefaultDelegate()
+	delegate.ShowDescription = false // Don't show description
+
+	m.genreList = list.New(items, delegate, 0, 0)
+	m.genreList.Title = genreListTitle(m.genreFilter)
+	m.genreList.SetShowFilter(true)
+	m.genreList.SetFilteringEnabled(true)
+	m.genreList.Styles.Title = titleStyle
+	m.genreList.SetStatusBarItemName(m.genreFilter, m.genreFilter+"s")
+	m.genreList.Styles.PaginationStyle = paginationStyle
+	m.genreList.Styles.HelpStyle = helpStyle
+	m.genreList.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(
+				key.WithKeys("f"),
+				key.WithHelp("f", "favs"),
+			),
+			key.NewBinding(
+				key.WithKeys("d"),
+				key.WithHelp("d", "albums"),
+			),
+			key.NewBinding(
+				key.WithKeys("t"),
+				key.WithHelp("t", "genre/decade"),
+			),
+		}
+	}
+	m.genreList.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(
+				key.WithKeys("enter"),
+				key.WithHelp("enter", "Play Genre/Decade"),
+			),
+			key.NewBinding(
+				key.WithKeys("f"),
+				key.WithHelp("f", "Add/Remove from Favorites"),
+			),
+			key.NewBinding(
+				key.WithKeys("d"),
+				key.WithHelp("d", "Browse Albums"),
+			),
+			key.NewBinding(
+				key.WithKeys("t"),
+				key.WithHelp("t", "Switch Genres/Decades"),
+			),
+			key.NewBinding(
+				key.WithKeys("R"),
+				key.WithHelp("R", "Refresh List"),
+			),
+		}
+	}
+
+	if m.width > 0 && m.height > 0 {
+		m.sizeList(&m.genreList)
+	}
+}
+
+// genreListTitle returns the list title for a filter
+func genreListTitle(filter string) string {
+	if filter == "decade" {
+		return "Plex Decades"
+	}
+	return "Plex Genres"
+}
+
+func (m *model) openGenreBrowser() (tea.Cmd, bool) {
+	if m.plexAuthenticated && m.config != nil {
+		m.initGenreBrowse()
+		return m.fetchGenresCmd(), true
+	} else {
+		m.status = "Plex authentication required (run with --auth)"
+	}
+	return nil, false
+}
+
+// handleGenreBrowseUpdate handles updates when in genre browse mode
+func (m *model) handleGenreBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	log.Debug("handleGenreBrowseUpdate received message: %T", msg)
+
+	// If we're in filtering mode, let the list handle the input
+	if m.genreList.FilterState() == list.Filtering {
+		var cmd tea.Cmd
+		m.genreList, cmd = m.genreList.Update(msg)
+		return m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		key := msg.String()
+
+		switch key {
+		case "esc", "q":
+			// Return to playback panel
+			m.panelMode = "playback"
+			m.status = ""
+			return m, nil
+
+		case "enter":
+			// Play every track in the selected genre or decade
+			if selected, ok := m.genreList.SelectedItem().(genreItem); ok && selected.key != "" {
+				name := strings.TrimSuffix(selected.title, " ★")
+				log.Debug("Playing %s: %s (key: %s)", m.genreFilter, name, selected.key)
+				m.lastCommand = fmt.Sprintf("Playing %s", name)
+				return m, m.playGenreCmd(m.genreFilter, name, selected.key)
+			}
+			return m, nil
+
+		case "f":
+			// add or remove selected genre from favorites (playback list)
+			if selected, ok := m.genreList.SelectedItem().(genreItem); ok && selected.key != "" {
+				log.Debug("Toggling favorite for %s: %s (key: %s)", m.genreFilter, selected.title, selected.key)
+				m.lastCommand = fmt.Sprintf("Toggling favorite for %s", selected.title)
+				_, cmd := m.addRemoveFavorite(strings.TrimSuffix(selected.title, " ★"), filterFavoriteKey(m.genreFilter, selected.key), m.genreFilter)
+				selected.ToggleFavorite()
+				// Update the item in the list
+				m.genreList.SetItem(m.genreList.Index(), selected)
+				return m, cmd
+			}
+			return m, nil
+
+		case "d":
+			// Drill down into the albums of the selected genre or decade
+			if selected, ok := m.genreList.SelectedItem().(genreItem); ok && selected.key != "" {
+				log.Debug("Browsing albums for %s: %s (key: %s)", m.genreFilter, selected.title, selected.key)
+				m.initFilterAlbumBrowse(m.genreFilter, selected)
+				return m, m.fetchAlbumsCmd()
+			}
+			return m, nil
+
+		case "t":
+			// Switch between genres and decades
+			if m.genreFilter == "genre" {
+				m.genreFilter = "decade"
+			} else {
+				m.genreFilter = "genre"
+			}
+			m.initGenreBrowse()
+			return m, m.fetchGenresCmd()
+
+		case "R":
+			// Refresh genre list
+			m.status = fmt.Sprintf("Refreshing %ss...", m.genreFilter)
+			return m, m.fetchGenresCmd()
+
+		default:
+
+			// Otherwise try the common controls
+			if cmd, handled := m.handleControl(key); handled {
+				return m, cmd
+			}
+		}
+
+	case genresFetchedMsg:
+		log.Debug("genresFetchedMsg received with %d values, error: %v", len(msg.values), msg.err)
+		// Ignore a response for the filter that was switched away from
+		if msg.filter != m.genreFilter {
+			return m, nil
+		}
+		if msg.err != nil {
+			if m.handleAuthError(msg.err) {
+				return m, nil
+			}
+			if errors.Is(msg.err, errNotMusicLibrary) {
+				m.genreList.SetItems(nil)
+			}
+			errMsg := libraryFetchStatus(msg.filter+"s", msg.err)
+			m.status = errMsg
+			log.Debug("%s", errMsg)
+			return m, nil
+		}
+
+		favSet := m.getCurrentFavSet()
+
+		// Convert genres to list items
+		var items []list.Item
+		for _, value := range msg.values {
+			title := value.Title
+			if _, exists := favSet[filterFavoriteKey(msg.filter, value.Key)]; exists {
+				title = fmt.Sprintf("%s ★", value.Title)
+			}
+			items = append(items, genreItem{
+				title: title,
+				key:   value.Key,
+			})
+		}
+
+		m.genreList.SetItems(items)
+		m.genreList.ResetSelected()
+		if len(items) == 0 {
+			m.status = fmt.Sprintf("No %ss in this library", msg.filter)
+		} else {
+			m.status = fmt.Sprintf("Loaded %d %ss", len(msg.values), msg.filter)
+		}
+
+		// Force a redraw
+		return m, tea.Batch(tea.ClearScreen, func() tea.Msg { return nil })
+	}
+
+	// Update the genre list and get the command
+	var listCmd tea.Cmd
+	m.genreList, listCmd = m.genreList.Update(msg)
+	return m, listCmd
+}
+
+// =====================
+// Genre Item Type
+// =====================
+
+type genreItem struct {
+	title string
+	key   string
+}
+
+func (i genreItem) Title() string       { return i.title }
+func (i genreItem) Description() string { return "" } // No description needed
+// FilterValue implements list.Item
+func (i genreItem) FilterValue() string {
+	return i.title
+}
+
+func (g *genreItem) ToggleFavorite() {
+	// If title already has a star, remove it
+	if strings.HasSuffix(g.title, " ★") {
+		g.title = strings.TrimSuffix(g.title, " ★")
+	} else {
+		g.title = fmt.Sprintf("%s ★", g.title)
+	}
+}
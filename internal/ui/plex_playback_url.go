@@ -1,11 +1,15 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
 
+	"plexamp-tui/internal/config"
+	"plexamp-tui/internal/plexhttp"
+
 	"github.com/google/uuid"
 )
 
@@ -18,6 +22,17 @@ const (
 	plexURIPrefix     = "server://%s/com.plexapp.plugins.library/library/metadata/%s"
 )
 
+// errNoServer is returned instead of sending a play request built from an empty or placeholder server ID
+var errNoServer = errors.New("select a server first (press 6)")
+
+// requireServer checks that a real server has been selected before building playback URLs from its ID
+func requireServer(serverID string) error {
+	if serverID == "" || config.IsPlaceholder(serverID) {
+		return errNoServer
+	}
+	return nil
+}
+
 // PlaybackURLBuilder handles building and sending Plex playback URLs
 type PlaybackURLBuilder struct {
 	serverID string
@@ -45,15 +60,39 @@ func (b *PlaybackURLBuilder) BuildPlayQueueURL(metadataID string) string {
 	return u
 }
 
-// BuildArtistRadioURL builds a URL for playing artist radio/station
-// This requires a station UUID in addition to the metadata ID
-func (b *PlaybackURLBuilder) BuildArtistRadioURL(metadataID, stationUUID string) string {
+// buildStationURL builds a URL for a radio station seeded from any library item
+func (b *PlaybackURLBuilder) buildStationURL(metadataID, stationUUID string) string {
 	uri := fmt.Sprintf(plexURIPrefix+"/station/%s", b.serverID, metadataID, stationUUID)
 	u := fmt.Sprintf("%s/player/playback/playMedia?type=10&type=audio&uri=%s",
 		plexListenBaseURL, url.QueryEscape(uri))
 	return u
 }
 
+// BuildArtistRadioURL builds a URL for playing artist radio/station
+// This requires a station UUID in addition to the metadata ID
+func (b *PlaybackURLBuilder) BuildArtistRadioURL(metadataID, stationUUID string) string {
+	return b.buildStationURL(metadataID, stationUUID)
+}
+
+// BuildAlbumRadioURL builds a URL for a station of music similar to an album
+func (b *PlaybackURLBuilder) BuildAlbumRadioURL(metadataID, stationUUID string) string {
+	return b.buildStationURL(metadataID, stationUUID)
+}
+
+// BuildTrackRadioURL builds a URL for a station of music similar to a track
+func (b *PlaybackURLBuilder) BuildTrackRadioURL(metadataID, stationUUID string) string {
+	return b.buildStationURL(metadataID, stationUUID)
+}
+
+// BuildLibraryFilterURL builds a URL for playing every track in a library
+// that matches a filter, such as genre=123 or decade=1990
+func (b *PlaybackURLBuilder) BuildLibraryFilterURL(libraryID, filter, value string) string {
+	uri := fmt.Sprintf("server://%s/com.plexapp.plugins.library/library/sections/%s/all?type=10&%s=%s",
+		b.serverID, libraryID, url.QueryEscape(filter), url.QueryEscape(value))
+	u := fmt.Sprintf("%s/player/playback/createPlayQueue?uri=%s", plexListenBaseURL, url.QueryEscape(uri))
+	return u
+}
+
 // ApplyShuffle modifies a URL to add or remove the shuffle parameter
 func ApplyShuffle(urlStr string, shuffle bool) (string, error) {
 	u, err := url.Parse(urlStr)
@@ -85,16 +124,16 @@ func SendPlaybackURL(serverIP, fullURL string, shuffle bool) error {
 	localURL := strings.Replace(modifiedURL, "https://listen.plex.tv", fmt.Sprintf("http://%s:32500", serverIP), 1)
 	localURL = strings.Replace(localURL, "http://listen.plex.tv", fmt.Sprintf("http://%s:32500", serverIP), 1)
 
-	log.Debug(fmt.Sprintf("Sending playback URL: %s", localURL))
+	log.Debug("Sending playback URL: %s", plexhttp.RedactToken(localURL))
 
-	resp, err := http.Get(localURL)
+	resp, err := plexClient.GetWithRetry(localURL)
 	if err != nil {
-		log.Debug(fmt.Sprintf("Request error: %v", err))
+		log.Debug("Request error: %v", err)
 		return fmt.Errorf("failed to connect to %s: %w", serverIP, err)
 	}
 	defer resp.Body.Close()
 
-	log.Debug(fmt.Sprintf("Response status: %d", resp.StatusCode))
+	log.Debug("Response status: %d", resp.StatusCode)
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
 		return fmt.Errorf("server returned status %d", resp.StatusCode)
@@ -106,26 +145,115 @@ func SendPlaybackURL(serverIP, fullURL string, shuffle bool) error {
 // PlayMetadata plays a specific metadata item (track, album, artist, etc.)
 // This is a convenience function that builds the URL and sends it
 func PlayMetadata(serverIP, serverID, metadataID string, shuffle bool) error {
+	if err := requireServer(serverID); err != nil {
+		return err
+	}
 	builder := NewPlaybackURLBuilder(serverID)
 	playbackURL := builder.BuildPlayQueueURL(metadataID)
 	return SendPlaybackURL(serverIP, playbackURL, shuffle)
 }
 
+// PlayMetadataList plays several metadata items in one play queue, in order
+// unless shuffled. Plex accepts a comma separated list of keys in the uri.
+func PlayMetadataList(serverIP, serverID string, metadataIDs []string, shuffle bool) error {
+	if err := requireServer(serverID); err != nil {
+		return err
+	}
+	builder := NewPlaybackURLBuilder(serverID)
+	playbackURL := builder.BuildPlayQueueURL(strings.Join(metadataIDs, ","))
+	return SendPlaybackURL(serverIP, playbackURL, shuffle)
+}
+
 // PlayArtistRadio plays an artist radio station
 // This is a convenience function that builds the URL and sends it
 // It generates a new UUID for each call to ensure a fresh radio station
 func PlayArtistRadio(serverIP, serverID, metadataID string, shuffle bool) error {
-	// Generate a new UUID for the station
+	if err := requireServer(serverID); err != nil {
+		return err
+	}
+	return playRadio(serverIP, metadataID, shuffle, NewPlaybackURLBuilder(serverID).BuildArtistRadioURL)
+}
+
+// PlayAlbumRadio plays a station seeded from an album
+func PlayAlbumRadio(serverIP, serverID, metadataID string, shuffle bool) error {
+	if err := requireServer(serverID); err != nil {
+		return err
+	}
+	return playRadio(serverIP, metadataID, shuffle, NewPlaybackURLBuilder(serverID).BuildAlbumRadioURL)
+}
+
+// PlayTrackRadio plays a station seeded from a track
+func PlayTrackRadio(serverIP, serverID, metadataID string, shuffle bool) error {
+	if err := requireServer(serverID); err != nil {
+		return err
+	}
+	return playRadio(serverIP, metadataID, shuffle, NewPlaybackURLBuilder(serverID).BuildTrackRadioURL)
+}
+
+// playRadio builds a station URL with a fresh UUID, so every call starts a new station, and sends it
+func playRadio(serverIP, metadataID string, shuffle bool, build func(metadataID, stationUUID string) string) error {
 	stationUUID := uuid.New().String()
+	return SendPlaybackURL(serverIP, build(metadataID, stationUUID), shuffle)
+}
+
+// PlayLibraryFilter plays every track in a library matching a filter
+// This is a convenience function that builds the URL and sends it
+func PlayLibraryFilter(serverIP, serverID, libraryID, filter, value string, shuffle bool) error {
+	if err := requireServer(serverID); err != nil {
+		return err
+	}
 	builder := NewPlaybackURLBuilder(serverID)
-	playbackURL := builder.BuildArtistRadioURL(metadataID, stationUUID)
+	playbackURL := builder.BuildLibraryFilterURL(libraryID, filter, value)
 	return SendPlaybackURL(serverIP, playbackURL, shuffle)
 }
 
 // PlayPlaylist plays a specific playlist
 // This is a convenience function that builds the URL and sends it
 func PlayPlaylist(serverIP, serverID, metadataID string, shuffle bool) error {
+	if err := requireServer(serverID); err != nil {
+		return err
+	}
 	builder := NewPlaybackURLBuilder(serverID)
 	playbackURL := builder.BuildPlaylistURL(metadataID)
 	return SendPlaybackURL(serverIP, playbackURL, shuffle)
 }
+
+// AddToPlayQueue adds a metadata item (track, album, artist) to the play
+// queue the player is working through, either right after the current
+// track or at the end, and tells the player to reload the queue
+func AddToPlayQueue(serverIP, serverAddr, serverID, playQueueID, metadataID string, next bool) error {
+	if err := requireServer(serverID); err != nil {
+		return err
+	}
+	source := url.Values{}
+	source.Set("uri", fmt.Sprintf(plexURIPrefix, serverID, metadataID))
+	return addToPlayQueue(serverIP, serverAddr, playQueueID, source, next)
+}
+
+// AddPlaylistToPlayQueue adds every track of a playlist to the play queue
+// the player is working through
+func AddPlaylistToPlayQueue(serverIP, serverAddr, playQueueID, playlistID string, next bool) error {
+	source := url.Values{}
+	source.Set("playlistID", playlistID)
+	return addToPlayQueue(serverIP, serverAddr, playQueueID, source, next)
+}
+
+// addToPlayQueue updates the queue on the server, then has the player pick up the change
+func addToPlayQueue(serverIP, serverAddr, playQueueID string, source url.Values, next bool) error {
+	if err := plexClient.AddToPlayQueue(serverAddr, playQueueID, source, next, plexClient.GetPlexToken()); err != nil {
+		return err
+	}
+
+	refreshURL := fmt.Sprintf("http://%s:32500/player/playback/refreshPlayQueue?playQueueID=%s&commandID=1&type=music",
+		serverIP, url.QueryEscape(playQueueID))
+	resp, err := plexClient.GetWithRetry(refreshURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", serverIP, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("player returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -1,13 +1,31 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"time"
+
+	"plexamp-tui/internal/config"
+	"plexamp-tui/internal/discovery"
 	"plexamp-tui/internal/plex"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// errNoPlayer is returned by playback commands attempted with no player selected
+var errNoPlayer = errors.New("no player selected")
+
+// noPlayerStatus tells the user to choose a player, naming the key that
+// opens the player list
+func noPlayerStatus() string {
+	if keys := boundKeys(config.ActionPlayers); keys != "" {
+		return fmt.Sprintf("No player selected — press %s to choose", keys)
+	}
+	return "No player selected — choose one in the player list"
+}
+
 type playerSelectMsg struct {
 	success bool
 	err     error
@@ -21,6 +39,22 @@ type playerItem struct {
 	address          string
 	local            string
 	port             string
+	connections      []plex.PlexConnection // Every connection of the player, local first
+	connIndex        int                   // Connection address and local currently refer to
+}
+
+// withConnection returns the item pointed at its i-th connection
+func (i playerItem) withConnection(index int) playerItem {
+	if len(i.connections) == 0 {
+		return i
+	}
+	index %= len(i.connections)
+	connection := i.connections[index]
+	i.connIndex = index
+	i.address = connection.Address
+	i.local = connection.Local
+	i.port = connection.Port
+	return i
 }
 
 // playersFetchedMsg is a message containing fetched players
@@ -29,8 +63,23 @@ type playersFetchedMsg struct {
 	err     error
 }
 
+// localDiscoveryTimeout bounds the search for players on the local network
+// that runs alongside each player fetch
+const localDiscoveryTimeout = 2 * time.Second
+
+// localPlayerMsg carries a player found on the local network, or the end of
+// the search when ok is false
+type localPlayerMsg struct {
+	player  discovery.Player
+	ok      bool
+	players <-chan discovery.Player // The search to wait on for the next player
+}
+
 // Title returns the playlist title
 func (i playerItem) Title() string {
+	if len(i.connections) > 1 {
+		return fmt.Sprintf("%s - %s (%d/%d)", i.title, i.address, i.connIndex+1, len(i.connections))
+	}
 	return fmt.Sprintf("%s - %s", i.title, i.address)
 }
 
@@ -45,29 +94,133 @@ func (i playerItem) FilterValue() string {
 // fetchPlayersCmd fetches players from the Plex server
 func (m *model) fetchPlayersCmd() tea.Cmd {
 	log.Debug("Fetching players...")
-	// ✅ Reapply sizing
-	footerHeight := 3 // or dynamically measure your footer
-	availableHeight := m.height - footerHeight - 5
-	m.playerList.SetSize(m.width/2-4, availableHeight)
+	m.sizeList(&m.playerList)
 	if m.config == nil {
 		return func() tea.Msg {
 			return playersFetchedMsg{err: fmt.Errorf("no config available")}
 		}
 	}
 
+	// Players on the local network are found without a token, so look for
+	// them even when plex.tv can't be asked
 	token := plexClient.GetPlexToken()
 	if token == "" {
-		return func() tea.Msg {
+		return tea.Batch(func() tea.Msg {
 			return playersFetchedMsg{err: fmt.Errorf("no Plex token found - run with --auth flag")}
-		}
+		}, m.discoverLocalPlayersCmd())
 	}
 
-	return func() tea.Msg {
+	return tea.Batch(m.startLoading("plex-players"), func() tea.Msg {
 		players, err := plexClient.GetPlexPlayers()
 		return playersFetchedMsg{players: players, err: err}
+	}, m.discoverLocalPlayersCmd())
+}
+
+// showPlayers fills the player list with the players on the account, merged
+// with the ones found on the local network
+func (m *model) showPlayers(players []plex.PlexConnectionSelection) {
+	// Convert players to list items
+	var items []list.Item
+	for i, player := range players {
+		if i < 5 { // Only log first 5 servers to avoid log spam
+			log.Debug("Adding player %d: %s (ratingKey: %s)", i+1, player.Name, player.ClientIdentifier)
+		}
+		item := playerItem{
+			title:            player.Name,
+			clientIdentifier: player.ClientIdentifier,
+			address:          player.Address,
+			local:            player.Local,
+			port:             player.Port,
+			connections:      player.Connections,
+		}
+		// Show the connection in use for the selected player so c cycles on from it
+		for j, connection := range player.Connections {
+			if connection.Address == m.selected {
+				item = item.withConnection(j)
+				break
+			}
+		}
+		items = append(items, item)
+	}
+	for _, player := range m.localPlayers {
+		items = mergeLocalPlayer(items, player)
+	}
+	m.playerList.SetItems(items)
+	m.playerList.ResetSelected()
+}
+
+// discoverLocalPlayersCmd starts a GDM search for players on the local network
+func (m *model) discoverLocalPlayersCmd() tea.Cmd {
+	m.localPlayers = nil
+	return func() tea.Msg {
+		players, err := discovery.Search(localDiscoveryTimeout)
+		if err != nil {
+			log.Debug("Local player discovery failed: %v", err)
+			return nil
+		}
+		return waitForLocalPlayer(players)
+	}
+}
+
+// waitForLocalPlayerCmd waits for the next player the search finds
+func waitForLocalPlayerCmd(players <-chan discovery.Player) tea.Cmd {
+	return func() tea.Msg {
+		return waitForLocalPlayer(players)
 	}
 }
 
+func waitForLocalPlayer(players <-chan discovery.Player) tea.Msg {
+	player, ok := <-players
+	return localPlayerMsg{player: player, ok: ok, players: players}
+}
+
+// handleLocalPlayer remembers a player found on the local network, so a
+// plex.tv fetch finishing later keeps it, and adds it to the player list
+func (m *model) handleLocalPlayer(msg localPlayerMsg) tea.Cmd {
+	if !msg.ok {
+		return nil
+	}
+	log.Debug("Found %s (%s) at %s on the local network", msg.player.Name, msg.player.Product, msg.player.Address)
+	m.localPlayers = append(m.localPlayers, msg.player)
+	if m.panelMode == "plex-players" {
+		items := append([]list.Item(nil), m.playerList.Items()...)
+		m.playerList.SetItems(mergeLocalPlayer(items, msg.player))
+		m.status = fmt.Sprintf("Found %s on the local network", msg.player.Name)
+	}
+	return waitForLocalPlayerCmd(msg.players)
+}
+
+// mergeLocalPlayer adds a player found on the local network to the player
+// items. A player plex.tv already listed gets the local address as its first
+// connection, keeping the connection it is using now; any other player is
+// added with just that address.
+func mergeLocalPlayer(items []list.Item, player discovery.Player) []list.Item {
+	local := plex.PlexConnection{Protocol: "http", Address: player.Address, Port: player.Port, Local: "1"}
+	for i, listItem := range items {
+		existing, ok := listItem.(playerItem)
+		if !ok || existing.clientIdentifier != player.ClientIdentifier {
+			continue
+		}
+		for _, connection := range existing.connections {
+			if connection.Address == player.Address {
+				return items
+			}
+		}
+		existing.connections = append([]plex.PlexConnection{local}, existing.connections...)
+		items[i] = existing.withConnection(existing.connIndex + 1)
+		return items
+	}
+
+	return append(items, playerItem{
+		title:            player.Name,
+		clientIdentifier: player.ClientIdentifier,
+		address:          player.Address,
+		local:            local.Local,
+		port:             player.Port,
+		connections:      []plex.PlexConnection{local},
+	})
+}
+
 // initPlayerBrowse creates a new player browser
 func (m *model) initPlayerBrowse() {
 	m.panelMode = "plex-players"
@@ -77,20 +230,41 @@ func (m *model) initPlayerBrowse() {
 	delegate := list.NewDefaultDelegate()
 	delegate.ShowDescription = false
 
-	items := []list.Item{playerItem{title: "Loading players..."}}
-
 	// Create the list with empty items for now
-	m.playerList = list.New(items, delegate, 0, 0)
+	m.playerList = list.New(nil, delegate, 0, 0)
 	m.playerList.Title = "Plex Players"
 	m.playerList.SetShowFilter(true)
 	m.playerList.SetFilteringEnabled(true)
 	m.playerList.Styles.Title = titleStyle
 	m.playerList.Styles.PaginationStyle = paginationStyle
 	m.playerList.Styles.HelpStyle = helpStyle
+	m.playerList.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(
+				key.WithKeys("c"),
+				key.WithHelp("c", "next connection"),
+			),
+		}
+	}
+	m.playerList.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(
+				key.WithKeys("c"),
+				key.WithHelp("c", "Use Next Connection"),
+			),
+			key.NewBinding(
+				key.WithKeys("R"),
+				key.WithHelp("R", "Refresh Players"),
+			),
+		}
+	}
 	if m.width > 0 && m.height > 0 {
-		m.playerList.SetSize(m.width/2-4, m.height-4)
+		m.sizeList(&m.playerList)
 	}
 }
+
+// selectPlayerCmd selects a player, first probing its connections when it
+// has several and switching to the first one that answers
 func (m *model) selectPlayerCmd(player playerItem) tea.Cmd {
 	if m.config == nil {
 		return func() tea.Msg {
@@ -98,8 +272,19 @@ func (m *model) selectPlayerCmd(player playerItem) tea.Cmd {
 		}
 	}
 
-	// Return a message with the player information
 	return func() tea.Msg {
+		if len(player.connections) > 1 {
+			connection, ok := plexClient.PickPlayerConnection(player.title, player.connections)
+			if !ok {
+				log.Debug("No connection of %s answered, using %s", player.title, connection.Address)
+			}
+			for i, c := range player.connections {
+				if c == connection {
+					player = player.withConnection(i)
+					break
+				}
+			}
+		}
 		return playerSelectMsg{
 			success: true,
 			player:  player,
@@ -107,8 +292,26 @@ func (m *model) selectPlayerCmd(player playerItem) tea.Cmd {
 	}
 }
 
+// cyclePlayerConnection switches the highlighted player to its next
+// connection and selects it without probing, for when the automatic pick
+// chose a slow one
+func (m *model) cyclePlayerConnection() tea.Cmd {
+	selected, ok := m.playerList.SelectedItem().(playerItem)
+	if !ok || len(selected.connections) < 2 {
+		m.status = "Player has no other connections"
+		return nil
+	}
+	selected = selected.withConnection(selected.connIndex + 1)
+	m.playerList.SetItem(m.playerList.GlobalIndex(), selected)
+	m.lastCommand = fmt.Sprintf("Using %s for %s", selected.address, selected.title)
+
+	return func() tea.Msg {
+		return playerSelectMsg{success: true, player: selected}
+	}
+}
+
 func (m *model) handlePlayerBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
-	log.Debug(fmt.Sprintf("handlePlayerBrowseUpdate received message: %T", msg))
+	log.Debug("handlePlayerBrowseUpdate received message: %T", msg)
 
 	// If we're in filtering mode, let the list handle the input
 	if m.playerList.FilterState() == list.Filtering {
@@ -131,12 +334,15 @@ func (m *model) handlePlayerBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 			// Select Server
 			if selected, ok := m.playerList.SelectedItem().(playerItem); ok {
-				log.Debug(fmt.Sprintf("Selecting player: %s (clientIdentifier: %s)", selected.title, selected.clientIdentifier))
+				log.Debug("Selecting player: %s (clientIdentifier: %s)", selected.title, selected.clientIdentifier)
 				m.lastCommand = fmt.Sprintf("Selecting %s", selected.title)
 				return m, m.selectPlayerCmd(selected)
 			}
 			return m, nil
 
+		case "c":
+			return m, m.cyclePlayerConnection()
+
 		case "R":
 			// Refresh player list
 			m.status = "Refreshing players..."
@@ -151,50 +357,30 @@ func (m *model) handlePlayerBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case playersFetchedMsg:
-		log.Debug(fmt.Sprintf("playersFetchedMsg received with %d players, error: %v", len(msg.players), msg.err))
+		log.Debug("playersFetchedMsg received with %d players, error: %v", len(msg.players), msg.err)
 		if msg.err != nil {
+			if m.handleAuthError(msg.err) {
+				return m, nil
+			}
 			errMsg := fmt.Sprintf("Error fetching players: %v", msg.err)
 			m.status = errMsg
-			log.Debug(errMsg)
+			log.Debug("%s", errMsg)
 			return m, nil
 		}
 
-		// Convert servers to list items
-		var items []list.Item
-		for i, player := range msg.players {
-			if i < 5 { // Only log first 5 servers to avoid log spam
-				log.Debug(fmt.Sprintf("Adding player %d: %s (ratingKey: %s)", i+1, player.Name, player.ClientIdentifier))
-			}
-			items = append(items, playerItem{
-				title:            player.Name,
-				clientIdentifier: player.ClientIdentifier,
-				address:          player.Address,
-				local:            player.Local,
-				port:             player.Port,
-			})
-		}
-
-		log.Debug(fmt.Sprintf("Creating new list with %d items", len(items)))
-		// Create a new list with the fetched items
 		// Preserve the current filter state
 		filterState := m.playerList.FilterState()
 		filterValue := m.playerList.FilterValue()
 
-		// Create a new default delegate with custom styling
-		delegate := list.NewDefaultDelegate()
-		delegate.ShowDescription = false // Don't show description
-
-		// Create new list with existing items
-		m.playerList.SetItems(items)
-		m.playerList.ResetSelected()
+		m.showPlayers(msg.players)
 
 		// Restore filter state if there was one
 		if filterState == list.Filtering {
 			m.playerList.ResetFilter()
 			m.playerList.FilterInput.SetValue(filterValue)
 		}
-		m.status = fmt.Sprintf("Loaded %d players", len(msg.players))
-		log.Debug(fmt.Sprintf("Updated model with new player list. List has %d items", m.playerList.VisibleItems()))
+		m.status = fmt.Sprintf("Loaded %d players", len(m.playerList.Items()))
+		log.Debug("Updated model with new player list. List has %d items", m.playerList.VisibleItems())
 
 		// Force a redraw
 		return m, tea.Batch(tea.ClearScreen, func() tea.Msg { return nil })
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"plexamp-tui/internal/config"
 	"plexamp-tui/internal/plex"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -14,6 +15,7 @@ import (
 type playlistPlaybackMsg struct {
 	success bool
 	err     error
+	played  config.HistoryItem // The item that was played, recorded in the history on success
 }
 
 // playlistItem represents a playlist in the list
@@ -58,10 +60,7 @@ func (p *playlistItem) ToggleFavorite() {
 // fetchPlaylistsCmd fetches playlists from the Plex server
 func (m *model) fetchPlaylistsCmd() tea.Cmd {
 	log.Debug("Fetching playlists...")
-	// ✅ Reapply sizing
-	footerHeight := 3 // or dynamically measure your footer
-	availableHeight := m.height - footerHeight - 5
-	m.playlistList.SetSize(m.width/2-4, availableHeight)
+	m.sizeList(&m.playlistList)
 	if m.config == nil {
 		return func() tea.Msg {
 			return playlistsFetchedMsg{err: fmt.Errorf("no config available")}
@@ -75,12 +74,12 @@ func (m *model) fetchPlaylistsCmd() tea.Cmd {
 		}
 	}
 
-	serverAddr := m.config.PlexServerAddr
+	serverAddr := m.config.ServerURL()
 
-	return func() tea.Msg {
+	return tea.Batch(m.startLoading("plex-playlists"), func() tea.Msg {
 		playlists, err := plexClient.FetchPlaylists(serverAddr, token)
 		return playlistsFetchedMsg{playlists: playlists, err: err}
-	}
+	})
 }
 
 // initPlaylistBrowse creates a new playlist browser
@@ -92,10 +91,8 @@ func (m *model) initPlaylistBrowse() {
 	delegate := list.NewDefaultDelegate()
 	delegate.ShowDescription = false
 
-	items := []list.Item{playlistItem{title: "Loading playlists..."}}
-
 	// Create the list with empty items for now
-	m.playlistList = list.New(items, delegate, 0, 0)
+	m.playlistList = list.New(nil, delegate, 0, 0)
 	m.playlistList.Title = "Plex Playlists"
 	m.playlistList.SetShowFilter(true)
 	m.playlistList.SetFilteringEnabled(true)
@@ -112,7 +109,7 @@ func (m *model) initPlaylistBrowse() {
 		}
 	}
 	m.playlistList.AdditionalFullHelpKeys = func() []key.Binding {
-		return []key.Binding{
+		return append([]key.Binding{
 			key.NewBinding(
 				key.WithKeys("f"),
 				key.WithHelp("f", "Add/Remove from Favorites"),
@@ -121,17 +118,18 @@ func (m *model) initPlaylistBrowse() {
 				key.WithKeys("R"),
 				key.WithHelp("R", "Refresh Playlists"),
 			),
-		}
+			jumpHelpKey(),
+		}, enqueueHelpKeys()...)
 	}
 	if m.width > 0 && m.height > 0 {
-		m.playlistList.SetSize(m.width/2-4, m.height-4)
+		m.sizeList(&m.playlistList)
 	}
 }
 
-func (m *model) playPlaylistCmd(ratingKey string) tea.Cmd {
+func (m *model) playPlaylistCmd(name, ratingKey string) tea.Cmd {
 	if m.selected == "" {
 		return func() tea.Msg {
-			return playlistPlaybackMsg{success: false, err: fmt.Errorf("no server selected")}
+			return playlistPlaybackMsg{success: false, err: errNoPlayer}
 		}
 	}
 
@@ -150,12 +148,12 @@ func (m *model) playPlaylistCmd(ratingKey string) tea.Cmd {
 		if err != nil {
 			return playlistPlaybackMsg{success: false, err: err}
 		}
-		return playlistPlaybackMsg{success: true}
+		return playlistPlaybackMsg{success: true, played: config.HistoryItem{Name: name, Type: "playlist", MetadataKey: ratingKey}}
 	}
 }
 
 func (m *model) handlePlaylistBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
-	log.Debug(fmt.Sprintf("handlePlaylistBrowseUpdate received message: %T", msg))
+	log.Debug("handlePlaylistBrowseUpdate received message: %T", msg)
 
 	// If we're in filtering mode, let the list handle the input
 	if m.playlistList.FilterState() == list.Filtering {
@@ -167,6 +165,9 @@ func (m *model) handlePlaylistBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		key := msg.String()
+		if m.handleJump(&m.playlistList, key, false) {
+			return m, nil
+		}
 
 		switch key {
 		case "esc", "q":
@@ -178,16 +179,16 @@ func (m *model) handlePlaylistBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 			// Play selected album's tracks
 			if selected, ok := m.playlistList.SelectedItem().(playlistItem); ok {
-				log.Debug(fmt.Sprintf("Playing playlist: %s (ratingKey: %s)", selected.title, selected.ratingKey))
+				log.Debug("Playing playlist: %s (ratingKey: %s)", selected.title, selected.ratingKey)
 				m.lastCommand = fmt.Sprintf("Playing %s", selected.title)
-				return m, m.playPlaylistCmd(selected.ratingKey)
+				return m, m.playPlaylistCmd(strings.TrimSuffix(selected.title, " ★"), selected.ratingKey)
 			}
 			return m, nil
 
 		case "f":
 			// add or remove selected artist from favorites (playback list)
 			if selected, ok := m.playlistList.SelectedItem().(playlistItem); ok {
-				log.Debug(fmt.Sprintf("Toggling favorite for playlist: %s (ratingKey: %s)", selected.title, selected.ratingKey))
+				log.Debug("Toggling favorite for playlist: %s (ratingKey: %s)", selected.title, selected.ratingKey)
 				m.lastCommand = fmt.Sprintf("Toggling favorite for %s", selected.title)
 				_, cmd := m.addRemoveFavorite(selected.title, selected.ratingKey, "playlist")
 				selected.ToggleFavorite()
@@ -196,6 +197,13 @@ func (m *model) handlePlaylistBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, cmd
 			}
 
+		case "N", "A":
+			// Queue the selected playlist after the current track or at the end, staying in this panel
+			if selected, ok := m.playlistList.SelectedItem().(playlistItem); ok && selected.ratingKey != "" {
+				return m, m.enqueueCmd(selected.title, selected.ratingKey, true, key == "N")
+			}
+			return m, nil
+
 		case "R":
 			// Refresh album list
 			m.status = "Refreshing albums..."
@@ -210,25 +218,24 @@ func (m *model) handlePlaylistBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case playlistsFetchedMsg:
-		log.Debug(fmt.Sprintf("playlistsFetchedMsg received with %d playlists, error: %v", len(msg.playlists), msg.err))
+		log.Debug("playlistsFetchedMsg received with %d playlists, error: %v", len(msg.playlists), msg.err)
 		if msg.err != nil {
+			if m.handleAuthError(msg.err) {
+				return m, nil
+			}
 			errMsg := fmt.Sprintf("Error fetching playlists: %v", msg.err)
 			m.status = errMsg
-			log.Debug(errMsg)
+			log.Debug("%s", errMsg)
 			return m, nil
 		}
 
-		favSet := make(map[string]struct{})
-		for _, pItem := range m.playbackList.Items() {
-			pItem := pItem.(item)
-			favSet[pItem.GetMetadataKey()] = struct{}{}
-		}
+		favSet := m.getCurrentFavSet()
 
 		// Convert playlists to list items
 		var items []list.Item
 		for i, playlist := range msg.playlists {
 			if i < 5 { // Only log first 5 playlists to avoid log spam
-				log.Debug(fmt.Sprintf("Adding playlist %d: %s (ratingKey: %s)", i+1, playlist.Title, playlist.RatingKey))
+				log.Debug("Adding playlist %d: %s (ratingKey: %s)", i+1, playlist.Title, playlist.RatingKey)
 			}
 
 			fav := false
@@ -246,7 +253,7 @@ func (m *model) handlePlaylistBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 			})
 		}
 
-		log.Debug(fmt.Sprintf("Creating new list with %d items", len(items)))
+		log.Debug("Creating new list with %d items", len(items))
 		// Create a new list with the fetched items
 		// Preserve the current filter state
 		filterState := m.playlistList.FilterState()
@@ -259,6 +266,7 @@ func (m *model) handlePlaylistBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Create new list with existing items
 		m.playlistList.SetItems(items)
 		m.playlistList.ResetSelected()
+		m.restoreSelection(&m.playlistList)
 
 		// Restore filter state if there was one
 		if filterState == list.Filtering {
@@ -266,21 +274,10 @@ func (m *model) handlePlaylistBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.playlistList.FilterInput.SetValue(filterValue)
 		}
 		m.status = fmt.Sprintf("Loaded %d playlists", len(msg.playlists))
-		log.Debug(fmt.Sprintf("Updated model with new playlist list. List has %d items", m.playlistList.VisibleItems()))
+		log.Debug("Updated model with new playlist list. List has %d items", m.playlistList.VisibleItems())
 
 		// Force a redraw
 		return m, tea.Batch(tea.ClearScreen, func() tea.Msg { return nil })
-
-	case playlistPlaybackMsg:
-		if msg.success {
-			m.lastCommand = "Playlist Playback Started"
-			m.status = "Playback triggered successfully"
-		} else {
-			m.lastCommand = "Playback Failed"
-			m.status = fmt.Sprintf("Playback error: %v", msg.err)
-		}
-		// Return the updated model and no command
-		return m, nil
 	}
 
 	// Update the artist list and get the command
@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"fmt"
+
+	"plexamp-tui/internal/plex"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// profilesFetchedMsg is a message containing the stored auth profiles
+type profilesFetchedMsg struct {
+	profiles []string
+	err      error
+}
+
+// profileSwitchedMsg is sent once a new auth profile has been activated and verified
+type profileSwitchedMsg struct {
+	profile       string
+	authenticated bool
+}
+
+// profileItem represents an auth profile in the list
+type profileItem struct {
+	name   string
+	active bool
+}
+
+// Title returns the profile name, marking the active profile
+func (i profileItem) Title() string {
+	if i.active {
+		return fmt.Sprintf("%s (active)", i.name)
+	}
+	return i.name
+}
+
+// Description returns the profile description (empty for now)
+func (i profileItem) Description() string { return "" }
+
+// FilterValue implements list.Item
+func (i profileItem) FilterValue() string { return i.name }
+
+// fetchProfilesCmd lists the stored auth profiles
+func (m *model) fetchProfilesCmd() tea.Cmd {
+	return tea.Batch(m.startLoading("plex-profiles"), func() tea.Msg {
+		profiles, err := plex.ListProfiles()
+		return profilesFetchedMsg{profiles: profiles, err: err}
+	})
+}
+
+// switchProfileCmd activates a profile and returns the command verifying its
+// token. The profile is switched here, in Update, so every command started
+// from now on uses its token.
+func (m *model) switchProfileCmd(profile string) tea.Cmd {
+	plexClient.SetProfile(profile)
+	profile = plexClient.Profile()
+	return func() tea.Msg {
+		return profileSwitchedMsg{
+			profile:       profile,
+			authenticated: plexClient.VerifyPlexAuthentication(),
+		}
+	}
+}
+
+// initProfileBrowse creates a new auth profile browser
+func (m *model) initProfileBrowse() {
+	m.panelMode = "plex-profiles"
+	m.status = "Loading profiles..."
+
+	// Create a new default delegate with custom styling
+	delegate := list.NewDefaultDelegate()
+	delegate.ShowDescription = false
+
+	m.profileList = list.New(nil, delegate, 0, 0)
+	m.profileList.Title = "Plex Profiles"
+	m.profileList.SetShowFilter(true)
+	m.profileList.SetFilteringEnabled(true)
+	m.profileList.Styles.Title = titleStyle
+	m.profileList.Styles.PaginationStyle = paginationStyle
+	m.profileList.Styles.HelpStyle = helpStyle
+	m.profileList.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(
+				key.WithKeys("enter"),
+				key.WithHelp("enter", "Switch Profile"),
+			),
+			key.NewBinding(
+				key.WithKeys("R"),
+				key.WithHelp("R", "Refresh Profiles"),
+			),
+		}
+	}
+	if m.width > 0 && m.height > 0 {
+		m.sizeList(&m.profileList)
+	}
+}
+
+// openProfileBrowser opens the profile panel
+// Unlike the other Plex panels it doesn't need a valid token, so an expired
+// profile can always be switched away from
+func (m *model) openProfileBrowser() (tea.Cmd, bool) {
+	m.initProfileBrowse()
+	return m.fetchProfilesCmd(), true
+}
+
+func (m *model) handleProfileBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// If we're in filtering mode, let the list handle the input
+	if m.profileList.FilterState() == list.Filtering {
+		var cmd tea.Cmd
+		m.profileList, cmd = m.profileList.Update(msg)
+		return m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		key := msg.String()
+
+		switch key {
+		case "esc", "q":
+			// Return to playback panel
+			m.panelMode = "playback"
+			m.status = ""
+			return m, nil
+
+		case "enter":
+			// Switch to the selected profile
+			if selected, ok := m.profileList.SelectedItem().(profileItem); ok && selected.name != "" {
+				log.Debug("Switching to profile: %s", selected.name)
+				m.status = fmt.Sprintf("Switching to profile %s...", selected.name)
+				return m, m.switchProfileCmd(selected.name)
+			}
+			return m, nil
+
+		case "R":
+			m.status = "Refreshing profiles..."
+			return m, m.fetchProfilesCmd()
+
+		default:
+
+			// Otherwise try the common controls
+			if cmd, handled := m.handleControl(key); handled {
+				return m, cmd
+			}
+		}
+
+	case profilesFetchedMsg:
+		if msg.err != nil {
+			errMsg := fmt.Sprintf("Error loading profiles: %v", msg.err)
+			m.status = errMsg
+			log.Debug("%s", errMsg)
+			return m, nil
+		}
+
+		active := plexClient.Profile()
+		var items []list.Item
+		for _, profile := range msg.profiles {
+			items = append(items, profileItem{name: profile, active: profile == active})
+		}
+		m.profileList.SetItems(items)
+		m.profileList.ResetSelected()
+		if len(items) == 0 {
+			m.status = "No profiles found - run with --auth [--profile <name>]"
+		} else {
+			m.status = fmt.Sprintf("Loaded %d profiles", len(items))
+		}
+		return m, nil
+	}
+
+	// Update the profile list and get the command
+	var listCmd tea.Cmd
+	m.profileList, listCmd = m.profileList.Update(msg)
+	return m, listCmd
+}
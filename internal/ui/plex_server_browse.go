@@ -1,10 +1,12 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"plexamp-tui/internal/config"
 	"plexamp-tui/internal/plex"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -16,6 +18,8 @@ type serverItem struct {
 	address          string
 	local            string
 	port             string
+	uri              string // Full URI of the connection, empty for older responses
+	relay            bool
 }
 
 // serversFetchedMsg is a message containing fetched servers
@@ -33,6 +37,9 @@ type serverSelectMsg struct {
 
 // Title returns the playlist title
 func (i serverItem) Title() string {
+	if i.relay {
+		return fmt.Sprintf("%s - %s (relay)", i.title, i.address)
+	}
 	return fmt.Sprintf("%s - %s", i.title, i.address)
 }
 
@@ -47,10 +54,7 @@ func (i serverItem) FilterValue() string {
 // fetchServersCmd fetches servers from the Plex server
 func (m *model) fetchServersCmd() tea.Cmd {
 	log.Debug("Fetching servers...")
-	// ✅ Reapply sizing
-	footerHeight := 3 // or dynamically measure your footer
-	availableHeight := m.height - footerHeight - 5
-	m.serverList.SetSize(m.width/2-4, availableHeight)
+	m.sizeList(&m.serverList)
 	if m.config == nil {
 		return func() tea.Msg {
 			return serversFetchedMsg{err: fmt.Errorf("no config available")}
@@ -64,10 +68,10 @@ func (m *model) fetchServersCmd() tea.Cmd {
 		}
 	}
 
-	return func() tea.Msg {
+	return tea.Batch(m.startLoading("plex-servers"), func() tea.Msg {
 		servers, err := plexClient.GetPlexServerInformation()
 		return serversFetchedMsg{servers: servers, err: err}
-	}
+	})
 }
 
 // initServerBrowse creates a new server browser
@@ -79,24 +83,42 @@ func (m *model) initServerBrowse() {
 	delegate := list.NewDefaultDelegate()
 	delegate.ShowDescription = false
 
-	items := []list.Item{serverItem{title: "Loading servers..."}}
-
 	// Create the list with empty items for now
-	m.serverList = list.New(items, delegate, 0, 0)
+	m.serverList = list.New(nil, delegate, 0, 0)
 	m.serverList.Title = "Plex Servers"
 	m.serverList.SetShowFilter(true)
 	m.serverList.SetFilteringEnabled(true)
 	m.serverList.Styles.Title = titleStyle
 	m.serverList.Styles.PaginationStyle = paginationStyle
 	m.serverList.Styles.HelpStyle = helpStyle
+	m.serverList.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(
+				key.WithKeys("a"),
+				key.WithHelp("a", "manual"),
+			),
+		}
+	}
+	m.serverList.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(
+				key.WithKeys("a"),
+				key.WithHelp("a", "Enter Server Manually"),
+			),
+			key.NewBinding(
+				key.WithKeys("R"),
+				key.WithHelp("R", "Refresh Servers"),
+			),
+		}
+	}
 	if m.width > 0 && m.height > 0 {
-		m.serverList.SetSize(m.width/2-4, m.height-4)
+		m.sizeList(&m.serverList)
 	}
 }
 func (m *model) selectServerCmd(server serverItem) tea.Cmd {
 	if m.selected == "" {
 		return func() tea.Msg {
-			return serverSelectMsg{success: false, err: fmt.Errorf("no server selected")}
+			return serverSelectMsg{success: false, err: errNoPlayer}
 		}
 	}
 
@@ -108,11 +130,19 @@ func (m *model) selectServerCmd(server serverItem) tea.Cmd {
 
 	return func() tea.Msg {
 
-		libraries, err := plexClient.FetchLibrary(fmt.Sprintf("%s:%s", server.address, server.port))
-		log.Debug(fmt.Sprintf("Fetched libraries: %v", libraries))
+		// Prefer the probed URI so HTTPS and relay connections keep their scheme
+		target := server.uri
+		if target == "" {
+			target = fmt.Sprintf("%s:%s", server.address, server.port)
+		}
+		libraries, err := plexClient.FetchLibraries(target, plexClient.GetPlexToken())
+		log.Debug("Fetched libraries: %v", libraries)
 
 		if err != nil {
-			log.Debug(fmt.Sprintf("Error fetching libraries: %v", err))
+			log.Debug("Error fetching libraries: %v", err)
+			if errors.Is(err, plex.ErrUnauthorized) {
+				return serverSelectMsg{success: false, err: err}
+			}
 		}
 
 		// When a server is selected we will write the serverId and serverAddress:port to the config file and save it to disk
@@ -124,7 +154,7 @@ func (m *model) selectServerCmd(server serverItem) tea.Cmd {
 }
 
 func (m *model) handleServerBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
-	log.Debug(fmt.Sprintf("handleServerBrowseUpdate received message: %T", msg))
+	log.Debug("handleServerBrowseUpdate received message: %T", msg)
 
 	// If we're in filtering mode, let the list handle the input
 	if m.serverList.FilterState() == list.Filtering {
@@ -147,12 +177,17 @@ func (m *model) handleServerBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 			// Select Server
 			if selected, ok := m.serverList.SelectedItem().(serverItem); ok {
-				log.Debug(fmt.Sprintf("Selecting server: %s (clientIdentifier: %s)", selected.title, selected.clientIdentifier))
+				log.Debug("Selecting server: %s (clientIdentifier: %s)", selected.title, selected.clientIdentifier)
 				m.lastCommand = fmt.Sprintf("Selecting %s", selected.title)
 				return m, m.selectServerCmd(selected)
 			}
 			return m, nil
 
+		case "a":
+			// Enter a server by hand when discovery can't find or reach it
+			m.initEditMode("server", -1)
+			return m, nil
+
 		case "R":
 			// Refresh server list
 			m.status = "Refreshing servers..."
@@ -167,11 +202,14 @@ func (m *model) handleServerBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case serversFetchedMsg:
-		log.Debug(fmt.Sprintf("serversFetchedMsg received with %d servers, error: %v", len(msg.servers), msg.err))
+		log.Debug("serversFetchedMsg received with %d servers, error: %v", len(msg.servers), msg.err)
 		if msg.err != nil {
+			if m.handleAuthError(msg.err) {
+				return m, nil
+			}
 			errMsg := fmt.Sprintf("Error fetching servers: %v", msg.err)
 			m.status = errMsg
-			log.Debug(errMsg)
+			log.Debug("%s", errMsg)
 			return m, nil
 		}
 
@@ -179,7 +217,7 @@ func (m *model) handleServerBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var items []list.Item
 		for i, server := range msg.servers {
 			if i < 5 { // Only log first 5 servers to avoid log spam
-				log.Debug(fmt.Sprintf("Adding server %d: %s (ratingKey: %s)", i+1, server.Name, server.ClientIdentifier))
+				log.Debug("Adding server %d: %s (ratingKey: %s)", i+1, server.Name, server.ClientIdentifier)
 			}
 			items = append(items, serverItem{
 				title:            server.Name,
@@ -187,10 +225,12 @@ func (m *model) handleServerBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 				address:          server.Address,
 				local:            server.Local,
 				port:             server.Port,
+				uri:              server.URI,
+				relay:            server.Relay == "1",
 			})
 		}
 
-		log.Debug(fmt.Sprintf("Creating new list with %d items", len(items)))
+		log.Debug("Creating new list with %d items", len(items))
 		// Create a new list with the fetched items
 		// Preserve the current filter state
 		filterState := m.serverList.FilterState()
@@ -210,7 +250,7 @@ func (m *model) handleServerBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.serverList.FilterInput.SetValue(filterValue)
 		}
 		m.status = fmt.Sprintf("Loaded %d servers", len(msg.servers))
-		log.Debug(fmt.Sprintf("Updated model with new server list. List has %d items", m.serverList.VisibleItems()))
+		log.Debug("Updated model with new server list. List has %d items", m.serverList.VisibleItems())
 
 		// Force a redraw
 		return m, tea.Batch(tea.ClearScreen, func() tea.Msg { return nil })
@@ -0,0 +1,290 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"plexamp-tui/internal/config"
+	"plexamp-tui/internal/plex"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type trackPlaybackMsg struct {
+	success bool
+	err     error
+	played  config.HistoryItem // The item that was played, recorded in the history on success
+}
+
+// tracksFetchedMsg is a message containing fetched album tracks
+type tracksFetchedMsg struct {
+	tracks []plex.PlexTrack
+	err    error
+}
+
+// trackItem represents a track in the list
+type trackItem struct {
+	title      string
+	index      string
+	durationMs int
+	ratingKey  string
+	userRating float64 // Plex rating out of 10
+	unit       string  // "Chapter" or "Episode" in audiobook and podcast libraries, empty for music
+	viewOffset int     // Resume position in milliseconds
+}
+
+// Title returns the track title prefixed with its track number, or its
+// chapter or episode number
+func (i trackItem) Title() string {
+	if i.index == "" {
+		return i.title
+	}
+	if i.unit != "" {
+		return fmt.Sprintf("%s %s: %s (%s)%s", i.unit, i.index, i.title, formatTime(i.durationMs), ratingMark(i.userRating))
+	}
+	return fmt.Sprintf("%s. %s (%s)%s", i.index, i.title, formatTime(i.durationMs), ratingMark(i.userRating))
+}
+
+// Description returns the track description (empty for now)
+func (i trackItem) Description() string { return "" }
+
+// FilterValue implements list.Item
+func (i trackItem) FilterValue() string {
+	return i.title
+}
+
+// fetchTracksCmd fetches the tracks of the currently browsed album
+func (m *model) fetchTracksCmd() tea.Cmd {
+	log.Debug("Fetching tracks...")
+	m.sizeList(&m.trackList)
+	if m.config == nil {
+		return func() tea.Msg {
+			return tracksFetchedMsg{err: fmt.Errorf("no config available")}
+		}
+	}
+
+	token := plexClient.GetPlexToken()
+	if token == "" {
+		return func() tea.Msg {
+			return tracksFetchedMsg{err: fmt.Errorf("no Plex token found - run with --auth flag")}
+		}
+	}
+
+	serverAddr := m.config.ServerURL()
+	albumKey := m.trackAlbumKey
+
+	return tea.Batch(m.startLoading("plex-tracks"), func() tea.Msg {
+		tracks, err := plexClient.FetchAlbumTracks(serverAddr, albumKey, token)
+		return tracksFetchedMsg{tracks: tracks, err: err}
+	})
+}
+
+// initTrackBrowse creates a new track browser for the given album
+func (m *model) initTrackBrowse(album albumItem) {
+	// Drilling down from the album list extends its breadcrumb
+	albumTitle := strings.TrimSuffix(album.title, " ★")
+	if m.panelMode == "plex-albums" {
+		m.trackCrumbs = append(m.breadcrumbs(), albumTitle)
+	} else {
+		m.trackCrumbs = []string{m.libraryCrumb(), album.artist, albumTitle}
+	}
+	m.panelMode = "plex-tracks"
+	m.status = "Loading tracks..."
+	m.trackAlbumKey = album.ratingKey
+
+	// Create a new default delegate with custom styling
+	delegate := list.NewDefaultDelegate()
+	delegate.ShowDescription = false
+
+	// Create the list with empty items for now
+	m.trackList = list.New(nil, delegate, 0, 0)
+	m.trackList.Title = fmt.Sprintf("%s - %s", album.artist, strings.TrimSuffix(album.title, " ★"))
+	m.trackList.SetShowFilter(true)
+	m.trackList.SetFilteringEnabled(true)
+	m.trackList.Styles.Title = titleStyle
+	m.trackList.Styles.PaginationStyle = paginationStyle
+	m.trackList.Styles.HelpStyle = helpStyle
+	m.trackList.AdditionalFullHelpKeys = func() []key.Binding {
+		return append([]key.Binding{
+			key.NewBinding(
+				key.WithKeys("esc"),
+				key.WithHelp("esc", "Back to Albums"),
+			),
+			key.NewBinding(
+				key.WithKeys("R"),
+				key.WithHelp("R", "Refresh Tracks"),
+			),
+			key.NewBinding(
+				key.WithKeys("r"),
+				key.WithHelp("r", "Play Radio"),
+			),
+			rateHelpKey(),
+		}, enqueueHelpKeys()...)
+	}
+	if m.width > 0 && m.height > 0 {
+		m.sizeList(&m.trackList)
+	}
+}
+
+// playTrackCmd starts playback of a single track
+func (m *model) playTrackCmd(name, ratingKey string) tea.Cmd {
+	if m.selected == "" {
+		return func() tea.Msg {
+			return trackPlaybackMsg{success: false, err: errNoPlayer}
+		}
+	}
+
+	if m.config == nil {
+		return func() tea.Msg {
+			return trackPlaybackMsg{success: false, err: fmt.Errorf("no config available")}
+		}
+	}
+
+	serverIP := m.selected
+	serverID := m.config.ServerID
+
+	return func() tea.Msg {
+		// Shuffling a single track makes no sense, so always play it as-is
+		err := PlayMetadata(serverIP, serverID, ratingKey, false)
+		if err != nil {
+			return trackPlaybackMsg{success: false, err: err}
+		}
+		return trackPlaybackMsg{success: true, played: config.HistoryItem{Name: name, Type: "track", MetadataKey: ratingKey}}
+	}
+}
+
+// playTrackRadioCmd starts a station of music similar to a track
+func (m *model) playTrackRadioCmd(name, ratingKey string) tea.Cmd {
+	if m.selected == "" {
+		return func() tea.Msg {
+			return trackPlaybackMsg{success: false, err: errNoPlayer}
+		}
+	}
+
+	if m.config == nil {
+		return func() tea.Msg {
+			return trackPlaybackMsg{success: false, err: fmt.Errorf("no config available")}
+		}
+	}
+
+	serverIP := m.selected
+	serverID := m.config.ServerID
+	shuffle := m.shuffle
+
+	return func() tea.Msg {
+		err := PlayTrackRadio(serverIP, serverID, ratingKey, shuffle)
+		if err != nil {
+			return trackPlaybackMsg{success: false, err: err}
+		}
+		return trackPlaybackMsg{success: true, played: config.HistoryItem{Name: name, Type: "station", MetadataKey: ratingKey}}
+	}
+}
+
+func (m *model) handleTrackBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	log.Debug("handleTrackBrowseUpdate received message: %T", msg)
+
+	// If we're in filtering mode, let the list handle the input
+	if m.trackList.FilterState() == list.Filtering {
+		var cmd tea.Cmd
+		m.trackList, cmd = m.trackList.Update(msg)
+		return m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		key := msg.String()
+
+		switch key {
+		case "esc", "q":
+			// Return to the album list, which still holds the previous selection
+			m.panelMode = "plex-albums"
+			m.status = ""
+			return m, nil
+
+		case "enter":
+			// Play just the selected track
+			if selected, ok := m.trackList.SelectedItem().(trackItem); ok && selected.ratingKey != "" {
+				log.Debug("Playing track: %s (ratingKey: %s)", selected.title, selected.ratingKey)
+				m.lastCommand = fmt.Sprintf("Playing %s", selected.title)
+				return m, m.offerResume(selected.title, selected.viewOffset, m.playTrackCmd(selected.title, selected.ratingKey))
+			}
+			return m, nil
+
+		case "r":
+			// Play a station seeded from the selected track
+			if selected, ok := m.trackList.SelectedItem().(trackItem); ok && selected.ratingKey != "" {
+				m.lastCommand = fmt.Sprintf("Playing %s Radio", selected.title)
+				return m, m.playTrackRadioCmd(selected.title, selected.ratingKey)
+			}
+			return m, nil
+
+		case "L":
+			// Love the selected track on Plex, or clear its rating
+			if selected, ok := m.trackList.SelectedItem().(trackItem); ok {
+				return m, m.rateCmd(selected.title, selected.ratingKey, selected.userRating)
+			}
+			return m, nil
+
+		case "N", "A":
+			// Queue the selected track after the current track or at the end, staying in this panel
+			if selected, ok := m.trackList.SelectedItem().(trackItem); ok && selected.ratingKey != "" {
+				return m, m.enqueueCmd(selected.title, selected.ratingKey, false, key == "N")
+			}
+			return m, nil
+
+		case "R":
+			// Refresh track list
+			m.status = "Refreshing tracks..."
+			return m, m.fetchTracksCmd()
+
+		default:
+
+			// Otherwise try the common controls
+			if cmd, handled := m.handleControl(key); handled {
+				return m, cmd
+			}
+		}
+
+	case tracksFetchedMsg:
+		log.Debug("tracksFetchedMsg received with %d tracks, error: %v", len(msg.tracks), msg.err)
+		if msg.err != nil {
+			if m.handleAuthError(msg.err) {
+				return m, nil
+			}
+			errMsg := fmt.Sprintf("Error fetching tracks: %v", msg.err)
+			m.status = errMsg
+			log.Debug("%s", errMsg)
+			return m, nil
+		}
+
+		// Convert tracks to list items
+		types := m.libraryTypes()
+		var items []list.Item
+		for _, track := range msg.tracks {
+			items = append(items, trackItem{
+				title:      track.Title,
+				index:      track.Index,
+				durationMs: track.Duration,
+				ratingKey:  track.RatingKey,
+				userRating: track.UserRating,
+				unit:       types.Track,
+				viewOffset: track.ViewOffset,
+			})
+		}
+
+		m.trackList.SetItems(items)
+		m.trackList.ResetSelected()
+		m.status = fmt.Sprintf("Loaded %d %s", len(msg.tracks), strings.ToLower(types.Tracks))
+
+		// Force a redraw
+		return m, tea.Batch(tea.ClearScreen, func() tea.Msg { return nil })
+	}
+
+	// Update the track list and get the command
+	var listCmd tea.Cmd
+	m.trackList, listCmd = m.trackList.Update(msg)
+	// Return the current model (as a pointer) and the command
+	return m, listCmd
+}
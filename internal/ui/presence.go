@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"plexamp-tui/internal/config"
+	"plexamp-tui/internal/presence"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// presencePauseGrace is how long playback can stay paused before the
+// Discord presence is cleared
+const presencePauseGrace = 2 * time.Minute
+
+// presenceState remembers what was last sent to Discord so updates are only
+// sent when the track or play state changes
+type presenceState struct {
+	track       string    // TrackText last shown
+	playing     bool      // Play state last shown
+	pausedSince time.Time // When playback was first seen paused
+	cleared     bool      // Whether the presence was cleared after the grace period
+}
+
+// presenceGraceMsg is sent when the pause grace period started at
+// pausedSince is up
+type presenceGraceMsg struct {
+	pausedSince time.Time
+}
+
+// newPresenceClient creates the Discord presence client when it is enabled
+func newPresenceClient(c *config.Config) *presence.Client {
+	if c == nil || !c.DiscordPresence {
+		return nil
+	}
+	if c.DiscordClientID == "" {
+		log.Warn("Discord presence is enabled but discord_client_id is not set")
+		return nil
+	}
+	return presence.NewClient(c.DiscordClientID)
+}
+
+// updatePresence returns a command syncing the Discord presence with a
+// timeline update, or nil when nothing changed
+func (m *model) updatePresence(msg trackMsgWithState) tea.Cmd {
+	if presenceClient == nil {
+		return nil
+	}
+
+	p := &m.presence
+	if msg.Title == "" {
+		if p.track == "" {
+			return nil
+		}
+		*p = presenceState{}
+		return clearPresenceCmd()
+	}
+
+	var graceCmd tea.Cmd
+	if !msg.IsPlaying {
+		if p.pausedSince.IsZero() {
+			// A paused player holds the long poll, so the grace period can't
+			// wait for the next timeline update
+			p.pausedSince = time.Now()
+			since := p.pausedSince
+			graceCmd = tea.Tick(presencePauseGrace, func(time.Time) tea.Msg {
+				return presenceGraceMsg{pausedSince: since}
+			})
+		}
+	} else {
+		p.pausedSince = time.Time{}
+		p.cleared = false
+	}
+
+	if p.cleared || (msg.TrackText == p.track && msg.IsPlaying == p.playing) {
+		return graceCmd
+	}
+	p.track = msg.TrackText
+	p.playing = msg.IsPlaying
+
+	activity := presence.Activity{
+		Details:    msg.Title,
+		State:      fmt.Sprintf("by %s", msg.Artist),
+		LargeImage: "plexamp",
+		LargeText:  msg.Album,
+		SmallImage: "paused",
+		SmallText:  "Paused",
+	}
+	if msg.IsPlaying {
+		activity.SmallImage = "playing"
+		activity.SmallText = "Playing"
+		// Backdate the start so Discord's elapsed time matches the track position
+		activity.Start = time.Now().Add(-time.Duration(msg.Position) * time.Millisecond)
+	}
+
+	return tea.Batch(graceCmd, func() tea.Msg {
+		if err := presenceClient.SetActivity(activity); err != nil && !errors.Is(err, presence.ErrNotRunning) {
+			log.Warn("Failed to update Discord presence: %v", err)
+		}
+		return nil
+	})
+}
+
+// handlePresenceGrace clears the presence when playback is still paused
+// since the pause the grace period was started for
+func (m *model) handlePresenceGrace(msg presenceGraceMsg) tea.Cmd {
+	p := &m.presence
+	if presenceClient == nil || p.cleared || !p.pausedSince.Equal(msg.pausedSince) {
+		return nil
+	}
+	p.cleared = true
+	return clearPresenceCmd()
+}
+
+// clearPresenceCmd removes the Discord presence
+func clearPresenceCmd() tea.Cmd {
+	return func() tea.Msg {
+		if err := presenceClient.Clear(); err != nil && !errors.Is(err, presence.ErrNotRunning) {
+			log.Warn("Failed to clear Discord presence: %v", err)
+		}
+		return nil
+	}
+}
+
+// closePresence clears the presence and closes the Discord connection on quit
+func closePresence() {
+	if presenceClient == nil {
+		return
+	}
+	if err := presenceClient.Clear(); err != nil && !errors.Is(err, presence.ErrNotRunning) {
+		log.Warn("Failed to clear Discord presence: %v", err)
+	}
+	presenceClient.Close()
+}
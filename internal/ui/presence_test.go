@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"testing"
+
+	"plexamp-tui/internal/presence"
+)
+
+func TestPresenceClearedAfterPauseWithoutTimeline(t *testing.T) {
+	useTestConfig(t)
+	saved := presenceClient
+	t.Cleanup(func() { presenceClient = saved })
+	presenceClient = presence.NewClient("client")
+
+	m := &model{}
+	m.updatePresence(testTrack("Tusk", true))
+	if cmd := m.updatePresence(testTrack("Tusk", false)); cmd == nil {
+		t.Fatal("pausing sent nothing")
+	}
+	paused := m.presence.pausedSince
+
+	// The grace period runs out with no timeline update in between
+	if cmd := m.handlePresenceGrace(presenceGraceMsg{pausedSince: paused}); cmd == nil || !m.presence.cleared {
+		t.Error("the presence wasn't cleared when the grace period ran out")
+	}
+}
+
+func TestPresenceGraceIgnoredAfterResume(t *testing.T) {
+	useTestConfig(t)
+	saved := presenceClient
+	t.Cleanup(func() { presenceClient = saved })
+	presenceClient = presence.NewClient("client")
+
+	m := &model{}
+	m.updatePresence(testTrack("Tusk", false))
+	paused := m.presence.pausedSince
+	m.updatePresence(testTrack("Tusk", true))
+
+	if cmd := m.handlePresenceGrace(presenceGraceMsg{pausedSince: paused}); cmd != nil || m.presence.cleared {
+		t.Error("cleared the presence of a track that is playing again")
+	}
+
+	// Nor by the grace period of an earlier pause
+	m.updatePresence(testTrack("Tusk", false))
+	if cmd := m.handlePresenceGrace(presenceGraceMsg{pausedSince: paused}); cmd != nil || m.presence.cleared {
+		t.Error("cleared the presence at the end of an earlier pause's grace period")
+	}
+}
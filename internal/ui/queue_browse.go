@@ -0,0 +1,357 @@
+package ui
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"plexamp-tui/internal/plex"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// queueFetchedMsg is a message containing the player's current play queue
+type queueFetchedMsg struct {
+	queue *plex.PlexPlayQueue
+	err   error
+}
+
+// queueItemRemovedMsg is sent once an item has been removed from the play queue
+type queueItemRemovedMsg struct {
+	title string
+	err   error
+}
+
+// queueClearedMsg is sent once every item has been removed from the play queue
+type queueClearedMsg struct {
+	err error
+}
+
+// enqueuedMsg is sent once an item has been added to the play queue from a browse panel
+type enqueuedMsg struct {
+	title string
+	next  bool
+	err   error
+}
+
+// queueItem represents a track in the play queue
+type queueItem struct {
+	track   plex.PlexQueueTrack
+	current bool
+}
+
+// Title returns the track title, marking the track that is playing
+func (i queueItem) Title() string {
+	if i.current {
+		return "▶ " + i.track.Title
+	}
+	return "  " + i.track.Title
+}
+
+// Description returns the artist and album of the track
+func (i queueItem) Description() string {
+	if i.track.GrandparentTitle == "" {
+		return ""
+	}
+	return fmt.Sprintf("  %s - %s", i.track.GrandparentTitle, i.track.ParentTitle)
+}
+
+// FilterValue implements list.Item
+func (i queueItem) FilterValue() string {
+	return i.track.Title + " " + i.track.GrandparentTitle
+}
+
+// fetchQueueCmd fetches the play queue the player is working through
+func (m *model) fetchQueueCmd() tea.Cmd {
+	if m.config == nil {
+		return func() tea.Msg {
+			return queueFetchedMsg{err: fmt.Errorf("no config available")}
+		}
+	}
+
+	token := plexClient.GetPlexToken()
+	if token == "" {
+		return func() tea.Msg {
+			return queueFetchedMsg{err: fmt.Errorf("no Plex token found - run with --auth flag")}
+		}
+	}
+
+	if m.playQueueID == "" {
+		return func() tea.Msg {
+			return queueFetchedMsg{err: fmt.Errorf("nothing is queued on the player")}
+		}
+	}
+
+	serverAddr := m.config.ServerURL()
+	playQueueID := m.playQueueID
+
+	return tea.Batch(m.startLoading("queue"), func() tea.Msg {
+		queue, err := plexClient.FetchPlayQueue(serverAddr, playQueueID, token)
+		return queueFetchedMsg{queue: queue, err: err}
+	})
+}
+
+// removeFromQueueCmd removes a track from the play queue
+func (m *model) removeFromQueueCmd(item queueItem) tea.Cmd {
+	token := plexClient.GetPlexToken()
+	serverAddr := m.config.ServerURL()
+	playQueueID := m.playQueueID
+
+	return func() tea.Msg {
+		err := plexClient.RemoveFromPlayQueue(serverAddr, playQueueID, item.track.PlayQueueItemID, token)
+		return queueItemRemovedMsg{title: item.track.Title, err: err}
+	}
+}
+
+// clearQueueCmd removes every item from the play queue
+func (m *model) clearQueueCmd() tea.Cmd {
+	token := plexClient.GetPlexToken()
+	serverAddr := m.config.ServerURL()
+	playQueueID := m.playQueueID
+
+	return func() tea.Msg {
+		return queueClearedMsg{err: plexClient.ClearPlayQueue(serverAddr, playQueueID, token)}
+	}
+}
+
+// enqueueCmd adds a library item, or a playlist, to the player's play queue
+// without replacing it: right after the current track when next is set,
+// otherwise at the end
+func (m *model) enqueueCmd(title, ratingKey string, playlist, next bool) tea.Cmd {
+	if m.selected == "" || m.config == nil {
+		m.status = noPlayerStatus()
+		return nil
+	}
+	if m.playQueueID == "" {
+		m.status = "Nothing is queued on the player - press Enter to start playing"
+		return nil
+	}
+
+	serverIP := m.selected
+	serverAddr := m.config.ServerURL()
+	serverID := m.config.ServerID
+	playQueueID := m.playQueueID
+	title = strings.TrimSuffix(title, " ★")
+
+	return func() tea.Msg {
+		var err error
+		if playlist {
+			err = AddPlaylistToPlayQueue(serverIP, serverAddr, playQueueID, ratingKey, next)
+		} else {
+			err = AddToPlayQueue(serverIP, serverAddr, serverID, playQueueID, ratingKey, next)
+		}
+		return enqueuedMsg{title: title, next: next, err: err}
+	}
+}
+
+// handleEnqueued reports the result of adding to the queue, leaving the panel as it is
+func (m *model) handleEnqueued(msg enqueuedMsg) {
+	if msg.err != nil {
+		if m.handleAuthError(msg.err) {
+			return
+		}
+		m.status = fmt.Sprintf("Error queueing %s: %v", msg.title, msg.err)
+		return
+	}
+	if msg.next {
+		m.lastCommand = fmt.Sprintf("Playing next: %s", msg.title)
+	} else {
+		m.lastCommand = fmt.Sprintf("Queued %s", msg.title)
+	}
+	m.status = m.lastCommand
+}
+
+// enqueueHelpKeys describes the queueing keys shared by the browse panels
+func enqueueHelpKeys() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "Play Next"),
+		),
+		key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "Add to Queue"),
+		),
+	}
+}
+
+// initQueueBrowse creates a new play queue browser
+func (m *model) initQueueBrowse() {
+	m.panelMode = "queue"
+	m.status = "Loading queue..."
+
+	m.queueList = list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	m.queueList.Title = "Play Queue"
+	m.queueList.SetShowFilter(true)
+	m.queueList.SetFilteringEnabled(true)
+	m.queueList.Styles.Title = titleStyle
+	m.queueList.Styles.PaginationStyle = paginationStyle
+	m.queueList.Styles.HelpStyle = helpStyle
+	m.queueList.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(
+				key.WithKeys("d"),
+				key.WithHelp("d", "remove"),
+			),
+		}
+	}
+	m.queueList.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(
+				key.WithKeys("enter"),
+				key.WithHelp("enter", "Jump to Track"),
+			),
+			key.NewBinding(
+				key.WithKeys("d"),
+				key.WithHelp("d", "Remove from Queue"),
+			),
+			key.NewBinding(
+				key.WithKeys("C"),
+				key.WithHelp("C", "Clear Queue"),
+			),
+			key.NewBinding(
+				key.WithKeys("R"),
+				key.WithHelp("R", "Refresh Queue"),
+			),
+		}
+	}
+	if m.width > 0 && m.height > 0 {
+		m.sizeList(&m.queueList)
+	}
+}
+
+func (m *model) openQueueBrowser() (tea.Cmd, bool) {
+	if m.plexAuthenticated && m.config != nil {
+		m.initQueueBrowse()
+		return m.fetchQueueCmd(), true
+	} else {
+		m.status = "Plex authentication required (run with --auth)"
+	}
+	return nil, false
+}
+
+func (m *model) handleQueueBrowseUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// If we're in filtering mode, let the list handle the input
+	if m.queueList.FilterState() == list.Filtering {
+		var cmd tea.Cmd
+		m.queueList, cmd = m.queueList.Update(msg)
+		return m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		key := msg.String()
+
+		switch key {
+		case "esc", "q":
+			// Return to playback panel
+			m.panelMode = "playback"
+			m.status = ""
+			return m, nil
+
+		case "enter":
+			// Skip ahead (or back) to the selected track
+			if selected, ok := m.queueList.SelectedItem().(queueItem); ok && selected.track.PlayQueueItemID != "" {
+				cmd := m.sendCommand(fmt.Sprintf("playback/skipTo?key=%s&playQueueItemID=%s&commandID=1&type=music",
+					url.QueryEscape("/library/metadata/"+selected.track.RatingKey), selected.track.PlayQueueItemID))
+				if cmd != nil {
+					m.lastCommand = fmt.Sprintf("Jump to %s", selected.track.Title)
+				}
+				return m, cmd
+			}
+			return m, nil
+
+		case "d":
+			if selected, ok := m.queueList.SelectedItem().(queueItem); ok && selected.track.PlayQueueItemID != "" {
+				if selected.current {
+					m.status = "Can't remove the track that is playing"
+					return m, nil
+				}
+				m.status = fmt.Sprintf("Removing %s...", selected.track.Title)
+				return m, m.removeFromQueueCmd(selected)
+			}
+			return m, nil
+
+		case "C":
+			if m.playQueueID == "" {
+				m.status = "Nothing is queued on the player"
+				return m, nil
+			}
+			m.status = "Clearing queue..."
+			return m, m.clearQueueCmd()
+
+		case "R":
+			m.status = "Refreshing queue..."
+			return m, m.fetchQueueCmd()
+
+		default:
+
+			// Otherwise try the common controls
+			if cmd, handled := m.handleControl(key); handled {
+				return m, cmd
+			}
+		}
+
+	case queueFetchedMsg:
+		if msg.err != nil {
+			if m.handleAuthError(msg.err) {
+				return m, nil
+			}
+			errMsg := fmt.Sprintf("Error loading queue: %v", msg.err)
+			m.status = errMsg
+			log.Debug("%s", errMsg)
+			return m, nil
+		}
+
+		// Keep the selection where it was unless the list is being loaded fresh
+		index := m.queueList.Index()
+		fresh := len(m.queueList.Items()) == 1 && m.queueList.Items()[0].(queueItem).track.PlayQueueItemID == ""
+
+		var items []list.Item
+		for i, track := range msg.queue.Tracks {
+			current := track.PlayQueueItemID == msg.queue.SelectedItemID
+			if current && fresh {
+				index = i
+			}
+			items = append(items, queueItem{track: track, current: current})
+		}
+		m.queueList.SetItems(items)
+		if index < len(items) {
+			m.queueList.Select(index)
+		}
+		m.status = fmt.Sprintf("Loaded %d queued tracks", len(items))
+		return m, nil
+
+	case queueItemRemovedMsg:
+		if msg.err != nil {
+			if m.handleAuthError(msg.err) {
+				return m, nil
+			}
+			m.status = fmt.Sprintf("Error removing %s: %v", msg.title, msg.err)
+			return m, nil
+		}
+		// The player keeps its own copy of the queue until told to reload it
+		refresh := m.sendCommand(fmt.Sprintf("playback/refreshPlayQueue?playQueueID=%s&commandID=1&type=music", m.playQueueID))
+		m.lastCommand = fmt.Sprintf("Removed %s", msg.title)
+		return m, tea.Batch(refresh, m.fetchQueueCmd())
+
+	case queueClearedMsg:
+		if msg.err != nil {
+			if m.handleAuthError(msg.err) {
+				return m, nil
+			}
+			m.status = fmt.Sprintf("Error clearing queue: %v", msg.err)
+			return m, nil
+		}
+		refresh := m.sendCommand(fmt.Sprintf("playback/refreshPlayQueue?playQueueID=%s&commandID=1&type=music", m.playQueueID))
+		m.lastCommand = "Cleared queue"
+		return m, tea.Batch(refresh, m.fetchQueueCmd())
+	}
+
+	// Update the queue list and get the command
+	var listCmd tea.Cmd
+	m.queueList, listCmd = m.queueList.Update(msg)
+	return m, listCmd
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"fmt"
+	"math/rand"
+
+	"plexamp-tui/internal/plex"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// randomAlbumMsg carries the library's albums, fetched to pick one at random
+type randomAlbumMsg struct {
+	library string
+	albums  []plex.PlexAlbum
+	err     error
+}
+
+// playRandomAlbum plays an album picked at random from the selected library,
+// shuffled when shuffle is on. The cached album list is used when there is
+// one; otherwise the albums are fetched, and cached for the album panel.
+func (m *model) playRandomAlbum() tea.Cmd {
+	if m.config == nil || !m.plexAuthenticated {
+		m.status = "Plex authentication required (run with --auth)"
+		return nil
+	}
+	if err := m.checkMusicLibrary(); err != nil {
+		m.status = err.Error()
+		return nil
+	}
+
+	serverAddr := m.config.ServerURL()
+	libraryID := m.config.PlexLibraryID
+	library := libraryCacheKey(serverAddr, libraryID)
+	if albums, ok := m.libraryCache.cachedAlbums(library); ok {
+		return m.playPickedAlbum(albums)
+	}
+
+	token := plexClient.GetPlexToken()
+	types := m.libraryTypes()
+	m.status = "Picking a random album..."
+
+	return func() tea.Msg {
+		albums, err := plexClient.FetchAlbums(serverAddr, libraryID, types, token)
+		return randomAlbumMsg{library: library, albums: albums, err: err}
+	}
+}
+
+// handleRandomAlbum caches the fetched albums and plays one of them
+func (m *model) handleRandomAlbum(msg randomAlbumMsg) tea.Cmd {
+	if msg.err != nil {
+		if m.handleAuthError(msg.err) {
+			return nil
+		}
+		m.status = fmt.Sprintf("Error fetching albums: %v", msg.err)
+		return nil
+	}
+	m.libraryCache.storeAlbums(msg.library, msg.albums)
+	return m.playPickedAlbum(msg.albums)
+}
+
+// playPickedAlbum plays one of albums, picked at random
+func (m *model) playPickedAlbum(albums []plex.PlexAlbum) tea.Cmd {
+	if len(albums) == 0 {
+		m.status = "No albums in this library"
+		return nil
+	}
+	album := albums[rand.Intn(len(albums))]
+	name := album.Title
+	if album.ParentTitle != "" {
+		name += " - " + album.ParentTitle
+	}
+	m.randomAlbumKey = album.RatingKey
+	m.randomAlbumName = name
+	m.lastCommand = "Playing " + name
+	m.status = "Random album: " + name
+	return m.playAlbumCmd(album.Title, album.RatingKey)
+}
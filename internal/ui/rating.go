@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// lovedRating is the Plex user rating (out of 10) given to loved items
+	lovedRating = 10
+
+	// highRating is the lowest rating marked in the lists, i.e. four stars
+	highRating = 8
+)
+
+// ratedMsg is sent once the server has stored a new rating for an item
+type ratedMsg struct {
+	ratingKey string
+	title     string
+	rating    int
+	err       error
+}
+
+// ratingMark returns the suffix shown after the title of a loved or highly
+// rated item. Ratings are kept on the server, unlike the local favorites
+// marked with ★.
+func ratingMark(rating float64) string {
+	if rating >= highRating {
+		return " ♥"
+	}
+	return ""
+}
+
+// playCountLabel describes how often and how recently an item was played
+func playCountLabel(viewCount int, lastViewedAt int64) string {
+	switch {
+	case viewCount == 0:
+		return "Never played"
+	case lastViewedAt == 0:
+		return fmt.Sprintf("Played %d×", viewCount)
+	default:
+		return fmt.Sprintf("Played %d×, last %s", viewCount, time.Unix(lastViewedAt, 0).Format("2006-01-02"))
+	}
+}
+
+// rateCmd loves an item, or clears its rating when it is already loved
+func (m *model) rateCmd(title, ratingKey string, current float64) tea.Cmd {
+	if m.config == nil || ratingKey == "" {
+		return nil
+	}
+
+	rating := lovedRating
+	if current >= lovedRating {
+		rating = 0
+	}
+
+	serverAddr := m.config.ServerURL()
+	token := plexClient.GetPlexToken()
+	title = strings.TrimSuffix(title, " ★")
+
+	return func() tea.Msg {
+		err := plexClient.RateItem(serverAddr, ratingKey, rating, token)
+		return ratedMsg{ratingKey: ratingKey, title: title, rating: rating, err: err}
+	}
+}
+
+// handleRated updates the rated item wherever it is listed
+func (m *model) handleRated(msg ratedMsg) {
+	if msg.err != nil {
+		if m.handleAuthError(msg.err) {
+			return
+		}
+		m.status = fmt.Sprintf("Error rating %s: %v", msg.title, msg.err)
+		return
+	}
+
+	for i, item := range m.albumList.Items() {
+		if album, ok := item.(albumItem); ok && album.ratingKey == msg.ratingKey {
+			album.userRating = float64(msg.rating)
+			m.albumList.SetItem(i, album)
+		}
+	}
+	for i, item := range m.trackList.Items() {
+		if track, ok := item.(trackItem); ok && track.ratingKey == msg.ratingKey {
+			track.userRating = float64(msg.rating)
+			m.trackList.SetItem(i, track)
+		}
+	}
+	m.libraryCache.setAlbumRating(msg.ratingKey, float64(msg.rating))
+
+	if msg.rating > 0 {
+		m.lastCommand = fmt.Sprintf("Loved %s", msg.title)
+	} else {
+		m.lastCommand = fmt.Sprintf("Cleared rating of %s", msg.title)
+	}
+}
+
+// rateHelpKey describes the rating key shared by the album and track lists
+func rateHelpKey() key.Binding {
+	return key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "Love/Unlove on Plex"),
+	)
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// resumeSeekDelay gives the player time to load an item before it is asked
+// to seek into it; a seek sent straight after the play command is lost
+const resumeSeekDelay = 2 * time.Second
+
+// resumeOffer is playback of a partly played item, waiting for the user to
+// choose between resuming it and starting over
+type resumeOffer struct {
+	name     string
+	offsetMs int
+	play     tea.Cmd
+}
+
+// resumeSeekMsg asks for a seek to where a resumed item was left off
+type resumeSeekMsg struct {
+	offsetMs int
+}
+
+// offerResume starts play, first asking whether to resume from offsetMs when
+// Plex has a resume position for the item. With always_resume set it
+// resumes without asking.
+func (m *model) offerResume(name string, offsetMs int, play tea.Cmd) tea.Cmd {
+	if offsetMs <= 0 {
+		return play
+	}
+	if m.config != nil && m.config.AlwaysResume {
+		return tea.Batch(play, resumeSeekCmd(offsetMs))
+	}
+	m.resume = &resumeOffer{name: name, offsetMs: offsetMs, play: play}
+	m.status = fmt.Sprintf("Resume %s at %s? (y/n, esc to cancel)", name, formatTime(offsetMs))
+	return nil
+}
+
+// handleResumeKey answers the resume question: y resumes, n starts over and
+// esc plays nothing. Other keys are ignored.
+func (m *model) handleResumeKey(key string) tea.Cmd {
+	offer := m.resume
+	switch key {
+	case "y", "Y", "enter":
+		m.resume = nil
+		m.status = ""
+		return tea.Batch(offer.play, resumeSeekCmd(offer.offsetMs))
+	case "n", "N":
+		m.resume = nil
+		m.status = ""
+		return offer.play
+	case "esc", "q":
+		m.resume = nil
+		m.status = ""
+		m.lastCommand = ""
+		return nil
+	}
+	return nil
+}
+
+// resumeSeekCmd seeks to offsetMs once the player has had time to start
+func resumeSeekCmd(offsetMs int) tea.Cmd {
+	return tea.Tick(resumeSeekDelay, func(time.Time) tea.Msg {
+		return resumeSeekMsg{offsetMs: offsetMs}
+	})
+}
+
+// resumeAt seeks the item that just started to where it was left off. Unlike
+// seekTo it doesn't clamp to the duration, which may still be the previous
+// track's.
+func (m *model) resumeAt(offsetMs int) tea.Cmd {
+	cmd := m.sendCommand(fmt.Sprintf("playback/seekTo?time=%d", offsetMs))
+	if cmd == nil {
+		return nil
+	}
+	m.lastCommand = "Resumed at " + formatTime(offsetMs)
+	m.positionMs = offsetMs
+	m.lastUpdate = time.Now()
+	return cmd
+}
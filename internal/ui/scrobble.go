@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"time"
+
+	"plexamp-tui/internal/config"
+	"plexamp-tui/internal/scrobble"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// minScrobbleDuration is the shortest track last.fm accepts scrobbles for
+const minScrobbleDuration = 30 * time.Second
+
+// maxScrobbleWait caps how long a long track has to play before it is scrobbled
+const maxScrobbleWait = 4 * time.Minute
+
+// scrobbleState tracks how long the current track has actually played, so
+// skipping through a track doesn't count as listening to it
+type scrobbleState struct {
+	track     string         // TrackText of the track being tracked
+	info      scrobble.Track // The track as reported to last.fm
+	startedAt time.Time      // When the track was first seen
+	countedAt time.Time      // When play time was last added up
+	playedMs  int            // Accumulated time spent playing
+	announced bool           // Whether now playing was sent
+	scrobbled bool           // Whether the scrobble was sent
+}
+
+// newScrobbler creates the last.fm scrobbler when scrobbling is enabled and configured
+func newScrobbler(c *config.Config) *scrobble.Scrobbler {
+	if c == nil || !c.ScrobbleEnabled {
+		return nil
+	}
+	if c.LastFMAPIKey == "" || c.LastFMAPISecret == "" || c.LastFMSessionKey == "" {
+		log.Warn("Scrobbling is enabled but the last.fm API key, secret or session key is missing")
+		return nil
+	}
+	return scrobble.NewScrobbler(c.LastFMAPIKey, c.LastFMAPISecret, c.LastFMSessionKey)
+}
+
+// trackScrobble updates the play time of the current track from a timeline
+// update and returns a command reporting it to last.fm when due
+func (m *model) trackScrobble(msg trackMsgWithState) tea.Cmd {
+	if scrobbler == nil {
+		return nil
+	}
+
+	s := &m.scrobble
+	// Time since the last count was spent on the track that was playing then
+	s.countPlayed(m.isPlaying)
+
+	var cmds []tea.Cmd
+	if msg.TrackText != s.track {
+		// The held long poll only answers once the next track starts, so a
+		// track that played straight through may only become due now
+		cmds = append(cmds, s.scrobbleCmd())
+		*s = scrobbleState{
+			track: msg.TrackText,
+			info: scrobble.Track{
+				Artist:   msg.Artist,
+				Title:    msg.Title,
+				Album:    msg.Album,
+				Duration: time.Duration(msg.Duration) * time.Millisecond,
+			},
+			startedAt: time.Now(),
+			countedAt: time.Now(),
+		}
+	}
+	if msg.Title == "" {
+		return tea.Batch(cmds...)
+	}
+
+	if msg.IsPlaying && !s.announced {
+		s.announced = true
+		track := s.info
+		cmds = append(cmds, func() tea.Msg {
+			if err := scrobbler.UpdateNowPlaying(track); err != nil {
+				log.Warn("Failed to update last.fm now playing: %v", err)
+			}
+			return nil
+		})
+	}
+	cmds = append(cmds, s.scrobbleCmd())
+	return tea.Batch(cmds...)
+}
+
+// scrobbleTick adds up play time on the progress tick, so a track is
+// scrobbled once it has played long enough rather than on the next timeline
+// update, which a held long poll may not send until the track ends
+func (m *model) scrobbleTick() tea.Cmd {
+	if scrobbler == nil {
+		return nil
+	}
+	m.scrobble.countPlayed(m.isPlaying)
+	return m.scrobble.scrobbleCmd()
+}
+
+// countPlayed adds the time since the last count to the play time when the
+// track was playing
+func (s *scrobbleState) countPlayed(playing bool) {
+	now := time.Now()
+	if playing && !s.countedAt.IsZero() {
+		s.playedMs += int(now.Sub(s.countedAt).Milliseconds())
+	}
+	s.countedAt = now
+}
+
+// scrobbleCmd returns a command scrobbling the track once it has played for
+// half its length, or maxScrobbleWait, and nil until then
+func (s *scrobbleState) scrobbleCmd() tea.Cmd {
+	if s.scrobbled || s.info.Title == "" || s.info.Duration < minScrobbleDuration {
+		return nil
+	}
+	threshold := s.info.Duration / 2
+	if threshold > maxScrobbleWait {
+		threshold = maxScrobbleWait
+	}
+	if time.Duration(s.playedMs)*time.Millisecond < threshold {
+		return nil
+	}
+
+	s.scrobbled = true
+	track, startedAt := s.info, s.startedAt
+	return func() tea.Msg {
+		if err := scrobbler.Scrobble(track, startedAt); err != nil {
+			log.Warn("Failed to scrobble to last.fm: %v", err)
+		} else {
+			log.Debug("Scrobbled %s - %s", track.Artist, track.Title)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"plexamp-tui/internal/scrobble"
+)
+
+// useTestScrobbler sets a scrobbler so play time is tracked. The commands it
+// returns are never run, so nothing is sent to last.fm.
+func useTestScrobbler(t *testing.T) {
+	t.Helper()
+	useTestConfig(t)
+	saved := scrobbler
+	t.Cleanup(func() { scrobbler = saved })
+	scrobbler = scrobble.NewScrobbler("key", "secret", "session")
+}
+
+func testTrack(title string, playing bool) trackMsgWithState {
+	return trackMsgWithState{
+		TrackText: "Fleetwood Mac - " + title,
+		Artist:    "Fleetwood Mac",
+		Title:     title,
+		IsPlaying: playing,
+		Duration:  int((4 * time.Minute).Milliseconds()),
+	}
+}
+
+func TestTrackChangeScrobblesFinishedTrack(t *testing.T) {
+	tests := []struct {
+		name     string
+		played   time.Duration
+		scrobble bool
+	}{
+		{name: "played through", played: 4 * time.Minute, scrobble: true},
+		{name: "skipped", played: 30 * time.Second, scrobble: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestScrobbler(t)
+			m := &model{}
+			m.trackScrobble(testTrack("Tusk", true))
+			m.isPlaying = true
+
+			// The held long poll sends nothing more until the next track starts
+			m.scrobble.countedAt = time.Now().Add(-tt.played)
+			cmd := m.trackScrobble(testTrack("Sara", false))
+
+			if got := cmd != nil; got != tt.scrobble {
+				t.Errorf("scrobbled Tusk after %v: %v, want %v", tt.played, got, tt.scrobble)
+			}
+			if m.scrobble.info.Title != "Sara" || m.scrobble.playedMs != 0 {
+				t.Errorf("tracking %q with %dms played, want Sara from the start", m.scrobble.info.Title, m.scrobble.playedMs)
+			}
+		})
+	}
+}
+
+func TestScrobbleTickScrobblesWithoutTimeline(t *testing.T) {
+	useTestScrobbler(t)
+	m := &model{}
+	m.trackScrobble(testTrack("Tusk", true))
+	m.isPlaying = true
+
+	if cmd := m.scrobbleTick(); cmd != nil {
+		t.Fatal("scrobbled a track that just started")
+	}
+	m.scrobble.countedAt = time.Now().Add(-2 * time.Minute)
+	if cmd := m.scrobbleTick(); cmd == nil {
+		t.Fatal("didn't scrobble a track played for half its length")
+	}
+	if cmd := m.scrobbleTick(); cmd != nil {
+		t.Error("scrobbled the same track twice")
+	}
+}
@@ -0,0 +1,189 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// serverEditLabels are the fields of the manual server form, in input order
+var serverEditLabels = []string{"Name:", "Client Identifier:", "Address:", "Port:"}
+
+// defaultServerPort is the port Plex Media Server listens on unless configured otherwise
+const defaultServerPort = "32400"
+
+// initServerEdit sets up the manual server form, prefilled with the configured server.
+// It is used when discovery through plex.tv can't find or reach the server.
+func (m *model) initServerEdit() {
+	name := textinput.New()
+	name.Placeholder = "My Server"
+	name.CharLimit = 100
+	name.Width = 50
+
+	clientID := textinput.New()
+	clientID.Placeholder = "Machine identifier (from /identity)"
+	clientID.CharLimit = 100
+	clientID.Width = 50
+
+	address := textinput.New()
+	address.Placeholder = "192.168.1.10"
+	address.CharLimit = 255
+	address.Width = 50
+
+	port := textinput.New()
+	port.Placeholder = defaultServerPort
+	port.CharLimit = 5
+	port.Width = 10
+
+	if m.config != nil {
+		name.SetValue(m.config.PlexServerName)
+		clientID.SetValue(m.config.ServerID)
+		host, p := splitServerAddr(m.config.PlexServerAddr)
+		address.SetValue(host)
+		port.SetValue(p)
+	}
+
+	m.editInputs = []textinput.Model{name, clientID, address, port}
+	m.updateServerEditFocus()
+}
+
+// splitServerAddr splits a stored address:port, which may carry a scheme, into its address and port
+func splitServerAddr(addr string) (string, string) {
+	i := strings.LastIndex(addr, ":")
+	if i < 0 {
+		return addr, ""
+	}
+	if _, err := strconv.Atoi(addr[i+1:]); err != nil {
+		return addr, ""
+	}
+	return addr[:i], addr[i+1:]
+}
+
+// handleServerEditUpdate processes key presses in the manual server form
+func (m *model) handleServerEditUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			m.cancelEdit()
+			return m, nil
+
+		case "enter":
+			cmd, err := m.saveServerEdit()
+			if err != nil {
+				m.lastCommand = fmt.Sprintf("Save failed: %v", err)
+				return m, nil
+			}
+			m.lastCommand = "Server saved"
+			return m, cmd
+
+		case "tab", "down":
+			m.editFocusIndex = (m.editFocusIndex + 1) % len(m.editInputs)
+			m.updateServerEditFocus()
+			return m, nil
+
+		case "shift+tab", "up":
+			m.editFocusIndex--
+			if m.editFocusIndex < 0 {
+				m.editFocusIndex = len(m.editInputs) - 1
+			}
+			m.updateServerEditFocus()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.editInputs[m.editFocusIndex], cmd = m.editInputs[m.editFocusIndex].Update(msg)
+	return m, cmd
+}
+
+// updateServerEditFocus focuses the selected input of the manual server form
+func (m *model) updateServerEditFocus() {
+	for i := range m.editInputs {
+		if i == m.editFocusIndex {
+			m.editInputs[i].Focus()
+		} else {
+			m.editInputs[i].Blur()
+		}
+	}
+}
+
+// saveServerEdit writes the manually entered server to the config, then loads
+// its libraries the same way selecting a discovered server does
+func (m *model) saveServerEdit() (tea.Cmd, error) {
+	if len(m.editInputs) < len(serverEditLabels) {
+		return nil, fmt.Errorf("missing input fields")
+	}
+	if m.config == nil {
+		return nil, fmt.Errorf("no config available")
+	}
+
+	name := strings.TrimSpace(m.editInputs[0].Value())
+	clientID := strings.TrimSpace(m.editInputs[1].Value())
+	address := strings.TrimSuffix(strings.TrimSpace(m.editInputs[2].Value()), "/")
+	port := strings.TrimSpace(m.editInputs[3].Value())
+
+	if clientID == "" {
+		return nil, fmt.Errorf("client identifier cannot be empty")
+	}
+	if address == "" {
+		return nil, fmt.Errorf("address cannot be empty")
+	}
+	if port == "" {
+		port = defaultServerPort
+	}
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		return nil, fmt.Errorf("invalid port %q", port)
+	}
+	if name == "" {
+		name = address
+	}
+
+	m.config.ServerID = clientID
+	m.config.PlexServerAddr = address + ":" + port
+	m.config.PlexServerURI = "" // Manual entries have no probed connection URI
+	m.config.PlexServerName = name
+	m.sessionsDenied = false
+	if err := cfgManager.Save(m.config); err != nil {
+		return nil, err
+	}
+
+	m.panelMode = "playback"
+	m.editInputs = nil
+
+	// Libraries are only refreshed once a player is selected, like the server browser
+	if m.selected == "" {
+		m.status = "Server saved; select a player to load its libraries"
+		return nil, nil
+	}
+	return m.selectServerCmd(serverItem{
+		title:            name,
+		clientIdentifier: clientID,
+		address:          address,
+		port:             port,
+	}), nil
+}
+
+// serverEditView renders the manual server form
+func (m model) serverEditView() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Underline(true)
+	content := titleStyle.Render("Enter Server Manually") + "\n\n"
+
+	for i, label := range serverEditLabels {
+		if i >= len(m.editInputs) {
+			break
+		}
+		if i == m.editFocusIndex {
+			label = "→ " + label
+		}
+		content += label + "\n" + m.editInputs[i].View() + "\n\n"
+	}
+
+	helpStyle := lipgloss.NewStyle().Foreground(theme.Muted).Render
+	content += "\n" + helpStyle("Enter: Save • Esc: Cancel • Tab/↑/↓: Switch fields")
+
+	return content
+}
@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// restorablePanels are the panels that can be reopened at startup, along with
+// whether they need Plex authentication to load
+var restorablePanels = map[string]bool{
+	"playback":       false,
+	"history":        false,
+	"plex-artists":   true,
+	"plex-albums":    true,
+	"plex-playlists": true,
+}
+
+// restoreLastPanel reopens the panel and selection saved when the app last quit.
+// Panels that need Plex authentication fall back to the favorites panel when
+// there is no valid token.
+func (m *model) restoreLastPanel() {
+	if m.config == nil {
+		return
+	}
+	panel := m.config.LastPanelMode
+	needsAuth, ok := restorablePanels[panel]
+	if !ok || (needsAuth && !m.plexAuthenticated) {
+		panel = "playback"
+	}
+
+	switch panel {
+	case "playback":
+		if m.config.LastSelectedIndex < len(m.playbackList.Items()) {
+			m.playbackList.Select(m.config.LastSelectedIndex)
+		}
+		return
+	case "history":
+		m.initHistoryBrowse()
+	case "plex-artists":
+		m.initArtistBrowse()
+	case "plex-albums":
+		m.initAlbumBrowse()
+	case "plex-playlists":
+		m.initPlaylistBrowse()
+	}
+	// The list is empty until the fetch started in Init completes
+	m.restoreIndex = m.config.LastSelectedIndex
+}
+
+// restoreSelection selects the saved index in a freshly loaded list, once
+func (m *model) restoreSelection(l *list.Model) {
+	if m.restoreIndex > 0 && m.restoreIndex < len(l.Items()) {
+		l.Select(m.restoreIndex)
+	}
+	m.restoreIndex = 0
+}
+
+// saveSession stores the current panel and selection in the config
+func (m *model) saveSession() {
+	if m.config == nil {
+		return
+	}
+
+	panel := m.panelMode
+	index := 0
+	switch panel {
+	case "playback":
+		index = m.playbackList.Index()
+	case "history":
+		index = m.historyList.Index()
+	case "plex-artists":
+		index = m.artistList.Index()
+	case "plex-albums":
+		// An album list scoped to one artist can't be rebuilt at startup
+		if m.albumFilter != "" {
+			panel = "playback"
+			index = m.playbackList.Index()
+		} else if m.albumArtistKey != "" {
+			panel = "plex-artists"
+			index = m.artistList.Index()
+		} else {
+			index = m.albumList.Index()
+		}
+	case "plex-playlists":
+		index = m.playlistList.Index()
+	default:
+		panel = "playback"
+		index = m.playbackList.Index()
+	}
+
+	m.config.LastPanelMode = panel
+	m.config.LastSelectedIndex = index
+	if err := cfgManager.Save(m.config); err != nil {
+		log.Error("Failed to save session state: %v", err)
+	}
+}
+
+// requestQuit quits, first asking for confirmation while something is playing
+func (m *model) requestQuit() tea.Cmd {
+	if !m.isPlaying {
+		return m.quit()
+	}
+	m.quitConfirm = true
+	m.status = "Quit while playing? (y/n)"
+	return nil
+}
+
+// handleQuitConfirm answers the quit confirmation; any key but y cancels it
+func (m *model) handleQuitConfirm(key string) tea.Cmd {
+	m.quitConfirm = false
+	if key == "y" || key == "Y" {
+		return m.quit()
+	}
+	m.status = ""
+	return nil
+}
+
+// quit saves the session state, shuts down the desktop integrations and exits the program
+func (m *model) quit() tea.Cmd {
+	m.saveSession()
+	closePresence()
+	closeMPRIS()
+	closeHTTPAPI()
+	return tea.Quit
+}
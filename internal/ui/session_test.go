@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"path/filepath"
+	"testing"
+
+	"plexamp-tui/internal/config"
+	"plexamp-tui/internal/logger"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// useTestConfig points the package-level config manager and logger at a
+// config file in a temporary directory and returns the loaded config
+func useTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+	testLog, err := logger.NewLogger(false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	log = testLog
+
+	cfgManager = loadTestConfigManager(t, filepath.Join(t.TempDir(), "config.json"))
+	return cfgManager.GetConfig()
+}
+
+// loadTestConfigManager loads the config at path through a new manager
+func loadTestConfigManager(t *testing.T, path string) *config.Manager {
+	t.Helper()
+	manager, err := config.NewManager(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.Load(); err != nil {
+		t.Fatal(err)
+	}
+	return manager
+}
+
+func testArtistList(count int) list.Model {
+	items := make([]list.Item, count)
+	for i := range items {
+		items[i] = artistItem{title: string(rune('A' + i))}
+	}
+	return list.New(items, list.NewDefaultDelegate(), 0, 0)
+}
+
+func TestSessionRoundTrip(t *testing.T) {
+	cfg := useTestConfig(t)
+
+	saved := model{config: cfg, panelMode: "plex-artists", artistList: testArtistList(5)}
+	saved.artistList.Select(3)
+	saved.saveSession()
+
+	// Reload the config from disk, as the next launch would
+	reloaded := loadTestConfigManager(t, cfgManager.GetConfigPath()).GetConfig()
+	if reloaded.LastPanelMode != "plex-artists" || reloaded.LastSelectedIndex != 3 {
+		t.Fatalf("saved panel %q index %d, want plex-artists index 3", reloaded.LastPanelMode, reloaded.LastSelectedIndex)
+	}
+
+	restored := model{config: reloaded, plexAuthenticated: true}
+	restored.restoreLastPanel()
+	if restored.panelMode != "plex-artists" {
+		t.Errorf("restored panel %q, want plex-artists", restored.panelMode)
+	}
+	if restored.restoreIndex != 3 {
+		t.Errorf("restore index %d, want 3", restored.restoreIndex)
+	}
+
+	// The selection is applied once the artists have loaded
+	restored.artistList = testArtistList(5)
+	restored.artistsTotal = 5
+	restored.restoreArtistSelection()
+	if got := restored.artistList.Index(); got != 3 {
+		t.Errorf("selected artist %d, want 3", got)
+	}
+}
+
+func TestSessionRestoreWithoutAuth(t *testing.T) {
+	cfg := useTestConfig(t)
+	cfg.LastPanelMode = "plex-artists"
+	cfg.LastSelectedIndex = 3
+
+	m := model{config: cfg, panelMode: "playback", plexAuthenticated: false, playbackList: testArtistList(2)}
+	m.restoreLastPanel()
+	if m.panelMode != "playback" {
+		t.Errorf("opened panel %q without a Plex token, want the favorites panel", m.panelMode)
+	}
+	if m.restoreIndex != 0 {
+		t.Errorf("restore index %d, want none", m.restoreIndex)
+	}
+}
+
+func TestSessionRestorePastLoadedArtists(t *testing.T) {
+	useTestConfig(t)
+
+	m := model{restoreIndex: 3, artistList: testArtistList(2), artistsTotal: 5}
+	m.restoreArtistSelection()
+	if m.restoreIndex != 3 {
+		t.Fatalf("dropped the restore index before its page loaded")
+	}
+
+	m.artistList = testArtistList(5)
+	m.restoreArtistSelection()
+	if got := m.artistList.Index(); got != 3 {
+		t.Errorf("selected artist %d, want 3", got)
+	}
+}
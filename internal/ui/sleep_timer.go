@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sleepTimerMsg fires when a sleep timer runs out
+type sleepTimerMsg struct {
+	ID int // Timer that scheduled this message; stale timers are ignored
+}
+
+// toggleSleepTimer cancels a running sleep timer, or prompts for a new one
+func (m *model) toggleSleepTimer() tea.Cmd {
+	if !m.sleepDeadline.IsZero() {
+		// Bumping the ID makes the pending tick a no-op
+		m.sleepTimerID++
+		m.sleepDeadline = time.Time{}
+		m.lastCommand = "Sleep timer cancelled"
+		return nil
+	}
+
+	input := textinput.New()
+	input.Placeholder = "minutes"
+	input.CharLimit = 4
+	input.Width = 8
+	m.sleepInput = input
+	m.sleepInputActive = true
+	return m.sleepInput.Focus()
+}
+
+// handleSleepInputUpdate processes key presses while the sleep timer prompt is open
+func (m *model) handleSleepInputUpdate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.sleepInputActive = false
+		return m, nil
+
+	case "enter":
+		m.sleepInputActive = false
+		value := strings.TrimSpace(m.sleepInput.Value())
+		minutes, err := strconv.Atoi(value)
+		if err != nil || minutes <= 0 {
+			m.status = fmt.Sprintf("Invalid sleep timer %q: enter a number of minutes", value)
+			return m, nil
+		}
+		return m, m.startSleepTimer(time.Duration(minutes) * time.Minute)
+	}
+
+	var cmd tea.Cmd
+	m.sleepInput, cmd = m.sleepInput.Update(msg)
+	return m, cmd
+}
+
+// startSleepTimer schedules playback to pause after d, replacing any running timer
+func (m *model) startSleepTimer(d time.Duration) tea.Cmd {
+	m.sleepTimerID++
+	id := m.sleepTimerID
+	m.sleepDeadline = time.Now().Add(d)
+	m.lastCommand = fmt.Sprintf("Sleep in %d min", int(d.Minutes()))
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return sleepTimerMsg{ID: id}
+	})
+}
+
+// handleSleepTimer pauses playback when the current sleep timer runs out
+func (m *model) handleSleepTimer(msg sleepTimerMsg) tea.Cmd {
+	if msg.ID != m.sleepTimerID || m.sleepDeadline.IsZero() {
+		return nil
+	}
+	m.sleepDeadline = time.Time{}
+	m.lastCommand = "Sleep timer: paused"
+	if !m.isPlaying {
+		return nil
+	}
+	cmd := m.sendCommand("playback/pause")
+	if cmd != nil {
+		m.isPlaying = false
+	}
+	return cmd
+}
+
+// sleepRemaining returns how long is left on the sleep timer
+func (m model) sleepRemaining() time.Duration {
+	remaining := time.Until(m.sleepDeadline)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// sleepInputView renders the sleep timer prompt
+func (m model) sleepInputView() string {
+	return fmt.Sprintf("Sleep after: %s min (Enter to start, Esc to cancel)", m.sleepInput.View())
+}
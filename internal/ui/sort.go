@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Orders the artist and album lists can be sorted in
+const (
+	sortArtist = "artist" // Albums only: by artist, as the server returns them
+	sortTitle  = "title"
+	sortYear   = "year" // Albums only
+	sortAdded  = "added"
+	sortPlays  = "plays"
+)
+
+var (
+	// artistSortModes and albumSortModes list the orders s cycles through; the first is the default
+	artistSortModes = []string{sortTitle, sortAdded, sortPlays}
+	albumSortModes  = []string{sortArtist, sortTitle, sortYear, sortAdded, sortPlays}
+)
+
+// artistServerSorts are the server-side orders of the artist sort modes, used
+// while only some pages of the library are loaded
+var artistServerSorts = map[string]string{
+	sortTitle: "titleSort",
+	sortAdded: "addedAt:desc",
+	sortPlays: "viewCount:desc",
+}
+
+// sortLabels describe each order in the status line
+var sortLabels = map[string]string{
+	sortArtist: "artist",
+	sortTitle:  "title",
+	sortYear:   "year (newest first)",
+	sortAdded:  "date added (newest first)",
+	sortPlays:  "play count (most played first)",
+}
+
+// validSortMode returns mode if it is one of modes, otherwise the default
+func validSortMode(modes []string, mode string) string {
+	for _, m := range modes {
+		if m == mode {
+			return mode
+		}
+	}
+	return modes[0]
+}
+
+// nextSortMode returns the order after current in modes
+func nextSortMode(modes []string, current string) string {
+	for i, m := range modes {
+		if m == current {
+			return modes[(i+1)%len(modes)]
+		}
+	}
+	return modes[0]
+}
+
+// sortTitleKey is the case-insensitive title used for sorting, ignoring the favorite mark
+func sortTitleKey(title string) string {
+	return strings.ToLower(strings.TrimSuffix(title, " ★"))
+}
+
+// sortArtistItems orders artists in place
+func sortArtistItems(items []list.Item, mode string) {
+	var less func(a, b artistItem) bool
+	switch mode {
+	case sortAdded:
+		less = func(a, b artistItem) bool { return a.addedAt > b.addedAt }
+	case sortPlays:
+		less = func(a, b artistItem) bool { return a.viewCount > b.viewCount }
+	default:
+		less = func(a, b artistItem) bool { return sortTitleKey(a.title) < sortTitleKey(b.title) }
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, aok := items[i].(artistItem)
+		b, bok := items[j].(artistItem)
+		return aok && bok && less(a, b)
+	})
+}
+
+// sortAlbumItems orders albums in place
+func sortAlbumItems(items []list.Item, mode string) {
+	var less func(a, b albumItem) bool
+	switch mode {
+	case sortTitle:
+		less = func(a, b albumItem) bool { return sortTitleKey(a.title) < sortTitleKey(b.title) }
+	case sortYear:
+		less = func(a, b albumItem) bool {
+			ay, _ := strconv.Atoi(a.year)
+			by, _ := strconv.Atoi(b.year)
+			return ay > by
+		}
+	case sortAdded:
+		less = func(a, b albumItem) bool { return a.addedAt > b.addedAt }
+	case sortPlays:
+		less = func(a, b albumItem) bool { return a.viewCount > b.viewCount }
+	default:
+		less = func(a, b albumItem) bool { return strings.ToLower(a.artist) < strings.ToLower(b.artist) }
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, aok := items[i].(albumItem)
+		b, bok := items[j].(albumItem)
+		return aok && bok && less(a, b)
+	})
+}
+
+// cycleArtistSort switches the artist list to the next order and remembers it.
+// A library that is only partly loaded is refetched from the start in the new
+// order, since the loaded pages alone aren't the top of it.
+func (m *model) cycleArtistSort() tea.Cmd {
+	m.artistSort = nextSortMode(artistSortModes, m.artistSort)
+	m.config.ArtistSort = m.artistSort
+	cfgManager.Save(m.config)
+
+	if len(m.artistList.Items()) < m.artistsTotal {
+		m.endJump()
+		m.status = fmt.Sprintf("Loading artists sorted by %s...", sortLabels[m.artistSort])
+		return m.fetchArtistsCmd()
+	}
+
+	items := append([]list.Item(nil), m.artistList.Items()...)
+	sortArtistItems(items, m.artistSort)
+	m.artistList.SetItems(items)
+	m.artistList.ResetSelected()
+	m.status = fmt.Sprintf("Artists sorted by %s", sortLabels[m.artistSort])
+	return nil
+}
+
+// cycleAlbumSort switches the album list to the next order and remembers it
+func (m *model) cycleAlbumSort() {
+	m.albumSort = nextSortMode(albumSortModes, m.albumSort)
+	m.config.AlbumSort = m.albumSort
+	cfgManager.Save(m.config)
+
+	items := append([]list.Item(nil), m.albumList.Items()...)
+	sortAlbumItems(items, m.albumSort)
+	m.albumList.SetItems(items)
+	m.albumList.ResetSelected()
+	m.status = fmt.Sprintf("Albums sorted by %s", sortLabels[m.albumSort])
+}
+
+// sortHelpKey describes the sort key shared by the artist and album lists
+func sortHelpKey() key.Binding {
+	return key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "Change Sort Order"),
+	)
+}
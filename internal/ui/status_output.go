@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"errors"
+
+	"plexamp-tui/internal/config"
+	"plexamp-tui/internal/statusfile"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newStatusWriter creates the status output writer when a path is configured
+func newStatusWriter(c *config.Config) *statusfile.Writer {
+	if c == nil || c.StatusOutputPath == "" {
+		return nil
+	}
+	return statusfile.New(c.StatusOutputPath, c.StatusOutputFormat)
+}
+
+// writeStatusOutput returns a command writing a timeline update to the
+// status output, or nil when the line written would not change
+func (m *model) writeStatusOutput(msg trackMsgWithState) tea.Cmd {
+	if statusWriter == nil {
+		return nil
+	}
+
+	state := statusfile.State{
+		State:    "paused",
+		Artist:   msg.Artist,
+		Title:    msg.Title,
+		Album:    msg.Album,
+		Position: formatTime(msg.Position),
+		Duration: formatTime(msg.Duration),
+		Volume:   msg.Volume,
+	}
+	switch {
+	case msg.Stopped || msg.Title == "":
+		state = statusfile.State{State: "stopped", Volume: msg.Volume}
+	case msg.IsPlaying:
+		state.State = "playing"
+	}
+
+	line := statusWriter.Format(state)
+	if line == m.statusOutput {
+		return nil
+	}
+	m.statusOutput = line
+
+	return func() tea.Msg {
+		if err := statusWriter.Write(line); err != nil {
+			if errors.Is(err, statusfile.ErrNoReader) {
+				log.Debug("Status output %s has no reader, skipped an update", cfg.StatusOutputPath)
+			} else {
+				log.Warn("Failed to write status output: %v", err)
+			}
+		}
+		return nil
+	}
+}
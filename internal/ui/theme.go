@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme holds the named colors the views are drawn with
+type Theme struct {
+	Name      string
+	Title     lipgloss.Color // App title and focused borders
+	Accent    lipgloss.Color // Section headings
+	Value     lipgloss.Color // Current values such as the playing track
+	Info      lipgloss.Color // Key hints and the controls list
+	Label     lipgloss.Color // Field labels
+	Positive  lipgloss.Color // Settings that are on
+	Negative  lipgloss.Color // Settings that are off, warnings
+	Muted     lipgloss.Color // Help text and unfocused borders
+	Highlight lipgloss.Color // Background of selected options
+}
+
+// themes are the built-in color schemes, in the order they are cycled through
+var themes = []Theme{
+	{
+		Name:      "default",
+		Title:     "#00ffff",
+		Accent:    "#ffaa00",
+		Value:     "#00ffcc",
+		Info:      "#8888ff",
+		Label:     "#aaaaaa",
+		Positive:  "#00ff00",
+		Negative:  "#ff5555",
+		Muted:     "240",
+		Highlight: "62",
+	},
+	{
+		Name:      "mono",
+		Title:     "#ffffff",
+		Accent:    "#ffffff",
+		Value:     "#ffffff",
+		Info:      "#bbbbbb",
+		Label:     "#999999",
+		Positive:  "#ffffff",
+		Negative:  "#777777",
+		Muted:     "#666666",
+		Highlight: "#444444",
+	},
+	{
+		Name:      "solarized",
+		Title:     "#268bd2",
+		Accent:    "#b58900",
+		Value:     "#2aa198",
+		Info:      "#6c71c4",
+		Label:     "#93a1a1",
+		Positive:  "#859900",
+		Negative:  "#dc322f",
+		Muted:     "#586e75",
+		Highlight: "#073642",
+	},
+}
+
+// theme is the active color scheme
+var theme = themes[0]
+
+// applyTheme makes the named theme active, falling back to the default for
+// unknown names, and rebuilds the shared styles that depend on it
+func applyTheme(name string) {
+	theme = themes[0]
+	for _, t := range themes {
+		if t.Name == name {
+			theme = t
+			break
+		}
+	}
+	helpStyle = lipgloss.NewStyle().Foreground(theme.Muted).Margin(1, 0, 0, 2)
+}
+
+// nextTheme returns the name of the theme after the active one
+func nextTheme() string {
+	for i, t := range themes {
+		if t.Name == theme.Name {
+			return themes[(i+1)%len(themes)].Name
+		}
+	}
+	return themes[0].Name
+}
+
+// cycleTheme switches to the next built-in theme and saves the choice
+func (m *model) cycleTheme() tea.Cmd {
+	applyTheme(nextTheme())
+	m.restyleLists()
+	m.lastCommand = "Theme " + theme.Name
+	if m.config != nil {
+		m.config.Theme = theme.Name
+		cfgManager.Save(m.config)
+	}
+	return nil
+}
+
+// restyleLists reapplies the theme-dependent styles to lists that were built
+// before the theme changed
+func (m *model) restyleLists() {
+	for _, l := range []*list.Model{
+		&m.playbackList, &m.artistList, &m.albumList, &m.trackList, &m.playlistList, &m.genreList,
+		&m.serverList, &m.playerList, &m.historyList, &m.profileList, &m.queueList, &m.libraryList,
+	} {
+		l.Styles.HelpStyle = helpStyle
+	}
+}
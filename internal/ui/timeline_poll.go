@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The timeline is fetched with wait=1, which has the player hold the request
+// until its state changes. Each answer sends the next poll, so a change shows
+// up as soon as it happens instead of on the next tick of a timer.
+
+// longPollFallback is how long a poll may go unanswered before another is
+// sent in its place, in case the request hangs
+const longPollFallback = 30 * time.Second
+
+// longPollClient sends the loop polls. The player may hold them for as long
+// as nothing changes, so unlike the shared Plex client its timeout outlasts
+// longPollFallback: a held poll is replaced by the fallback, and its answer
+// dropped, rather than failing as if the player were unreachable. Connecting
+// still times out quickly, so an unreachable player is noticed.
+var longPollClient = &http.Client{
+	Timeout: longPollFallback + 15*time.Second,
+	Transport: &http.Transport{
+		Proxy:       http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{Timeout: 5 * time.Second}).DialContext,
+	},
+}
+
+// pollIntervals are the timeline poll intervals the poll interval key steps through
+var pollIntervals = []time.Duration{time.Second, 2 * time.Second, 5 * time.Second, 10 * time.Second}
+
+// startPolling starts the polling loop. It is sent as a message rather than
+// polling directly, since Init can't update the model.
+func startPolling(seq int) tea.Cmd {
+	return func() tea.Msg { return pollMsg{seq: seq} }
+}
+
+// loopPollCmd sends the next poll of the loop, along with the fallback that
+// replaces it if no answer comes. Answers to earlier loop polls are ignored
+// from then on. With no player selected the loop stops until one is.
+func (m *model) loopPollCmd() tea.Cmd {
+	if m.selected == "" {
+		return nil
+	}
+	m.pollSeq++
+	m.pollStarted = time.Now()
+	seq := m.pollSeq
+	return tea.Batch(m.timelineCmd(seq), tea.Tick(longPollFallback, func(time.Time) tea.Msg {
+		return pollMsg{seq: seq, fallback: true}
+	}))
+}
+
+// nextPollCmd follows an answer to the latest loop poll with the next one. A
+// player that answers at once, rather than holding the request, is polled no
+// more often than pollInterval, and an unreachable one with growing delays.
+func (m *model) nextPollCmd(failed bool) tea.Cmd {
+	m.pollAnswered = m.pollSeq
+	delay := m.pollInterval - time.Since(m.pollStarted)
+	if failed {
+		delay = m.pollDelay()
+	}
+	if delay <= 0 {
+		return m.loopPollCmd()
+	}
+	seq := m.pollSeq
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return pollMsg{seq: seq}
+	})
+}
+
+// handlePollMsg sends the next loop poll once its delay is up, or when the
+// latest poll has gone unanswered for too long. Messages for a poll the loop
+// has moved on from are dropped.
+func (m *model) handlePollMsg(msg pollMsg) tea.Cmd {
+	if msg.seq != m.pollSeq {
+		return nil
+	}
+	if msg.fallback {
+		if m.pollAnswered >= msg.seq {
+			return nil
+		}
+		log.Debug("Timeline poll to %s unanswered after %s, polling again", m.selected, longPollFallback)
+	}
+	return m.loopPollCmd()
+}
+
+// cyclePollInterval switches to the next interval in pollIntervals for this
+// session, for trying out how often the player needs polling. It takes effect
+// from the next answer.
+func (m *model) cyclePollInterval() {
+	next := pollIntervals[0]
+	for _, interval := range pollIntervals {
+		if interval > m.pollInterval {
+			next = interval
+			break
+		}
+	}
+	m.pollInterval = next
+	m.lastCommand = fmt.Sprintf("Polling at most every %s", next)
+}
@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"testing"
+)
+
+func TestParseTimeline(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    trackMsgWithState
+		wantErr bool
+	}{
+		{
+			name: "empty container",
+			body: `<MediaContainer commandID="1"></MediaContainer>`,
+			want: trackMsgWithState{Stopped: true},
+		},
+		{
+			name: "no music timeline",
+			body: `<MediaContainer><Timeline type="video" state="playing" time="1000"/></MediaContainer>`,
+			want: trackMsgWithState{Stopped: true},
+		},
+		{
+			name: "stopped",
+			body: `<MediaContainer><Timeline type="music" state="stopped" volume="40" time="1000" duration="2000">
+				<Track title="Teardrop" grandparentTitle="Massive Attack" parentTitle="Mezzanine"/>
+			</Timeline></MediaContainer>`,
+			want: trackMsgWithState{Stopped: true, Volume: 40},
+		},
+		{
+			name: "playing",
+			body: `<MediaContainer><Timeline type="music" state="playing" volume="40" time="1000" duration="2000">
+				<Track title="Teardrop" grandparentTitle="Massive Attack" parentTitle="Mezzanine" ratingKey="7"/>
+			</Timeline></MediaContainer>`,
+			want: trackMsgWithState{
+				TrackText: "Massive Attack - Teardrop (Mezzanine)",
+				Artist:    "Massive Attack",
+				Title:     "Teardrop",
+				Album:     "Mezzanine",
+				IsPlaying: true,
+				Duration:  2000,
+				Position:  1000,
+				Volume:    40,
+				TrackKey:  "7",
+			},
+		},
+		{
+			name:    "not xml",
+			body:    `<html>Bad Gateway`,
+			want:    trackMsgWithState{Stopped: true},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTimeline([]byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error %v, want error: %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmptyTimelineClearsTrack(t *testing.T) {
+	useTestConfig(t)
+	m := model{
+		currentTrack: "Massive Attack - Teardrop (Mezzanine)",
+		current:      currentItems{trackKey: "7", title: "Teardrop"},
+		isPlaying:    true,
+		durationMs:   2000,
+		positionMs:   1000,
+	}
+
+	msg, err := parseTimeline([]byte(`<MediaContainer></MediaContainer>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, _ := m.update(msg)
+	m = next.(model)
+
+	if m.currentTrack != "" || m.current.trackKey != "" || m.current.title != "" {
+		t.Errorf("track still shown: %q (%+v)", m.currentTrack, m.current)
+	}
+	if m.durationMs != 0 || m.positionMs != 0 {
+		t.Errorf("duration %d and position %d, want 0", m.durationMs, m.positionMs)
+	}
+	if m.isPlaying || !m.isStopped {
+		t.Errorf("playing %v, stopped %v; want stopped", m.isPlaying, m.isStopped)
+	}
+}
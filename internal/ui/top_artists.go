@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"plexamp-tui/internal/config"
+	"plexamp-tui/internal/plex"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	defaultTopArtists = 10  // Suggested number of artists to import
+	maxTopArtists     = 100 // Most artists imported at once
+)
+
+// topArtistsMsg carries the most played artists fetched for importing
+type topArtistsMsg struct {
+	artists []plex.PlexArtist
+	err     error
+}
+
+// openTopArtistsInput asks how many of the most played artists to add to
+// the favorites
+func (m *model) openTopArtistsInput() tea.Cmd {
+	input := textinput.New()
+	input.Placeholder = fmt.Sprintf("1-%d", maxTopArtists)
+	input.CharLimit = 3
+	input.Width = 5
+	input.SetValue(strconv.Itoa(defaultTopArtists))
+	input.CursorEnd()
+	m.topArtistsInput = input
+	m.topArtistsInputActive = true
+	return m.topArtistsInput.Focus()
+}
+
+// handleTopArtistsInputUpdate processes key presses while the prompt is open
+func (m *model) handleTopArtistsInputUpdate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.topArtistsInputActive = false
+		return m, nil
+
+	case "enter":
+		m.topArtistsInputActive = false
+		value := strings.TrimSpace(m.topArtistsInput.Value())
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxTopArtists {
+			m.status = fmt.Sprintf("Invalid number %q: enter a number from 1 to %d", value, maxTopArtists)
+			return m, nil
+		}
+		return m, m.fetchTopArtistsCmd(n)
+	}
+
+	var cmd tea.Cmd
+	m.topArtistsInput, cmd = m.topArtistsInput.Update(msg)
+	return m, cmd
+}
+
+// topArtistsInputView renders the prompt
+func (m model) topArtistsInputView() string {
+	return fmt.Sprintf("Add top artists to favorites: %s (Enter to add, Esc to cancel)", m.topArtistsInput.View())
+}
+
+// fetchTopArtistsCmd fetches the n most played artists of the library
+func (m *model) fetchTopArtistsCmd(n int) tea.Cmd {
+	if m.config == nil {
+		return nil
+	}
+	token := plexClient.GetPlexToken()
+	serverAddr := m.config.ServerURL()
+	libraryID := m.config.PlexLibraryID
+	types := m.libraryTypes()
+	m.status = fmt.Sprintf("Fetching the %d most played artists...", n)
+
+	return func() tea.Msg {
+		artists, err := plexClient.TopArtists(serverAddr, libraryID, types, token, n)
+		return topArtistsMsg{artists: artists, err: err}
+	}
+}
+
+// importTopArtists adds the fetched artists to the favorites, skipping the
+// ones already there, and stars them in the artist list
+func (m *model) importTopArtists(msg topArtistsMsg) {
+	if msg.err != nil {
+		if m.handleAuthError(msg.err) {
+			return
+		}
+		m.status = fmt.Sprintf("Error fetching top artists: %v", msg.err)
+		return
+	}
+	if len(msg.artists) == 0 {
+		m.status = "No played artists to add"
+		return
+	}
+
+	added, skipped := 0, 0
+	for _, artist := range msg.artists {
+		exists, err := favsManager.Has("artist", artist.RatingKey)
+		if err != nil {
+			m.status = fmt.Sprintf("Error checking favorites: %v", err)
+			return
+		}
+		if exists {
+			skipped++
+			continue
+		}
+		if err := favsManager.Add(config.FavoriteItem{Name: artist.Title, Type: "artist", MetadataKey: artist.RatingKey}); err != nil {
+			m.status = fmt.Sprintf("Error adding favorite: %v", err)
+			return
+		}
+		added++
+	}
+
+	if err := m.reloadFavorites(); err != nil {
+		m.status = fmt.Sprintf("Error loading favorites: %v", err)
+		return
+	}
+	m.markFavoriteArtists()
+
+	m.lastCommand = "Imported top artists"
+	m.status = fmt.Sprintf("Added %d top artists to favorites", added)
+	if skipped > 0 {
+		m.status += fmt.Sprintf(", %d already there", skipped)
+	}
+}
+
+// markFavoriteArtists stars the loaded artists that are favorites
+func (m *model) markFavoriteArtists() {
+	favSet := m.getCurrentFavSet()
+	for i, listItem := range m.artistList.Items() {
+		artist, ok := listItem.(artistItem)
+		if !ok || strings.HasSuffix(artist.title, " ★") {
+			continue
+		}
+		if _, ok := favSet[artist.ratingKey]; ok {
+			artist.ToggleFavorite()
+			m.artistList.SetItem(i, artist)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"plexamp-tui/internal/plex"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// upNextMsg carries the play queue fetched to find the track after the
+// playing one
+type upNextMsg struct {
+	queueItemID string
+	queue       *plex.PlexPlayQueue
+	err         error
+}
+
+// fetchUpNextCmd looks up the track queued after the playing one. It is
+// called when the player moves on to another queue item and when something
+// is added to the queue.
+func (m *model) fetchUpNextCmd() tea.Cmd {
+	m.upNext = nil
+	if m.playQueueID == "" || m.config == nil || !m.plexAuthenticated {
+		return nil
+	}
+
+	token := plexClient.GetPlexToken()
+	serverAddr := m.config.ServerURL()
+	playQueueID := m.playQueueID
+	queueItemID := m.playQueueItemID
+
+	return func() tea.Msg {
+		queue, err := plexClient.FetchPlayQueue(serverAddr, playQueueID, token)
+		return upNextMsg{queueItemID: queueItemID, queue: queue, err: err}
+	}
+}
+
+// handleUpNext stores the next track, unless the player has moved on since.
+// The line is simply left out when the queue can't be fetched.
+func (m *model) handleUpNext(msg upNextMsg) {
+	if msg.queueItemID != m.playQueueItemID {
+		return
+	}
+	if msg.err != nil {
+		log.Debug("No up next track for queue item %s: %v", msg.queueItemID, msg.err)
+		return
+	}
+	m.setUpNext(msg.queue)
+}
+
+// setUpNext finds the track after the playing one in a fetched play queue
+func (m *model) setUpNext(queue *plex.PlexPlayQueue) {
+	m.upNext = nil
+	if queue == nil {
+		return
+	}
+	current := m.playQueueItemID
+	if current == "" {
+		current = queue.SelectedItemID
+	}
+	for i, track := range queue.Tracks {
+		if track.PlayQueueItemID == current && i+1 < len(queue.Tracks) {
+			next := queue.Tracks[i+1]
+			m.upNext = &next
+			return
+		}
+	}
+}
+
+// upNextLabel describes the next track for the Now Playing panel
+func (m model) upNextLabel() string {
+	if m.upNext == nil {
+		return ""
+	}
+	if m.upNext.GrandparentTitle == "" {
+		return m.upNext.Title
+	}
+	return m.upNext.GrandparentTitle + " - " + m.upNext.Title
+}
+
+// restartTrack plays the current track again from the start
+func (m *model) restartTrack() tea.Cmd {
+	if m.current.trackKey == "" {
+		return nil
+	}
+	cmd := m.seekTo(0)
+	m.lastCommand = "Restarted " + m.current.title
+	return cmd
+}
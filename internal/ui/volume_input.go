@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openVolumeInput shows a prompt for typing an absolute volume
+func (m *model) openVolumeInput() tea.Cmd {
+	input := textinput.New()
+	input.Placeholder = "0-100"
+	input.CharLimit = 3
+	input.Width = 5
+	input.SetValue(strconv.Itoa(m.volume))
+	input.CursorEnd()
+	m.volumeInput = input
+	m.volumeInputActive = true
+	return m.volumeInput.Focus()
+}
+
+// handleVolumeInputUpdate processes key presses while the volume prompt is open
+func (m *model) handleVolumeInputUpdate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.volumeInputActive = false
+		return m, nil
+
+	case "enter":
+		m.volumeInputActive = false
+		value := strings.TrimSpace(m.volumeInput.Value())
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			m.status = fmt.Sprintf("Invalid volume %q: enter a number from 0 to 100", value)
+			return m, nil
+		}
+		if v < 0 {
+			v = 0
+		} else if v > 100 {
+			v = 100
+		}
+		cmd := m.setVolume(v)
+		if cmd != nil {
+			m.forgetMute()
+			m.lastCommand = fmt.Sprintf("Volume %d%%", v)
+		}
+		return m, cmd
+	}
+
+	var cmd tea.Cmd
+	m.volumeInput, cmd = m.volumeInput.Update(msg)
+	return m, cmd
+}
+
+// volumeInputView renders the volume prompt
+func (m model) volumeInputView() string {
+	return fmt.Sprintf("Set volume: %s (Enter to apply, Esc to cancel)", m.volumeInput.View())
+}
@@ -0,0 +1,52 @@
+// Package version holds the build information of the running binary.
+//
+// The values are set at build time with ldflags, e.g.
+//
+//	go build -ldflags "-X plexamp-tui/internal/version.Version=1.2.0 \
+//	  -X plexamp-tui/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X plexamp-tui/internal/version.Date=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+var (
+	Version = "dev"     // Release version
+	Commit  = "unknown" // Git commit the binary was built from
+	Date    = "unknown" // Build date
+)
+
+func init() {
+	// Fall back to the VCS details Go embeds when ldflags weren't used
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+	if Version == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		Version = info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if Commit == "unknown" {
+				Commit = setting.Value
+				if len(Commit) > 12 {
+					Commit = Commit[:12]
+				}
+			}
+		case "vcs.time":
+			if Date == "unknown" {
+				Date = setting.Value
+			}
+		}
+	}
+}
+
+// String returns the version, commit, build date and Go version for --version
+func String() string {
+	return fmt.Sprintf("plexamp-tui %s (commit %s, built %s, %s %s/%s)",
+		Version, Commit, Date, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"plexamp-tui/internal/config"
 	"plexamp-tui/internal/database"
 	"plexamp-tui/internal/logger"
 	"plexamp-tui/internal/plex"
 	"plexamp-tui/internal/ui"
+	"plexamp-tui/internal/version"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -34,8 +36,22 @@ func main() {
 	configFlag := flag.String("config", "", "Path to configuration file (optional)")
 	flag.BoolVar(&debug, "debug", false, "Enable debug logging")
 	authFlag := flag.Bool("auth", false, "Authenticate with Plex.tv")
+	profileFlag := flag.String("profile", "", "Plex auth profile to use (default profile if empty)")
+	logoutFlag := flag.Bool("logout", false, "Sign out of Plex.tv and remove the stored token")
+	exportFlag := flag.String("export-favorites", "", "Export favorites to a JSON or .m3u file and exit")
+	importFlag := flag.String("import-favorites", "", "Import favorites from a JSON or .m3u file and exit")
+	versionFlag := flag.Bool("version", false, "Print version information and exit")
+	playFavoriteFlag := flag.String("play-favorite", "", "Play the favorite with this name on the selected player and exit")
+	playKeyFlag := flag.String("play-key", "", "Play the item with this rating key on the selected player and exit (needs --type)")
+	typeFlag := flag.String("type", "", "Type of the --play-key item: artist, album, track, playlist, station, genre or decade")
+	dbMaintenanceFlag := flag.Bool("db-maintenance", false, "Compact and optimize the favorites and history database and exit")
 	flag.Parse()
 
+	if *versionFlag {
+		fmt.Println(version.String())
+		return
+	}
+
 	// Initialize config
 	cfgManager, err = config.NewManager(*configFlag)
 	if err != nil {
@@ -54,13 +70,21 @@ func main() {
 		os.Exit(1)
 	}
 	defer log.Close()
+	log.SetRotation(cfg.LogMaxSizeMB, cfg.LogMaxBackups)
+	if cfgManager.CorruptBackup != "" {
+		log.Warn("Config file could not be read (%v), moved it to %s and started with the defaults", cfgManager.CorruptErr, cfgManager.CorruptBackup)
+	}
+	for _, migration := range cfgManager.Migrations {
+		log.Info("Migrated config to %s", migration)
+	}
 
-	plexClient = plex.NewPlexClient(log)
+	plexClient = plex.NewPlexClient(log, cfg.RequestTimeout())
+	plexClient.SetProfile(*profileFlag)
 
 	// Handle Plex authentication
 	if *authFlag {
-		fmt.Println("Starting Plex authentication...")
-		_, err := plexClient.AuthenticateWithPlex()
+		fmt.Printf("Starting Plex authentication for profile %q...\n", plexClient.Profile())
+		_, err := plexClient.AuthenticateWithPlex(*profileFlag)
 		if err != nil {
 			fmt.Printf("Authentication failed: %v\n", err)
 			os.Exit(1)
@@ -69,6 +93,20 @@ func main() {
 		return
 	}
 
+	// Handle Plex logout
+	if *logoutFlag {
+		if plexClient.GetPlexToken() == "" {
+			fmt.Println("No Plex token stored, nothing to log out.")
+			return
+		}
+		if err := plexClient.ClearPlexAuth(); err != nil {
+			fmt.Printf("Logout failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Logged out of Plex. Run with --auth to sign in again.")
+		return
+	}
+
 	// Initialize database
 	dbPath := filepath.Join(cfgManager.GetConfigDir(), "favorites.db")
 	db, err := database.New(dbPath)
@@ -76,6 +114,19 @@ func main() {
 		log.Fatal("Failed to initialize database: %v", err)
 	}
 	defer db.Close()
+	if db.CorruptBackup != "" {
+		log.Warn("Database could not be read (%v), moved it to %s and started with an empty one", db.CorruptErr, db.CorruptBackup)
+	}
+
+	if *dbMaintenanceFlag {
+		reclaimed, err := db.Maintain()
+		if err != nil {
+			fmt.Printf("Database maintenance failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Database maintenance done, reclaimed %.1f KB\n", float64(reclaimed)/1024)
+		return
+	}
 
 	// Initialize favorites manager
 	favsManager, err := config.NewFavoritesManager(db)
@@ -87,14 +138,76 @@ func main() {
 	jsonPath := filepath.Join(cfgManager.GetConfigDir(), "favorites.json")
 	if err := favsManager.MigrateFromJSON(jsonPath); err != nil {
 		log.Warn("Failed to migrate favorites from JSON: %v", err)
+	} else if db.CorruptBackup != "" {
+		if _, err := os.Stat(jsonPath); err == nil {
+			log.Info("Restored favorites from %s", jsonPath)
+		}
+	}
+	// Initialize play history manager
+	historyManager, err := config.NewHistoryManager(db)
+	if err != nil {
+		log.Fatal("Failed to initialize history manager: %v", err)
 	}
+
+	// Handle favorites export/import
+	if *exportFlag != "" {
+		count, err := favsManager.Export(*exportFlag)
+		if err != nil {
+			fmt.Printf("Export failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Exported %d favorites to %s\n", count, *exportFlag)
+		return
+	}
+	if *importFlag != "" {
+		count, err := favsManager.Import(*importFlag)
+		if err != nil {
+			fmt.Printf("Import failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Imported %d favorites from %s\n", count, *importFlag)
+		return
+	}
+
 	// Load favorites
 	favs, err := favsManager.Load()
 	if err != nil {
 		log.Fatal("Failed to load favorites: %v", err)
 	}
 
-	uiManager := ui.NewUiManager(log, cfg, cfgManager, favs, plexClient, favsManager)
+	// Handle one-shot playback
+	if *playFavoriteFlag != "" || *playKeyFlag != "" {
+		item := config.FavoriteItem{Type: *typeFlag, MetadataKey: *playKeyFlag}
+		if *playFavoriteFlag != "" {
+			found := false
+			for _, fav := range favs.Items {
+				if strings.EqualFold(fav.Name, *playFavoriteFlag) {
+					item, found = fav, true
+					break
+				}
+			}
+			if !found {
+				fmt.Printf("No favorite named %q\n", *playFavoriteFlag)
+				os.Exit(1)
+			}
+		} else if *typeFlag == "" {
+			fmt.Println("--play-key needs --type")
+			os.Exit(1)
+		}
+
+		label := item.Name
+		if label == "" {
+			label = fmt.Sprintf("%s %s", item.Type, item.MetadataKey)
+		}
+		if err := ui.PlayOnce(log, cfg, plexClient, historyManager, item); err != nil {
+			fmt.Printf("Playback of %s failed: %v\n", label, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Playing %s on %s\n", label, cfg.SelectedPlayerName)
+		return
+	}
+
+	uiManager := ui.NewUiManager(log, cfg, cfgManager, favs, plexClient, favsManager, historyManager)
 
 	p := tea.NewProgram(uiManager.Model, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
@@ -103,8 +216,8 @@ func main() {
 
 	serverInfo, err := plexClient.GetPlexServerInformation()
 	if err != nil {
-		log.Debug(fmt.Sprintf("Error getting server information: %v", err))
+		log.Debug("Error getting server information: %v", err)
 		os.Exit(1)
 	}
-	log.Debug(fmt.Sprintf("Server information: %v", serverInfo))
+	log.Debug("Server information: %v", serverInfo)
 }
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSingleUIImplementation guards against the UI drifting into parallel
+// copies again: the model type and handleControl must each be declared once
+// in the whole module, in internal/ui.
+func TestSingleUIImplementation(t *testing.T) {
+	found := map[string][]string{}
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != "." && (strings.HasPrefix(d.Name(), ".") || d.Name() == "vendor") {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if spec, ok := spec.(*ast.TypeSpec); ok && spec.Name.Name == "model" {
+						found["type model"] = append(found["type model"], path)
+					}
+				}
+			case *ast.FuncDecl:
+				if decl.Name.Name == "handleControl" {
+					found["handleControl"] = append(found["handleControl"], path)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"type model", "handleControl"} {
+		paths := found[name]
+		if len(paths) != 1 {
+			t.Errorf("%s is declared %d times, want once: %v", name, len(paths), paths)
+			continue
+		}
+		if filepath.Dir(paths[0]) != filepath.Join("internal", "ui") {
+			t.Errorf("%s is declared in %s, want internal/ui", name, paths[0])
+		}
+	}
+}